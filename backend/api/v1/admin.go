@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+type GrantRoleRequest struct {
+	Role models.Role `json:"role" binding:"required"`
+}
+
+func (s *Server) RegisterAdminRoutes(router *gin.Engine) {
+	admin := router.Group("/api/v1/admin")
+	admin.Use(middleware.WalletAuth(s.store), middleware.RequireRole(s.store, models.RoleAdmin))
+	{
+		admin.GET("/users/:id/roles", s.listUserRoles)
+		admin.POST("/users/:id/roles", s.grantUserRole)
+		admin.DELETE("/users/:id/roles/:role", s.revokeUserRole)
+		admin.POST("/categories", s.createCategory)
+		admin.POST("/reputation/recompute", s.recomputeReputation)
+	}
+}
+
+func (s *Server) listUserRoles(c *gin.Context) {
+	userID := strings.ToLower(c.Param("id"))
+
+	roles, err := s.roles.ListRoles(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch roles"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id": userID,
+		"roles":   roles,
+	})
+}
+
+func (s *Server) grantUserRole(c *gin.Context) {
+	userID := strings.ToLower(c.Param("id"))
+
+	var req GrantRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := s.store.Users().Get(c.Request.Context(), userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant role"})
+		return
+	}
+
+	if err := s.roles.Grant(c.Request.Context(), user.ID, req.Role, middleware.CurrentUserID(c)); err != nil {
+		if errors.Is(err, services.ErrInvalidRole) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to grant role"})
+		return
+	}
+
+	s.listUserRoles(c)
+}
+
+func (s *Server) revokeUserRole(c *gin.Context) {
+	userID := strings.ToLower(c.Param("id"))
+	role := models.Role(c.Param("role"))
+
+	if err := s.roles.Revoke(c.Request.Context(), userID, role); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRole):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrRoleNotGranted):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrLastAdmin):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke role"})
+		}
+		return
+	}
+
+	s.listUserRoles(c)
+}
+
+// recomputeReputation rebuilds every user's score, level and badges from the
+// ledger with the current level curve and badge definitions.
+func (s *Server) recomputeReputation(c *gin.Context) {
+	result, err := services.NewReputationService(s.store).Rebuild(c.Request.Context())
+	if err != nil {
+		log.Printf("Failed to recompute reputation: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute reputation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
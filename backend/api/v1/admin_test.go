@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAdminRoles(t *testing.T) {
+	ts := newTestServer(t)
+	const admin, alice = "0x00000000000000000000000000000000000000ad", "0x00000000000000000000000000000000000000a1"
+
+	adminToken := ts.login(t, admin)
+	aliceToken := ts.login(t, alice)
+	if err := ts.roles.Grant(context.Background(), admin, models.RoleAdmin, "bootstrap"); err != nil {
+		t.Fatal(err)
+	}
+
+	path := "/api/v1/admin/users/" + alice + "/roles"
+	if code := ts.do(t, http.MethodPost, path, aliceToken, gin.H{"role": models.RoleArbiter}, nil); code != http.StatusForbidden {
+		t.Fatalf("non-admin grant: status %d, want 403", code)
+	}
+
+	var granted struct {
+		Roles []models.Role `json:"roles"`
+	}
+	if code := ts.do(t, http.MethodPost, path, adminToken, gin.H{"role": models.RoleArbiter}, &granted); code != http.StatusOK {
+		t.Fatalf("grant: status %d", code)
+	}
+	if len(granted.Roles) != 2 || granted.Roles[1] != models.RoleArbiter {
+		t.Fatalf("roles = %v, want [user arbiter]", granted.Roles)
+	}
+
+	if code := ts.do(t, http.MethodPost, path, adminToken, gin.H{"role": "superuser"}, nil); code != http.StatusBadRequest {
+		t.Fatalf("invalid role: status %d, want 400", code)
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/admin/users/0xunknown/roles", adminToken, gin.H{"role": models.RoleArbiter}, nil); code != http.StatusNotFound {
+		t.Fatalf("unknown user: status %d, want 404", code)
+	}
+	if code := ts.do(t, http.MethodDelete, "/api/v1/admin/users/"+admin+"/roles/admin", adminToken, nil, nil); code != http.StatusConflict {
+		t.Fatalf("revoking last admin: status %d, want 409", code)
+	}
+	if code := ts.do(t, http.MethodDelete, path+"/arbiter", adminToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("revoke: status %d", code)
+	}
+	if code := ts.do(t, http.MethodDelete, path+"/arbiter", adminToken, nil, nil); code != http.StatusNotFound {
+		t.Fatalf("revoking twice: status %d, want 404", code)
+	}
+}
@@ -0,0 +1,259 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/pkg/sealed"
+	"github.com/gin-gonic/gin"
+)
+
+// maxSubmissionContent caps the text part of a multipart submission.
+const maxSubmissionContent = 64 << 10
+
+// multipartOverhead is allowed on top of the attachment limit for the form's
+// boundaries, headers and text fields.
+const multipartOverhead = 1 << 20
+
+// maxSubmissionEnvelopes caps the key envelopes field of a multipart
+// submission.
+const maxSubmissionEnvelopes = 64 << 10
+
+// submissionForm is a multipart submission. Plain submissions have content
+// and files, encrypted ones a ciphertext and key envelopes.
+type submissionForm struct {
+	Content       string
+	Files         []services.Attachment
+	CiphertextCID string
+	Envelopes     []sealed.Envelope
+}
+
+// Encrypted reports whether the form holds any part of an encrypted
+// submission.
+func (f *submissionForm) Encrypted() bool {
+	return f.CiphertextCID != "" || f.Envelopes != nil
+}
+
+// readSubmissionForm reads a multipart submission: a "content" text field and
+// any number of "files" parts, or for an encrypted submission a "ciphertext"
+// part and an "envelopes" JSON field. Uploads are streamed to the content
+// store as they arrive. It responds and returns false on failure.
+func (s *Server) readSubmissionForm(c *gin.Context, attachments *services.AttachmentService) (*submissionForm, bool) {
+	limits := attachments.Limits()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limits.MaxTotalSize+multipartOverhead)
+
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+		return nil, false
+	}
+
+	form := &submissionForm{}
+	remaining := limits.MaxTotalSize
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			respondAttachmentError(c, err)
+			return nil, false
+		}
+
+		switch {
+		case part.FormName() == "content":
+			data, err := io.ReadAll(io.LimitReader(part, maxSubmissionContent+1))
+			if err != nil {
+				respondAttachmentError(c, err)
+				return nil, false
+			}
+			if len(data) > maxSubmissionContent {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Content is too long"})
+				return nil, false
+			}
+			form.Content = string(data)
+		case part.FormName() == "files" && part.FileName() != "":
+			if len(form.Files) == limits.MaxFiles {
+				respondAttachmentError(c, services.ErrTooManyAttachments)
+				return nil, false
+			}
+			file, err := attachments.Upload(c.Request.Context(), part.FileName(), part, remaining)
+			if err != nil {
+				respondAttachmentError(c, err)
+				return nil, false
+			}
+			remaining -= file.Size
+			form.Files = append(form.Files, *file)
+		case part.FormName() == "envelopes":
+			data, err := io.ReadAll(io.LimitReader(part, maxSubmissionEnvelopes+1))
+			if err != nil {
+				respondAttachmentError(c, err)
+				return nil, false
+			}
+			if len(data) > maxSubmissionEnvelopes {
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Envelopes are too long"})
+				return nil, false
+			}
+			if err := json.Unmarshal(data, &form.Envelopes); err != nil || form.Envelopes == nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "envelopes must be a JSON array of key envelopes"})
+				return nil, false
+			}
+		case part.FormName() == "ciphertext":
+			if form.CiphertextCID != "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Only one ciphertext is allowed"})
+				return nil, false
+			}
+			form.CiphertextCID, err = attachments.UploadCiphertext(c.Request.Context(), part, remaining)
+			if err != nil {
+				respondAttachmentError(c, err)
+				return nil, false
+			}
+		}
+		part.Close()
+	}
+	return form, true
+}
+
+func respondAttachmentError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.Is(err, services.ErrAttachmentTooLarge), errors.As(err, &maxBytesErr):
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Attachments exceed the size limit"})
+	case errors.Is(err, services.ErrAttachmentType):
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+	case errors.Is(err, sealed.ErrInvalidCiphertext):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrTooManyAttachments):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, multipart.ErrMessageTooLarge), errors.Is(err, io.ErrUnexpectedEOF):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid multipart form"})
+	default:
+		log.Printf("Failed to store attachments: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store attachments"})
+	}
+}
+
+// loadSubmission loads the bounty and submission named in the path. It
+// responds and returns false on failure.
+func (s *Server) loadSubmission(c *gin.Context) (*models.Bounty, *models.BountySubmission, bool) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return nil, nil, false
+	}
+	submissionID, err := strconv.ParseUint(c.Param("submissionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return nil, nil, false
+	}
+
+	bounty, ok := s.loadBounty(c, id)
+	if !ok {
+		return nil, nil, false
+	}
+	submission, err := s.store.Submissions().Get(c.Request.Context(), bounty.ID, uint(submissionID))
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+		return nil, nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load submission %d: %v", submissionID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		return nil, nil, false
+	}
+	return bounty, submission, true
+}
+
+// loadSubmissionManifest loads a submission's attachment manifest for the
+// bounty creator or the submitting hunter. It responds and returns false on
+// failure.
+func (s *Server) loadSubmissionManifest(c *gin.Context, attachments *services.AttachmentService) (*services.AttachmentManifest, bool) {
+	bounty, submission, ok := s.loadSubmission(c)
+	if !ok {
+		return nil, false
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	if err := domain.GuardViewSubmission(bounty.Participants(), submission.HunterID, currentUser); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	if submission.IPFSHash == "" {
+		return &services.AttachmentManifest{Files: []services.Attachment{}}, true
+	}
+	manifest, err := attachments.Manifest(c.Request.Context(), submission.IPFSHash)
+	if err != nil {
+		log.Printf("Failed to load manifest of submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch attachments"})
+		return nil, false
+	}
+	return manifest, true
+}
+
+func (s *Server) listSubmissionAttachments(c *gin.Context) {
+	manifest, ok := s.loadSubmissionManifest(c, services.NewAttachmentService(s.content))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, manifest)
+}
+
+// downloadSubmissionAttachment streams one attachment listed in the
+// submission's manifest. Content that does not match its CID is cut off
+// before the end, so clients never receive a complete-looking corrupt file.
+func (s *Server) downloadSubmissionAttachment(c *gin.Context) {
+	attachments := services.NewAttachmentService(s.content)
+	manifest, ok := s.loadSubmissionManifest(c, attachments)
+	if !ok {
+		return
+	}
+	file, found := manifest.File(c.Param("cid"))
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+
+	content, err := attachments.Open(c.Request.Context(), file)
+	if errors.Is(err, services.ErrAttachmentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open attachment %s: %v", file.CID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch attachment"})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, file.Size, file.MimeType, content, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}),
+		"X-Content-Type-Options": "nosniff",
+	})
+	if err := c.Errors.Last(); err != nil {
+		log.Printf("Failed to send attachment %s: %v", file.CID, err)
+	}
+}
+
+// checkCanSubmit rejects a submission before any upload is accepted. The
+// checks are repeated under the bounty lock when the submission is saved.
+func checkCanSubmit(bounty *models.Bounty, hunterID string) error {
+	if err := domain.GuardSubmit(bounty.Participants(), hunterID); err != nil {
+		return err
+	}
+	if !domain.CanTransition(bounty.Status, domain.ActionSubmit) {
+		return &domain.TransitionError{From: bounty.Status, Action: domain.ActionSubmit}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	uploadCreator  = "0x00000000000000000000000000000000000000c0"
+	uploadHunter   = "0x00000000000000000000000000000000000000b0"
+	uploadOutsider = "0x00000000000000000000000000000000000000d0"
+)
+
+// uploadFile is one "files" part of a multipart submission.
+type uploadFile struct {
+	name string
+	data []byte
+}
+
+// setAttachmentLimits applies limits for the rest of the test.
+func setAttachmentLimits(t *testing.T, limits services.AttachmentLimits) {
+	t.Helper()
+	services.SetAttachmentLimits(limits)
+	t.Cleanup(func() { services.SetAttachmentLimits(services.DefaultAttachmentLimits()) })
+}
+
+// submitFiles posts a multipart submission and decodes the response into out
+// if it is not nil.
+func (ts *testServer) submitFiles(t *testing.T, path, token, content string, files []uploadFile, out interface{}) int {
+	t.Helper()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("content", content); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		part, err := form.CreateFormFile("files", file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := part.Write(file.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := form.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, path+"/submit", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	ts.router.ServeHTTP(w, req)
+
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("submit: decoding %q: %v", w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+// stored reports whether data is in the server's content store.
+func (ts *testServer) stored(t *testing.T, data []byte) bool {
+	t.Helper()
+	c, err := storage.ComputeCID(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ts.content.Stat(context.Background(), c)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+// claimedBounty creates a bounty claimed by the hunter and returns its path.
+func claimedBounty(t *testing.T, ts *testServer, creatorToken, hunterToken string) string {
+	t.Helper()
+	id := ts.createBounty(t, creatorToken, 1)
+	path := "/api/v1/bounties/" + strconv.FormatUint(uint64(id), 10)
+	if code := ts.do(t, http.MethodPost, path+"/claim", hunterToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("claim: status %d", code)
+	}
+	return path
+}
+
+func text(n int) []byte {
+	return []byte(strings.Repeat("a", n))
+}
+
+func TestSubmitAttachmentLimits(t *testing.T) {
+	limits := services.DefaultAttachmentLimits()
+	limits.MaxFileSize = 1024
+	limits.MaxTotalSize = 2048
+	limits.MaxFiles = 3
+	limits.AllowedTypes = []string{"text/plain"}
+	setAttachmentLimits(t, limits)
+
+	tests := []struct {
+		name  string
+		files []uploadFile
+		want  int
+	}{
+		{"file over the limit", []uploadFile{{"big.txt", text(1025)}}, http.StatusRequestEntityTooLarge},
+		{"files over the total", []uploadFile{{"a.txt", text(1024)}, {"b.txt", text(1000)}, {"c.txt", text(100)}}, http.StatusRequestEntityTooLarge},
+		{"too many files", []uploadFile{{"a.txt", text(1)}, {"b.txt", text(2)}, {"c.txt", text(3)}, {"d.txt", text(4)}}, http.StatusBadRequest},
+		{"type not allowed", []uploadFile{{"page.txt", []byte("<html><body>hi</body></html>")}}, http.StatusUnsupportedMediaType},
+		{"png not allowed here", []uploadFile{{"shot.png", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")}}, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newTestServer(t)
+			creatorToken := ts.login(t, uploadCreator)
+			hunterToken := ts.login(t, uploadHunter)
+			path := claimedBounty(t, ts, creatorToken, hunterToken)
+
+			if code := ts.submitFiles(t, path, hunterToken, "Fixed", tt.files, nil); code != tt.want {
+				t.Fatalf("status %d, want %d", code, tt.want)
+			}
+			// Nothing was submitted, so the hunter can still submit
+			if code := ts.do(t, http.MethodPost, path+"/submit", hunterToken, gin.H{"content": "Fixed"}, nil); code != http.StatusCreated {
+				t.Fatalf("submit after a rejected upload: status %d", code)
+			}
+		})
+	}
+}
+
+func TestSubmitAttachmentsWithinLimits(t *testing.T) {
+	limits := services.DefaultAttachmentLimits()
+	limits.MaxFileSize = 1024
+	limits.MaxTotalSize = 2048
+	limits.MaxFiles = 2
+	setAttachmentLimits(t, limits)
+
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, uploadCreator)
+	hunterToken := ts.login(t, uploadHunter)
+	path := claimedBounty(t, ts, creatorToken, hunterToken)
+
+	files := []uploadFile{{"a.txt", text(1024)}, {"../b.txt", text(1024)}}
+	var submission models.BountySubmission
+	if code := ts.submitFiles(t, path, hunterToken, "Fixed", files, &submission); code != http.StatusCreated {
+		t.Fatalf("submit: status %d", code)
+	}
+
+	var manifest services.AttachmentManifest
+	attachmentsPath := submissionPath(path, submission.ID) + "/attachments"
+	if code := ts.do(t, http.MethodGet, attachmentsPath, creatorToken, nil, &manifest); code != http.StatusOK {
+		t.Fatalf("list attachments: status %d", code)
+	}
+	if len(manifest.Files) != 2 || manifest.Files[1].Name != "b.txt" || manifest.Files[0].MimeType != "text/plain" || manifest.Files[0].Size != 1024 {
+		t.Fatalf("manifest = %+v, want both files", manifest)
+	}
+
+	if code := ts.do(t, http.MethodGet, attachmentsPath, ts.login(t, uploadOutsider), nil, nil); code != http.StatusForbidden {
+		t.Errorf("list attachments as an outsider: status %d, want 403", code)
+	}
+}
+
+func TestSubmitAttachmentsByNonParticipant(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, uploadCreator)
+	hunterToken := ts.login(t, uploadHunter)
+	path := claimedBounty(t, ts, creatorToken, hunterToken)
+
+	data := []byte("Not my bounty, but here is a file")
+	for name, token := range map[string]string{
+		"outsider": ts.login(t, uploadOutsider),
+		"creator":  creatorToken,
+	} {
+		if code := ts.submitFiles(t, path, token, "Fixed", []uploadFile{{"notes.txt", data}}, nil); code != http.StatusForbidden {
+			t.Errorf("%s: status %d, want 403", name, code)
+		}
+	}
+	// The upload is refused before any of it is stored
+	if ts.stored(t, data) {
+		t.Error("file from a non-participant was stored")
+	}
+}
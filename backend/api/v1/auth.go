@@ -0,0 +1,236 @@
+package v1
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+const nonceTTL = 10 * time.Minute
+
+type VerifySIWERequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+type LensLoginRequest struct {
+	AccessToken string `json:"access_token" binding:"required"` // Lens API access token
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+func (s *Server) RegisterAuthRoutes(router *gin.Engine) {
+	v1 := router.Group("/api/v1/auth")
+	{
+		v1.GET("/nonce", s.getNonce)
+		v1.POST("/verify", s.verifySIWE)
+		v1.POST("/lens", s.lensLogin)
+		v1.POST("/refresh", s.refreshSession)
+	}
+
+	protected := router.Group("/api/v1/auth")
+	protected.Use(middleware.WalletAuth(s.store))
+	{
+		protected.GET("/me", s.getCurrentUser)
+		protected.POST("/logout", s.logout)
+		protected.POST("/logout-all", s.logoutAll)
+	}
+}
+
+func (s *Server) getNonce(c *gin.Context) {
+	record, err := s.nonces.Issue(c.Request.Context(), nonceTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate nonce"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nonce":      record.Nonce,
+		"expires_at": record.ExpiresAt,
+	})
+}
+
+func (s *Server) verifySIWE(c *gin.Context) {
+	var req VerifySIWERequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	msg, err := auth.ParseSIWEMessage(req.Message)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	if err := msg.Validate(auth.GetSIWEConfig(), now); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := msg.VerifySignature(req.Message, req.Signature); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Consume the nonce so the signed message cannot be replayed
+	valid, err := s.nonces.Consume(c.Request.Context(), msg.Nonce, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify nonce"})
+		return
+	}
+	if !valid {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired nonce"})
+		return
+	}
+
+	// The signature proves ownership, so it is now safe to provision the user
+	address := strings.ToLower(msg.Address.Hex())
+	user, err := s.users.FindOrCreateByAddress(c.Request.Context(), address)
+	if err != nil {
+		log.Printf("Failed to provision user %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+		return
+	}
+
+	tokens, err := s.sessions.Create(c.Request.Context(), user, services.ProviderWallet, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"user":   user,
+	})
+}
+
+func (s *Server) lensLogin(c *gin.Context) {
+	var req LensLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	identity, err := auth.GetLensVerifier().Verify(c.Request.Context(), req.AccessToken)
+	if err != nil {
+		log.Printf("Lens token verification failed: %v", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": auth.ErrInvalidLensToken.Error()})
+		return
+	}
+
+	// Lens profiles are linked to the wallet user that owns them, so wallet and
+	// Lens logins resolve to the same account
+	address := strings.ToLower(identity.OwnedBy.Hex())
+	user, err := s.users.FindOrCreateByAddress(c.Request.Context(), address)
+	if err != nil {
+		log.Printf("Failed to provision user %s: %v", address, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+		return
+	}
+
+	err = s.users.LinkLensProfile(c.Request.Context(), user, identity)
+	if errors.Is(err, services.ErrLensProfileTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to link Lens profile %s to %s: %v", identity.ProfileID, user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to link Lens profile"})
+		return
+	}
+
+	tokens, err := s.sessions.Create(c.Request.Context(), user, services.ProviderLens, identity.ProfileID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tokens": tokens,
+		"user":   user,
+	})
+}
+
+func (s *Server) refreshSession(c *gin.Context) {
+	var req RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := s.sessions.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, services.ErrSessionInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to refresh session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tokens": tokens})
+}
+
+// getCurrentUser returns the signed-in user and the roles they hold, so
+// clients can show role-gated actions without knowing who holds which role.
+func (s *Server) getCurrentUser(c *gin.Context) {
+	ctx := c.Request.Context()
+	userID := middleware.CurrentUserID(c)
+
+	user, err := s.store.Users().Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load user %s: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+		return
+	}
+	roles, err := s.roles.ListRoles(ctx, user.ID)
+	if err != nil {
+		log.Printf("Failed to load roles of %s: %v", user.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load user"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user":  user,
+		"roles": roles,
+	})
+}
+
+func (s *Server) logout(c *gin.Context) {
+	claims := middleware.GetClaims(c)
+	if err := s.sessions.Revoke(c.Request.Context(), claims.UserID(), claims.SessionID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke session"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+func (s *Server) logoutAll(c *gin.Context) {
+	revoked, err := s.sessions.RevokeAll(c.Request.Context(), middleware.CurrentUserID(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke sessions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "All sessions revoked",
+		"revoked": revoked,
+	})
+}
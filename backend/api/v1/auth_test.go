@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/services"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRefreshAndLogout(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+
+	user, err := ts.users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := ts.sessions.Create(ctx, user, services.ProviderWallet, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var refreshed struct {
+		Tokens services.TokenPair `json:"tokens"`
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": tokens.RefreshToken}, &refreshed); code != http.StatusOK {
+		t.Fatalf("refresh: status %d", code)
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/refresh", "", gin.H{"refresh_token": tokens.RefreshToken}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("reused refresh token: status %d, want 401", code)
+	}
+
+	access := refreshed.Tokens.AccessToken
+	if code := ts.do(t, http.MethodGet, "/api/v1/notifications", access, nil, nil); code != http.StatusOK {
+		t.Fatalf("authenticated request: status %d", code)
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/logout", access, nil, nil); code != http.StatusOK {
+		t.Fatalf("logout: status %d", code)
+	}
+	if code := ts.do(t, http.MethodGet, "/api/v1/notifications", access, nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("request after logout: status %d, want 401", code)
+	}
+}
+
+func TestProtectedRoutesRequireToken(t *testing.T) {
+	ts := newTestServer(t)
+
+	for _, token := range []string{"", "garbage"} {
+		if code := ts.do(t, http.MethodPost, "/api/v1/bounties", token, gin.H{}, nil); code != http.StatusUnauthorized {
+			t.Errorf("token %q: status %d, want 401", token, code)
+		}
+	}
+}
+
+func TestLensLogin(t *testing.T) {
+	const owner, newOwner = "0x00000000000000000000000000000000000000A1", "0x00000000000000000000000000000000000000b0"
+	identities := map[string]*auth.LensIdentity{
+		"alice": {ProfileID: "0x01", OwnedBy: common.HexToAddress(owner)},
+		"bob":   {ProfileID: "0x01", OwnedBy: common.HexToAddress(newOwner)},
+	}
+	previous := auth.GetLensVerifier()
+	auth.SetLensVerifier(auth.LensVerifierFunc(func(ctx context.Context, accessToken string) (*auth.LensIdentity, error) {
+		if identity, ok := identities[accessToken]; ok {
+			return identity, nil
+		}
+		return nil, errors.New("Lens API unavailable")
+	}))
+	t.Cleanup(func() { auth.SetLensVerifier(previous) })
+
+	ts := newTestServer(t)
+
+	var login struct {
+		Tokens services.TokenPair `json:"tokens"`
+		User   models.User        `json:"user"`
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/lens", "", gin.H{"access_token": "alice"}, &login); code != http.StatusOK {
+		t.Fatalf("login: status %d", code)
+	}
+	if login.User.ID != strings.ToLower(owner) || login.User.LensProfileID == nil || *login.User.LensProfileID != "0x01" {
+		t.Fatalf("user = %+v", login.User)
+	}
+	claims, err := ts.sessions.Authenticate(context.Background(), login.Tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Provider != services.ProviderLens || claims.ProfileID != "0x01" {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	// Once the profile is transferred, its new owner signs in with it
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/lens", "", gin.H{"access_token": "bob"}, &login); code != http.StatusOK {
+		t.Fatalf("transferred profile: status %d", code)
+	}
+	if login.User.ID != newOwner || login.User.LensProfileID == nil || *login.User.LensProfileID != "0x01" {
+		t.Fatalf("user = %+v", login.User)
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/auth/lens", "", gin.H{"access_token": "unknown"}, nil); code != http.StatusUnauthorized {
+		t.Fatalf("verifier error: status %d, want 401", code)
+	}
+}
+
+func TestCurrentUserRoles(t *testing.T) {
+	ts := newTestServer(t)
+	const alice = "0x00000000000000000000000000000000000000a1"
+	token := ts.login(t, alice)
+
+	var me struct {
+		User  models.User   `json:"user"`
+		Roles []models.Role `json:"roles"`
+	}
+	if code := ts.do(t, http.MethodGet, "/api/v1/auth/me", token, nil, &me); code != http.StatusOK {
+		t.Fatalf("me: status %d", code)
+	}
+	if me.User.ID != alice || len(me.Roles) != 1 || me.Roles[0] != models.RoleUser {
+		t.Fatalf("me = %+v", me)
+	}
+
+	// Roles granted later show up without signing in again
+	if err := ts.roles.Grant(context.Background(), alice, models.RoleArbiter, "bootstrap"); err != nil {
+		t.Fatal(err)
+	}
+	if code := ts.do(t, http.MethodGet, "/api/v1/auth/me", token, nil, &me); code != http.StatusOK {
+		t.Fatalf("me: status %d", code)
+	}
+	if len(me.Roles) != 2 || me.Roles[1] != models.RoleArbiter {
+		t.Fatalf("roles = %v, want [user arbiter]", me.Roles)
+	}
+
+	if code := ts.do(t, http.MethodGet, "/api/v1/auth/me", "", nil, nil); code != http.StatusUnauthorized {
+		t.Fatalf("signed out: status %d, want 401", code)
+	}
+}
@@ -1,18 +1,21 @@
 package v1
 
 import (
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/domain"
 	"github.com/bountyBoard/internal/middleware"
 	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/shopspring/decimal"
-	"gorm.io/gorm"
 )
 
 type CreateBountyRequest struct {
@@ -22,114 +25,393 @@ type CreateBountyRequest struct {
 	Reward       decimal.Decimal `json:"reward" binding:"required"`
 	Deadline     time.Time       `json:"deadline" binding:"required"`
 	TxHash       string          `json:"txHash" binding:"required"` // Transaction hash from contract
+	ReopenOnReject bool          `json:"reopen_on_reject"`
+	Category     string          `json:"category"` // Category slug
+	Tags         []string        `json:"tags"`
+	Skills       []string        `json:"skills"` // Skills a hunter needs
+	// MetadataCID names a document pinned with POST /bounties/metadata before
+	// the bounty was created on-chain. If empty the document is built from
+	// this request and pinned.
+	MetadataCID string `json:"metadata_cid"`
+	// CommentPolicy says who may comment; participants if empty.
+	CommentPolicy domain.CommentPolicy `json:"comment_policy"`
+}
+
+// bountyListResponse is a page of bounties with facet counts for building
+// filters.
+type bountyListResponse struct {
+	*repository.Page[models.Bounty]
+	Facets *repository.BountyFacets `json:"facets"`
 }
 
 type SubmitWorkRequest struct {
 	Content string `json:"content" binding:"required"`
 }
 
-func RegisterBountyRoutes(router *gin.Engine) {
+func (s *Server) RegisterBountyRoutes(router *gin.Engine) {
 	// Public routes
 	v1 := router.Group("/api/v1")
 	{
-		v1.GET("/bounties", listBounties)
-		v1.GET("/bounties/:id", getBounty)
-		v1.GET("/bounties/:id/comments", getBountyComments)
+		v1.GET("/bounties", s.listBounties)
+		v1.GET("/bounties/search", s.searchBounties)
+		v1.GET("/bounties/metadata/schema", s.getBountyMetadataSchema)
+		v1.GET("/bounties/:id", s.getBounty)
+		v1.GET("/bounties/:id/metadata", s.getBountyMetadata)
+		v1.GET("/bounties/:id/comments", s.getBountyComments)
+		v1.GET("/bounties/:id/comments/:commentId/revisions", s.getCommentRevisions)
+		v1.GET("/bounties/:id/history", s.getBountyHistory)
+		v1.GET("/categories", s.listCategories)
 	}
 
 	// Protected routes (require auth)
 	protected := router.Group("/api/v1")
-	protected.Use(middleware.WalletAuth())
+	protected.Use(middleware.WalletAuth(s.store))
+	{
+		protected.POST("/bounties", s.createBounty)
+		protected.POST("/bounties/metadata", s.prepareBountyMetadata)
+		protected.GET("/bounties/:id/submissions", s.getBountySubmissions)  
+		protected.POST("/bounties/:id/claim", s.claimBounty)
+		protected.POST("/bounties/:id/submit", s.submitBounty)
+		protected.POST("/bounties/:id/dispute", s.raiseDispute)
+		protected.POST("/bounties/:id/complete", s.completeBounty)
+		protected.POST("/bounties/:id/comments", s.addBountyComment)
+		protected.PUT("/bounties/:id/comments/:commentId", s.editBountyComment)
+		protected.DELETE("/bounties/:id/comments/:commentId", s.deleteBountyComment)
+		protected.PUT("/bounties/:id/comment-policy", s.setCommentPolicy)
+		protected.POST("/bounties/:id/submissions/:submissionId/accept", s.acceptSubmission)
+		protected.POST("/bounties/:id/submissions/:submissionId/reject", s.rejectSubmission)
+		protected.POST("/bounties/:id/submissions/:submissionId/request-changes", s.requestSubmissionChanges)
+		protected.GET("/bounties/:id/submissions/:submissionId/attachments", s.listSubmissionAttachments)
+		protected.GET("/bounties/:id/submissions/:submissionId/attachments/:cid", s.downloadSubmissionAttachment)
+		protected.GET("/bounties/:id/submissions/:submissionId/sealed", s.getSealedSubmission)
+		protected.GET("/bounties/:id/submissions/:submissionId/sealed/ciphertext", s.downloadSealedSubmission)
+		protected.POST("/bounties/:id/submissions/:submissionId/envelopes", s.reshareSubmission)
+	}
+
+	// Dispute resolution is reserved for arbiters and admins
+	arbiters := router.Group("/api/v1")
+	arbiters.Use(middleware.WalletAuth(s.store), middleware.RequireRole(s.store, models.RoleAdmin, models.RoleArbiter))
 	{
-		protected.POST("/bounties", createBounty)
-		protected.GET("/bounties/:id/submissions", getBountySubmissions)  
-		protected.POST("/bounties/:id/claim", claimBounty)
-		protected.POST("/bounties/:id/submit", submitBounty)
-		protected.POST("/bounties/:id/dispute", raiseDispute)
-		protected.POST("/bounties/:id/complete", completeBounty)
-		protected.POST("/bounties/:id/comments", addBountyComment)
-		protected.POST("/bounties/:id/resolve", resolveDispute)
+		arbiters.POST("/bounties/:id/resolve", s.resolveDispute)
 	}
 }
 
-func createBounty(c *gin.Context) {
+func (s *Server) createBounty(c *gin.Context) {
 	var req CreateBountyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
+	tagNames, err := domain.NormalizeLabels(req.Tags, domain.MaxTagsPerBounty)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tags: " + err.Error()})
+		return
+	}
+	skillNames, err := domain.NormalizeLabels(req.Skills, domain.MaxSkillsPerBounty)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "skills: " + err.Error()})
+		return
+	}
+
+	if req.CommentPolicy == "" {
+		req.CommentPolicy = domain.CommentsParticipants
+	}
+	if !req.CommentPolicy.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment_policy must be participants or everyone"})
+		return
+	}
+
+	creatorID := strings.ToLower(middleware.CurrentUserID(c))
+	metadataCID, ok := s.resolveBountyMetadata(c, &services.BountyMetadata{
+		Version:     services.BountyMetadataVersion,
+		Title:       req.Title,
+		Description: req.Description,
+		Reward:      req.Reward.String(),
+		Deadline:    req.Deadline.UTC(),
+		Creator:     creatorID,
+		Category:    strings.ToLower(req.Category),
+		Tags:        tagNames,
+		Skills:      skillNames,
+	}, req.MetadataCID)
+	if !ok {
+		return
+	}
+
+	// Bounties stay pending until their transaction is verified on-chain
+	status := domain.StatusOpen
+	if s.verifier != nil {
+		status = domain.StatusPendingConfirmation
+	}
+
 	// Create bounty in database
 	bounty := &models.Bounty{
 		BlockchainID: req.BlockchainID, // Use the contract's bounty ID
 		Title:        req.Title,
 		Description:  req.Description,
 		Reward:       req.Reward,
-		CreatorID:    strings.ToLower(c.GetString("user_id")), // Convert to lowercase
-		Status:       "open",
+		CreatorID:    creatorID,
+		Status:       status,
 		Deadline:     req.Deadline,
 		TxHash:       req.TxHash, // Store the transaction hash
-		IPFSHash:     "",
+		IPFSHash:     metadataCID, // Canonical metadata document
+		ReopenOnReject: req.ReopenOnReject,
+		CommentPolicy: req.CommentPolicy,
+	}
+
+	ctx := c.Request.Context()
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if req.Category != "" {
+			category, err := tx.Taxonomy().GetCategory(ctx, strings.ToLower(req.Category))
+			if err != nil {
+				return err
+			}
+			bounty.CategoryID, bounty.Category = &category.ID, category
+		}
+
+		var err error
+		if bounty.Tags, err = tx.Taxonomy().EnsureTags(ctx, tagNames); err != nil {
+			return err
+		}
+		if bounty.Skills, err = tx.Taxonomy().EnsureSkills(ctx, skillNames); err != nil {
+			return err
+		}
+
+		// Use the contract's bounty ID
+		return tx.Bounties().Create(ctx, bounty)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrNotFound):
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Unknown category"})
+		case errors.Is(err, repository.ErrDuplicate):
+			c.JSON(http.StatusConflict, gin.H{"error": "Bounty already exists"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bounty"})
+		}
+		return
 	}
 
-	// Use the contract's bounty ID
-	if err := database.DB.Create(bounty).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create bounty"})
+	if status == domain.StatusPendingConfirmation {
+		s.verifier.Enqueue(bounty.ID)
+		c.JSON(http.StatusAccepted, bounty)
 		return
 	}
 
 	c.JSON(http.StatusCreated, bounty)
 }
 
-func listBounties(c *gin.Context) {
-	var bounties []models.Bounty
-	query := database.DB
+func (s *Server) listBounties(c *gin.Context) {
+	// Newest first unless the client asks otherwise
+	page, ok := parsePage(c, true, repository.SortCreatedAt, repository.SortReward, repository.SortDeadline)
+	if !ok {
+		return
+	}
+
+	filter, ok := parseBountyFilter(c)
+	if !ok {
+		return
+	}
+
+	bounties, err := s.store.Bounties().List(c.Request.Context(), filter, page)
+	if isPageError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to fetch bounties: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bounties"})
+		return
+	}
+
+	facets, err := s.store.Bounties().Facets(c.Request.Context(), filter)
+	if err != nil {
+		log.Printf("Failed to count bounty facets: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bounties"})
+		return
+	}
+
+	log.Printf("Found %d bounties", bounties.Total)
+	c.JSON(http.StatusOK, bountyListResponse{Page: bounties, Facets: facets})
+}
+
+// searchBounties runs a full-text search over bounty titles and descriptions,
+// combined with the same filters as listBounties. Results are ordered by
+// relevance.
+func (s *Server) searchBounties(c *gin.Context) {
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+
+	page, ok := parsePage(c, true, repository.SortRelevance)
+	if !ok {
+		return
+	}
+	page.Desc = true
+
+	filter, ok := parseBountyFilter(c)
+	if !ok {
+		return
+	}
+
+	results, err := s.store.Bounties().Search(c.Request.Context(), q, filter, page)
+	if isPageError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to search bounties: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search bounties"})
+		return
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+// parseBountyFilter reads the creator, hunter, status, category, tags,
+// tag_match, reward range and date window query parameters, responding with
+// 400 if any is invalid. Tags may be comma-separated or repeated.
+func parseBountyFilter(c *gin.Context) (repository.BountyFilter, bool) {
+	var filter repository.BountyFilter
 
 	// Filter by creator if specified
 	if creator := c.Query("creator"); creator != "" {
 		creator = strings.ToLower(creator) // Convert to lowercase
 		log.Printf("Filtering bounties by creator: %s", creator)
-		// Debug: Check if any bounties exist with this creator
-		var count int64
-		database.DB.Model(&models.Bounty{}).Where("creator_id = ?", creator).Count(&count)
-		log.Printf("Found bounties count for creator: %d", count)
-		query = query.Where("creator_id = ?", creator)
+		filter.CreatorID = creator
 	}
 
 	// Filter by hunter if specified
 	if hunter := c.Query("hunter"); hunter != "" {
 		hunter = strings.ToLower(hunter) // Convert to lowercase
 		log.Printf("Filtering bounties by hunter: %s", hunter)
-		query = query.Where("hunter_id = ?", hunter)
+		filter.HunterID = hunter
 	}
 
 	// Filter by status if specified
 	if status := c.Query("status"); status != "" {
 		log.Printf("Filtering bounties by status: %s", status)
-		query = query.Where("status = ?", status)
+		parsed, err := domain.ParseBountyStatus(status)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return filter, false
+		}
+		filter.Status = parsed
+	}
+
+	if category := c.Query("category"); category != "" {
+		filter.Category = strings.ToLower(category)
+	}
+
+	var tags []string
+	for _, v := range c.QueryArray("tags") {
+		for _, tag := range strings.Split(v, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	if len(tags) > 0 {
+		normalized, err := domain.NormalizeLabels(tags, domain.MaxTagsPerBounty)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "tags: " + err.Error()})
+			return filter, false
+		}
+		filter.Tags = normalized
+	}
+
+	switch c.Query("tag_match") {
+	case "", "any":
+	case "all":
+		filter.MatchAllTags = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tag_match must be any or all"})
+		return filter, false
+	}
+
+	var err error
+	if filter.MinReward, err = parseRewardQuery(c, "min_reward"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.MaxReward, err = parseRewardQuery(c, "max_reward"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.MinReward != nil && filter.MaxReward != nil && filter.MinReward.GreaterThan(*filter.MaxReward) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_reward must not exceed max_reward"})
+		return filter, false
+	}
+
+	if filter.DeadlineAfter, err = parseTimeQuery(c, "deadline_after"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.DeadlineBefore, err = parseTimeQuery(c, "deadline_before"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
+	}
+	if filter.DeadlineAfter != nil && filter.DeadlineBefore != nil && !filter.DeadlineAfter.Before(*filter.DeadlineBefore) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "deadline_after must be before deadline_before"})
+		return filter, false
+	}
+	if filter.CreatedSince, err = parseTimeQuery(c, "created_since"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return filter, false
 	}
 
-	// Debug: Print the final SQL query
-	stmt := query.Session(&gorm.Session{DryRun: true}).Find(&models.Bounty{})
-	sql := stmt.Statement.SQL.String()
-	log.Printf("Final SQL query: %s", sql)
+	return filter, true
+}
 
-	if err := query.Find(&bounties).Error; err != nil {
-		log.Printf("Failed to fetch bounties: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bounties"})
-		return
+// rewardScale is the number of decimal places stored for rewards.
+const rewardScale = 18
+
+// parseRewardQuery parses a non-negative reward amount. Amounts are kept as
+// decimals so comparisons are exact at the stored 18 decimal places.
+func parseRewardQuery(c *gin.Context, name string) (*decimal.Decimal, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
 	}
+	amount, err := decimal.NewFromString(v)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be a decimal number", name)
+	}
+	if amount.IsNegative() {
+		return nil, fmt.Errorf("%s must not be negative", name)
+	}
+	if !amount.Equal(amount.Truncate(rewardScale)) {
+		return nil, fmt.Errorf("%s has more than %d decimal places", name, rewardScale)
+	}
+	return &amount, nil
+}
 
-	log.Printf("Found %d bounties", len(bounties))
-	c.JSON(http.StatusOK, bounties)
+// parseTimeQuery parses an RFC 3339 timestamp or a YYYY-MM-DD date, which
+// means midnight UTC.
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, v); err != nil {
+			return nil, fmt.Errorf("%s must be an RFC 3339 timestamp or YYYY-MM-DD date", name)
+		}
+	}
+	return &t, nil
 }
 
-func getBounty(c *gin.Context) {
-	id := c.Param("id")
+func (s *Server) getBounty(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
 
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
+	bounty, ok := s.loadBounty(c, id)
+	if !ok {
 		return
 	}
 
@@ -151,11 +433,14 @@ func ensureAddressFormat(address string) string {
 	return address
 }
 
-func getBountySubmissions(c *gin.Context) {
-	id := c.Param("id")
+func (s *Server) getBountySubmissions(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
 
 	// Check if user is authenticated
-	currentUser := c.GetString("user_id")
+	currentUser := middleware.CurrentUserID(c)
 	log.Printf("Current user from context: %s", currentUser)
 	
 	if currentUser == "" {
@@ -163,9 +448,8 @@ func getBountySubmissions(c *gin.Context) {
 		return
 	}
 
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
+	bounty, ok := s.loadBounty(c, id)
+	if !ok {
 		return
 	}
 
@@ -191,126 +475,172 @@ func getBountySubmissions(c *gin.Context) {
 
 	log.Printf("Access granted - fetching submissions")
 
-	var submissions []models.BountySubmission
-	if err := database.DB.Where("bounty_id = ?", id).Find(&submissions).Error; err != nil {
+	page, ok := parsePage(c, false, repository.SortCreatedAt)
+	if !ok {
+		return
+	}
+
+	submissions, err := s.store.Submissions().ListByBounty(c.Request.Context(), id, page)
+	if isPageError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
 		log.Printf("Error fetching submissions: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submissions"})
 		return
 	}
 
 	// Convert all hunter IDs to lowercase for consistency
-	for i := range submissions {
-		submissions[i].HunterID = strings.ToLower(submissions[i].HunterID)
+	for i := range submissions.Items {
+		submissions.Items[i].HunterID = strings.ToLower(submissions.Items[i].HunterID)
 	}
 
-	log.Printf("Found %d submissions", len(submissions))
+	log.Printf("Found %d submissions", submissions.Total)
 	c.JSON(http.StatusOK, submissions)
 }
 
-func getBountyComments(c *gin.Context) {
-	bountyID := c.Param("id")
-	var comments []models.BountyComment
-	
-	if err := database.DB.Where("bounty_id = ?", bountyID).Order("created_at desc").Find(&comments).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+func (s *Server) getBountyHistory(c *gin.Context) {
+	bountyID, ok := parseBountyID(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, comments)
-}
-
-func claimBounty(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	history, err := s.store.Bounties().History(c.Request.Context(), bountyID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bounty ID"})
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status history"})
 		return
 	}
 
-	hunterID := c.GetString("user_id") // Set from auth middleware
+	c.JSON(http.StatusOK, history)
+}
 
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
+func (s *Server) claimBounty(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
 		return
 	}
 
-	if bounty.Status != "open" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Bounty is not available for claiming"})
-		return
-	}
+	hunterID := middleware.CurrentUserID(c) // Set from auth middleware
 
-	// Update bounty status and hunter
-	bounty.Status = "claimed"
-	bounty.HunterID = &hunterID
+	bounty, err := services.WithLockedBounty(c.Request.Context(), s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if err := domain.GuardClaim(bounty.Participants(), hunterID); err != nil {
+			return err
+		}
 
-	if err := database.DB.Save(&bounty).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim bounty"})
+		// Update bounty status and hunter
+		bounty.HunterID = &hunterID
+		return tx.Bounties().Transition(c.Request.Context(), bounty, domain.ActionClaim, hunterID)
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to claim bounty")
 		return
 	}
 
 	c.JSON(http.StatusOK, bounty)
 }
 
-func submitBounty(c *gin.Context) {
-	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bounty ID"})
-		return
-	}
-
-	var req SubmitWorkRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	hunterID := c.GetString("user_id")
-
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
-		return
-	}
-
-	if bounty.Status != "claimed" || *bounty.HunterID != hunterID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot submit work for this bounty"})
-		return
+// submitBounty records the hunter's work. It accepts a JSON body with the
+// content, or a multipart form with a "content" field and "files" parts whose
+// manifest CID is stored on the submission.
+func (s *Server) submitBounty(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+
+	hunterID := middleware.CurrentUserID(c)
+
+	var content, manifestCID, ciphertextCID string
+	var envelopes []models.SubmissionKeyEnvelope
+	if c.ContentType() == "multipart/form-data" {
+		bounty, ok := s.loadBounty(c, id)
+		if !ok {
+			return
+		}
+		if err := checkCanSubmit(bounty, hunterID); err != nil {
+			respondLifecycleError(c, err, "Failed to save submission")
+			return
+		}
+
+		attachments := services.NewAttachmentService(s.content)
+		form, ok := s.readSubmissionForm(c, attachments)
+		if !ok {
+			return
+		}
+		if form.Encrypted() {
+			if envelopes, ok = s.checkSealedForm(c, bounty, hunterID, form); !ok {
+				return
+			}
+			ciphertextCID = form.CiphertextCID
+		} else {
+			content = form.Content
+			if strings.TrimSpace(content) == "" && len(form.Files) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Content or at least one file is required"})
+				return
+			}
+			if len(form.Files) > 0 {
+				var err error
+				if manifestCID, err = attachments.SaveManifest(c.Request.Context(), form.Files); err != nil {
+					respondAttachmentError(c, err)
+					return
+				}
+			}
+		}
+	} else {
+		var req SubmitWorkRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		content = req.Content
 	}
 
-	// Create submission without IPFS for now
 	submission := &models.BountySubmission{
-		BountyID: uint(id),
-		HunterID: hunterID,
-		Content:  req.Content,
-		Status:   "pending",
-	}
-
-	if err := database.DB.Create(submission).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save submission"})
+		BountyID:      id,
+		HunterID:      hunterID,
+		Content:       content,
+		IPFSHash:      manifestCID,
+		Encrypted:     ciphertextCID != "",
+		CiphertextCID: ciphertextCID,
+		Status:        domain.SubmissionPending,
+	}
+
+	_, err := services.WithLockedBounty(c.Request.Context(), s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if err := domain.GuardSubmit(bounty.Participants(), hunterID); err != nil {
+			return err
+		}
+		if err := tx.Bounties().Transition(c.Request.Context(), bounty, domain.ActionSubmit, hunterID); err != nil {
+			return err
+		}
+		if err := tx.Submissions().Create(c.Request.Context(), submission); err != nil {
+			return err
+		}
+		for i := range envelopes {
+			envelopes[i].SubmissionID = submission.ID
+			if err := tx.Submissions().AddEnvelope(c.Request.Context(), &envelopes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to save submission")
 		return
 	}
 
 	c.JSON(http.StatusCreated, submission)
 }
 
-func addBountyComment(c *gin.Context) {
-	bountyID := c.Param("id")
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, bountyID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
-		return
-	}
-
-	// Only allow creator and hunter to comment
-	currentUser := strings.ToLower(c.GetString("user_id"))
-	if currentUser != strings.ToLower(bounty.CreatorID) &&
-		(bounty.HunterID == nil || strings.ToLower(*bounty.HunterID) != currentUser) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only the bounty creator or hunter can comment"})
+func (s *Server) raiseDispute(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
 		return
 	}
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
 
 	var input struct {
-		Content string `json:"content" binding:"required"`
+		Reason string `json:"reason" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -318,145 +648,141 @@ func addBountyComment(c *gin.Context) {
 		return
 	}
 
-	comment := models.BountyComment{
-		BountyID: bounty.ID,
-		UserID:   currentUser,
-		Content:  input.Content,
-	}
+	bounty, err := services.WithLockedBounty(c.Request.Context(), s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		// Only creator can raise dispute
+		if err := domain.GuardDispute(bounty.Participants(), currentUser); err != nil {
+			return err
+		}
 
-	if err := database.DB.Create(&comment).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		// Update bounty status and store dispute reason
+		bounty.DisputeReason = &input.Reason
+		return tx.Bounties().Transition(c.Request.Context(), bounty, domain.ActionDispute, currentUser)
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update bounty status")
 		return
 	}
 
-	c.JSON(http.StatusCreated, comment)
+	c.JSON(http.StatusOK, bounty)
 }
 
-func raiseDispute(c *gin.Context) {
-	id := c.Param("id")
-	currentUser := strings.ToLower(c.GetString("user_id"))
-
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
+func (s *Server) completeBounty(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
 		return
 	}
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
 
-	// Only creator can raise dispute
-	if currentUser != strings.ToLower(bounty.CreatorID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only bounty creator can raise dispute"})
+	bounty, err := services.WithLockedBounty(c.Request.Context(), s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		// Only creator can complete bounties
+		if err := domain.GuardComplete(bounty.Participants(), currentUser); err != nil {
+			return err
+		}
+		if err := tx.Bounties().Transition(c.Request.Context(), bounty, domain.ActionComplete, currentUser); err != nil {
+			return err
+		}
+		return services.NewReputationService(tx).AwardCompletion(c.Request.Context(), bounty, currentUser)
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update bounty status")
 		return
 	}
 
-	// Can only dispute claimed bounties
-	if bounty.Status != "claimed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Can only dispute claimed bounties"})
+	c.JSON(http.StatusOK, bounty)
+}
+
+func (s *Server) resolveDispute(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
 		return
 	}
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
 
 	var input struct {
-		Reason string `json:"reason" binding:"required"`
+		Winner     string `json:"winner" binding:"required"`
+		Resolution string `json:"resolution" binding:"required"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	winner := strings.ToLower(input.Winner)
 
-	// Update bounty status and store dispute reason
-	bounty.Status = "disputed"
-	bounty.DisputeReason = &input.Reason
-
-	if err := database.DB.Save(&bounty).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bounty status"})
+	bounty, err := services.WithLockedBounty(c.Request.Context(), s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		// Verify winner is either creator or hunter
+		if err := domain.GuardResolve(bounty.Participants(), winner); err != nil {
+			return err
+		}
+
+		// Update bounty with resolution info
+		now := time.Now()
+		bounty.DisputeWinner = &winner
+		bounty.DisputeResolution = &input.Resolution
+		bounty.ResolvedAt = &now
+		if err := tx.Bounties().Transition(c.Request.Context(), bounty, domain.ActionResolve, currentUser); err != nil {
+			return err
+		}
+		return services.NewReputationService(tx).AwardDisputeResolution(c.Request.Context(), bounty, currentUser)
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to update bounty status")
 		return
 	}
 
 	c.JSON(http.StatusOK, bounty)
 }
 
-func completeBounty(c *gin.Context) {
-	id := c.Param("id")
-	currentUser := strings.ToLower(c.GetString("user_id"))
-
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
-		return
-	}
-
-	// Only creator can complete bounties
-	if currentUser != strings.ToLower(bounty.CreatorID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only the bounty creator can complete bounties"})
-		return
-	}
-
-	// Can only complete claimed bounties
-	if bounty.Status != "claimed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Can only complete claimed bounties"})
-		return
-	}
-
-	// Update bounty status to completed
-	bounty.Status = "completed"
-	if err := database.DB.Save(&bounty).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bounty status"})
-		return
+// parseBountyID reads the :id path parameter, responding with 400 if it is
+// not a valid bounty ID.
+func parseBountyID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bounty ID"})
+		return 0, false
 	}
-
-	c.JSON(http.StatusOK, bounty)
+	return uint(id), true
 }
 
-func resolveDispute(c *gin.Context) {
-	id := c.Param("id")
-	currentUser := strings.ToLower(c.GetString("user_id"))
-
-	var bounty models.Bounty
-	if err := database.DB.First(&bounty, id).Error; err != nil {
+// loadBounty fetches a bounty, responding with 404 if it does not exist.
+func (s *Server) loadBounty(c *gin.Context, id uint) (*models.Bounty, bool) {
+	bounty, err := s.store.Bounties().Get(c.Request.Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
-		return
+		return nil, false
 	}
-
-	// Only admin can resolve disputes
-	if currentUser != strings.ToLower("0x15b5BDf7a5e0305B9a4bE413383C9b1500C8FCF2") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only admin can resolve disputes"})
-		return
-	}
-
-	// Can only resolve disputed bounties
-	if bounty.Status != "disputed" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Can only resolve disputed bounties"})
-		return
-	}
-
-	var input struct {
-		Winner string `json:"winner" binding:"required"`
-		Resolution string `json:"resolution" binding:"required"`
+	if err != nil {
+		log.Printf("Failed to load bounty %d: %v", id, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch bounty"})
+		return nil, false
 	}
+	return bounty, true
+}
 
-	if err := c.ShouldBindJSON(&input); err != nil {
+// respondLifecycleError maps domain errors to uniform responses: invalid
+// status transitions, including the loser of a concurrent update, are 409
+// Conflict and guard failures are 403 Forbidden.
+func respondLifecycleError(c *gin.Context, err error, fallback string) {
+	var transitionErr *domain.TransitionError
+	switch {
+	case errors.As(err, &transitionErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  transitionErr.Error(),
+			"status": transitionErr.From,
+			"action": transitionErr.Action,
+		})
+	case errors.Is(err, domain.ErrInvalidTransition), errors.Is(err, domain.ErrStaleSubmission):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrBountyNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty not found"})
+	case errors.Is(err, errSubmissionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission not found"})
+	case errors.Is(err, domain.ErrNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidWinner):
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	// Verify winner is either creator or hunter
-	winner := strings.ToLower(input.Winner)
-	if winner != strings.ToLower(bounty.CreatorID) && (bounty.HunterID == nil || winner != strings.ToLower(*bounty.HunterID)) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Winner must be creator or hunter"})
-		return
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
 	}
-
-	// Update bounty with resolution info
-	now := time.Now()
-	bounty.Status = "completed"
-	bounty.DisputeWinner = &winner
-	bounty.DisputeResolution = &input.Resolution
-	bounty.ResolvedAt = &now
-
-	if err := database.DB.Save(&bounty).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update bounty status"})
-		return
-	}
-
-	c.JSON(http.StatusOK, bounty)
 }
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/bountyBoard/internal/database/dbtest"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+func TestCreateAndClaimBounty(t *testing.T) {
+	ts := newTestServer(t)
+	const creator, hunter = "0x00000000000000000000000000000000000000c0", "0x00000000000000000000000000000000000000b0"
+
+	creatorToken := ts.login(t, creator)
+	hunterToken := ts.login(t, hunter)
+	id := ts.createBounty(t, creatorToken, 1)
+	path := "/api/v1/bounties/" + strconv.FormatUint(uint64(id), 10)
+
+	var bounty models.Bounty
+	if code := ts.do(t, http.MethodGet, path, "", nil, &bounty); code != http.StatusOK {
+		t.Fatalf("get bounty: status %d", code)
+	}
+	if bounty.CreatorID != creator || bounty.Status != domain.StatusOpen || bounty.IPFSHash == "" {
+		t.Fatalf("bounty = %+v, want open bounty by %s with pinned metadata", bounty, creator)
+	}
+
+	if code := ts.do(t, http.MethodPost, path+"/claim", creatorToken, nil, nil); code != http.StatusForbidden {
+		t.Fatalf("creator claim: status %d, want 403", code)
+	}
+	if code := ts.do(t, http.MethodPost, path+"/claim", hunterToken, nil, &bounty); code != http.StatusOK {
+		t.Fatalf("claim: status %d", code)
+	}
+	if bounty.Status != domain.StatusClaimed || bounty.HunterID == nil || *bounty.HunterID != hunter {
+		t.Fatalf("claimed bounty = %+v", bounty)
+	}
+	if code := ts.do(t, http.MethodPost, path+"/claim", hunterToken, nil, nil); code != http.StatusConflict {
+		t.Fatalf("second claim: status %d, want 409", code)
+	}
+
+	if code := ts.do(t, http.MethodPost, "/api/v1/bounties/999/claim", hunterToken, nil, nil); code != http.StatusNotFound {
+		t.Fatalf("unknown bounty: status %d, want 404", code)
+	}
+}
+
+func TestConcurrentClaimsHaveOneWinner(t *testing.T) {
+	testConcurrentClaims(t, newTestServer(t))
+}
+
+// The memory store serialises every transaction, so only Postgres shows that
+// the claim really locks the bounty row.
+func TestConcurrentClaimsHaveOneWinnerPostgres(t *testing.T) {
+	testConcurrentClaims(t, newTestServerWith(t, repository.NewGormStore(dbtest.OpenPostgresMigrated(t))))
+}
+
+func testConcurrentClaims(t *testing.T, ts *testServer) {
+	const hunters = 8
+
+	id := ts.createBounty(t, ts.login(t, "0x00000000000000000000000000000000000000c0"), 1)
+	path := "/api/v1/bounties/" + strconv.FormatUint(uint64(id), 10) + "/claim"
+
+	tokens := make([]string, hunters)
+	for i := range tokens {
+		tokens[i] = ts.login(t, fmt.Sprintf("0x%040x", 0xb0+i))
+	}
+
+	codes := make([]int, hunters)
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i, token := range tokens {
+		wg.Add(1)
+		go func(i int, token string) {
+			defer wg.Done()
+			<-start
+			codes[i] = ts.do(t, http.MethodPost, path, token, nil, nil)
+		}(i, token)
+	}
+	close(start)
+	wg.Wait()
+
+	won := 0
+	for i, code := range codes {
+		switch code {
+		case http.StatusOK:
+			won++
+		case http.StatusConflict:
+		default:
+			t.Errorf("hunter %d: status %d, want 200 or 409", i, code)
+		}
+	}
+	if won != 1 {
+		t.Fatalf("%d claims succeeded, want 1 (statuses %v)", won, codes)
+	}
+
+	history, err := ts.store.Bounties().History(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimed := 0
+	for _, entry := range history {
+		if entry.ToStatus == domain.StatusClaimed {
+			claimed++
+		}
+	}
+	if claimed != 1 {
+		t.Fatalf("history has %d claimed entries, want 1: %+v", claimed, history)
+	}
+}
@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+type CreateCategoryRequest struct {
+	Slug        string `json:"slug" binding:"required"`
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+func (s *Server) listCategories(c *gin.Context) {
+	categories, err := s.store.Taxonomy().ListCategories(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch categories"})
+		return
+	}
+
+	c.JSON(http.StatusOK, categories)
+}
+
+// createCategory adds a category to the curated list. Slugs follow the same
+// rules as tags.
+func (s *Server) createCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	slug, err := domain.NormalizeLabel(req.Slug)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	category := &models.Category{
+		Slug:        slug,
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+	}
+	if err := s.store.Taxonomy().CreateCategory(c.Request.Context(), category); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Category already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create category"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, category)
+}
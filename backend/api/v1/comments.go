@@ -0,0 +1,467 @@
+package v1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// maxCommentLength caps the markdown of a comment, in characters.
+const maxCommentLength = 10000
+
+var errCommentNotFound = errors.New("comment not found")
+
+type commentRequest struct {
+	Content  string `json:"content" binding:"required"`
+	ParentID *uint  `json:"parent_id"` // Comment to reply to
+}
+
+type commentPolicyRequest struct {
+	Policy domain.CommentPolicy `json:"policy" binding:"required"`
+}
+
+// getBountyComments lists a bounty's comments. parent_id lists the replies to
+// one comment and top_level=true only comments that are not replies. Deleted
+// comments are listed without their content, so threads stay intact.
+func (s *Server) getBountyComments(c *gin.Context) {
+	bountyID, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+
+	var filter repository.CommentFilter
+	if v := c.Query("parent_id"); v != "" {
+		parentID, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parent_id"})
+			return
+		}
+		id := uint(parentID)
+		filter.ParentID = &id
+	}
+	filter.TopLevel = c.Query("top_level") == "true"
+	if filter.TopLevel && filter.ParentID != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use either parent_id or top_level, not both"})
+		return
+	}
+
+	// Newest first unless the client asks otherwise
+	page, ok := parsePage(c, true, repository.SortCreatedAt)
+	if !ok {
+		return
+	}
+
+	comments, err := s.store.Comments().ListByBounty(c.Request.Context(), bountyID, filter, page)
+	if isPageError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch comments"})
+		return
+	}
+
+	for i := range comments.Items {
+		presentComment(&comments.Items[i])
+	}
+	c.JSON(http.StatusOK, comments)
+}
+
+func (s *Server) addBountyComment(c *gin.Context) {
+	bountyID, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+
+	bounty, ok := s.loadBounty(c, bountyID)
+	if !ok {
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	if err := domain.GuardComment(bounty.Participants(), bounty.CommentPolicy, currentUser); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	var input commentRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	html, ok := renderComment(c, input.Content)
+	if !ok {
+		return
+	}
+
+	comment := models.BountyComment{
+		BountyID:    bounty.ID,
+		ParentID:    input.ParentID,
+		UserID:      currentUser,
+		Content:     input.Content,
+		ContentHTML: html,
+	}
+
+	ctx := c.Request.Context()
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		if comment.ParentID != nil {
+			parent, err := tx.Comments().Get(ctx, bounty.ID, *comment.ParentID)
+			if errors.Is(err, repository.ErrNotFound) {
+				return errCommentNotFound
+			}
+			if err != nil {
+				return err
+			}
+			if parent.Deleted() {
+				return fmt.Errorf("%w: cannot reply to a deleted comment", domain.ErrNotAllowed)
+			}
+		}
+		if err := tx.Comments().Create(ctx, &comment); err != nil {
+			return err
+		}
+		return notifyMentions(ctx, tx, bounty, &comment, domain.ExtractMentions(comment.Content), domain.Mentions{})
+	})
+	switch {
+	case errors.Is(err, errCommentNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parent comment not found"})
+		return
+	case errors.Is(err, domain.ErrNotAllowed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("Failed to create comment on bounty %d: %v", bounty.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create comment"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// editBountyComment replaces the content of the caller's comment. The
+// previous content is kept as a revision, and only users who were not
+// mentioned before are notified.
+func (s *Server) editBountyComment(c *gin.Context) {
+	bountyID, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Content string `json:"content" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	html, ok := renderComment(c, input.Content)
+	if !ok {
+		return
+	}
+
+	bounty, ok := s.loadBounty(c, bountyID)
+	if !ok {
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	ctx := c.Request.Context()
+	var comment *models.BountyComment
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		comment, err = lockComment(ctx, tx, bounty.ID, commentID)
+		if err != nil {
+			return err
+		}
+		if err := domain.GuardEditComment(comment.UserID, currentUser); err != nil {
+			return err
+		}
+		if comment.Content == input.Content {
+			return nil
+		}
+
+		if err := tx.Comments().AddRevision(ctx, &models.BountyCommentRevision{
+			CommentID: comment.ID,
+			Content:   comment.Content,
+			EditedBy:  currentUser,
+		}); err != nil {
+			return err
+		}
+
+		previous := domain.ExtractMentions(comment.Content)
+		now := time.Now()
+		comment.Content = input.Content
+		comment.ContentHTML = html
+		comment.EditedAt = &now
+		if err := tx.Comments().Save(ctx, comment); err != nil {
+			return err
+		}
+		return notifyMentions(ctx, tx, bounty, comment, domain.ExtractMentions(comment.Content), previous)
+	})
+	if err != nil {
+		respondCommentError(c, err, "Failed to edit comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// deleteBountyComment soft-deletes a comment. Authors can delete their own
+// comments and moderators any comment.
+func (s *Server) deleteBountyComment(c *gin.Context) {
+	bountyID, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	ctx := c.Request.Context()
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		comment, err := lockComment(ctx, tx, bountyID, commentID)
+		if err != nil {
+			return err
+		}
+
+		isModerator := false
+		if !strings.EqualFold(comment.UserID, currentUser) {
+			isModerator, err = services.NewRoleService(tx).HasAnyRole(ctx, currentUser, models.RoleModerator, models.RoleAdmin)
+			if err != nil {
+				return fmt.Errorf("failed to check roles of %s: %w", currentUser, err)
+			}
+		}
+		if err := domain.GuardDeleteComment(comment.UserID, currentUser, isModerator); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		comment.DeletedAt = &now
+		comment.DeletedBy = &currentUser
+		return tx.Comments().Save(ctx, comment)
+	})
+	if err != nil {
+		respondCommentError(c, err, "Failed to delete comment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// getCommentRevisions lists the earlier versions of a comment, oldest first.
+func (s *Server) getCommentRevisions(c *gin.Context) {
+	bountyID, commentID, ok := parseCommentID(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	comment, err := loadComment(ctx, s.store, bountyID, commentID)
+	if err != nil {
+		respondCommentError(c, err, "Failed to fetch revisions")
+		return
+	}
+
+	revisions, err := s.store.Comments().ListRevisions(ctx, comment.ID)
+	if err != nil {
+		respondCommentError(c, err, "Failed to fetch revisions")
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+// setCommentPolicy lets the bounty creator choose who may comment.
+func (s *Server) setCommentPolicy(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+
+	var input commentPolicyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !input.Policy.Valid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "policy must be participants or everyone"})
+		return
+	}
+
+	bounty, ok := s.loadBounty(c, id)
+	if !ok {
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	if err := domain.GuardSetCommentPolicy(bounty.Participants(), currentUser); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := s.store.Bounties().SetCommentPolicy(c.Request.Context(), bounty.ID, input.Policy); err != nil {
+		log.Printf("Failed to set comment policy of bounty %d: %v", bounty.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update comment policy"})
+		return
+	}
+	bounty.CommentPolicy = input.Policy
+
+	c.JSON(http.StatusOK, bounty)
+}
+
+// parseCommentID reads the :id and :commentId path parameters, responding
+// with 400 if either is invalid.
+func parseCommentID(c *gin.Context) (bountyID, commentID uint, ok bool) {
+	bountyID, ok = parseBountyID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := strconv.ParseUint(c.Param("commentId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return 0, 0, false
+	}
+	return bountyID, uint(id), true
+}
+
+// loadComment fetches a comment that has not been deleted.
+func loadComment(ctx context.Context, store repository.Store, bountyID, id uint) (*models.BountyComment, error) {
+	return liveComment(store.Comments().Get(ctx, bountyID, id))
+}
+
+// lockComment loads a comment like loadComment and locks it until the
+// transaction tx ends.
+func lockComment(ctx context.Context, tx repository.Store, bountyID, id uint) (*models.BountyComment, error) {
+	return liveComment(tx.Comments().GetForUpdate(ctx, bountyID, id))
+}
+
+// liveComment turns missing and deleted comments into errCommentNotFound.
+func liveComment(comment *models.BountyComment, err error) (*models.BountyComment, error) {
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, errCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if comment.Deleted() {
+		return nil, errCommentNotFound
+	}
+	return comment, nil
+}
+
+func respondCommentError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, errCommentNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+	case errors.Is(err, domain.ErrNotAllowed):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	default:
+		log.Printf("%s: %v", fallback, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// renderComment checks the length of a comment and renders its markdown,
+// responding and returning false on failure.
+func renderComment(c *gin.Context, content string) (string, bool) {
+	if strings.TrimSpace(content) == "" || utf8.RuneCountInString(content) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("content must be 1 to %d characters", maxCommentLength)})
+		return "", false
+	}
+	html, err := services.RenderMarkdown(content)
+	if err != nil {
+		log.Printf("Failed to render comment: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render comment"})
+		return "", false
+	}
+	return html, true
+}
+
+// presentComment prepares a stored comment for listing: deleted comments lose
+// their content, and comments written before markdown rendering are rendered
+// now.
+func presentComment(comment *models.BountyComment) {
+	if comment.Deleted() {
+		comment.Content, comment.ContentHTML = "", ""
+		return
+	}
+	if comment.ContentHTML == "" {
+		if html, err := services.RenderMarkdown(comment.Content); err == nil {
+			comment.ContentHTML = html
+		}
+	}
+}
+
+// notifyMentions notifies the registered users mentioned in a comment,
+// other than its author and users who were already mentioned in its previous
+// content. Unknown addresses and usernames are ignored.
+func notifyMentions(ctx context.Context, tx repository.Store, bounty *models.Bounty, comment *models.BountyComment, mentions, previous domain.Mentions) error {
+	if mentions.Empty() {
+		return nil
+	}
+	userIDs, err := mentionedUsers(ctx, tx, mentions)
+	if err != nil {
+		return err
+	}
+	previousIDs, err := mentionedUsers(ctx, tx, previous)
+	if err != nil {
+		return err
+	}
+	notified := map[string]bool{strings.ToLower(comment.UserID): true}
+	for _, userID := range previousIDs {
+		notified[userID] = true
+	}
+
+	for _, userID := range userIDs {
+		if notified[userID] {
+			continue
+		}
+		notified[userID] = true
+		if err := tx.Notifications().Create(ctx, &models.Notification{
+			UserID:   userID,
+			Type:     services.NotificationCommentMention,
+			BountyID: &bounty.ID,
+			Message:  fmt.Sprintf("%s mentioned you in a comment on %q", comment.UserID, bounty.Title),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mentionedUsers resolves mentions to the IDs of registered users. A user
+// mentioned by both address and username may appear twice.
+func mentionedUsers(ctx context.Context, tx repository.Store, mentions domain.Mentions) ([]string, error) {
+	var userIDs []string
+	for _, address := range mentions.Addresses {
+		user, err := tx.Users().Get(ctx, address)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, strings.ToLower(user.ID))
+	}
+	for _, username := range mentions.Usernames {
+		user, err := tx.Users().GetByUsername(ctx, username)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, strings.ToLower(user.ID))
+	}
+	return userIDs, nil
+}
@@ -0,0 +1,53 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+func TestDeleteComment(t *testing.T) {
+	ts := newTestServer(t)
+	const creator, bob, moderator = "0x00000000000000000000000000000000000000c0", "0x00000000000000000000000000000000000000b0", "0x000000000000000000000000000000000000000d"
+	creatorToken := ts.login(t, creator)
+	bobToken := ts.login(t, bob)
+	moderatorToken := ts.login(t, moderator)
+	if err := ts.roles.Grant(context.Background(), moderator, models.RoleModerator, "bootstrap"); err != nil {
+		t.Fatal(err)
+	}
+	bountyID := ts.createBounty(t, creatorToken, 1)
+
+	comment := func() string {
+		var comment struct {
+			ID uint `json:"id"`
+		}
+		path := fmt.Sprintf("/api/v1/bounties/%d/comments", bountyID)
+		if code := ts.do(t, http.MethodPost, path, creatorToken, gin.H{"content": "first"}, &comment); code != http.StatusCreated {
+			t.Fatalf("comment: status %d", code)
+		}
+		return fmt.Sprintf("%s/%d", path, comment.ID)
+	}
+
+	own := comment()
+	if code := ts.do(t, http.MethodDelete, own, bobToken, nil, nil); code != http.StatusForbidden {
+		t.Fatalf("delete by other user: status %d, want 403", code)
+	}
+	if code := ts.do(t, http.MethodDelete, own, creatorToken, nil, nil); code != http.StatusNoContent {
+		t.Fatalf("delete by author: status %d, want 204", code)
+	}
+	if code := ts.do(t, http.MethodDelete, own, creatorToken, nil, nil); code != http.StatusNotFound {
+		t.Fatalf("second delete: status %d, want 404", code)
+	}
+	if code := ts.do(t, http.MethodPut, own, creatorToken, gin.H{"content": "edited"}, nil); code != http.StatusNotFound {
+		t.Fatalf("edit after delete: status %d, want 404", code)
+	}
+
+	moderated := comment()
+	if code := ts.do(t, http.MethodDelete, moderated, moderatorToken, nil, nil); code != http.StatusNoContent {
+		t.Fatalf("delete by moderator: status %d, want 204", code)
+	}
+}
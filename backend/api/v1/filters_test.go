@@ -0,0 +1,244 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/bountyBoard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// bountyList is a list response. Tags are encoded as names, so items are
+// decoded into a smaller struct than models.Bounty.
+type bountyList struct {
+	Items []struct {
+		ID   uint     `json:"id"`
+		Tags []string `json:"tags"`
+	} `json:"items"`
+	Total  int64                   `json:"total"`
+	Facets repository.BountyFacets `json:"facets"`
+}
+
+const (
+	filterCreator = "0x00000000000000000000000000000000000000c0"
+	filterHunter  = "0x00000000000000000000000000000000000000b0"
+)
+
+func (ts *testServer) list(t *testing.T, query url.Values) bountyList {
+	t.Helper()
+	var list bountyList
+	if code := ts.do(t, http.MethodGet, "/api/v1/bounties?"+query.Encode(), "", nil, &list); code != http.StatusOK {
+		t.Fatalf("list %s: status %d", query.Encode(), code)
+	}
+	return list
+}
+
+func (l bountyList) ids() []uint {
+	ids := make([]uint, len(l.Items))
+	for i, item := range l.Items {
+		ids[i] = item.ID
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func equalIDs(a, b []uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func equalFacets(a, b []repository.FacetCount) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// seedTaggedBounties creates three bounties, claiming the first:
+// claimed [go solidity], open [go] and open [rust].
+func seedTaggedBounties(t *testing.T, ts *testServer) (claimed, goOnly, rust uint) {
+	t.Helper()
+	token := ts.login(t, filterCreator)
+	claimed = ts.createBountyWith(t, token, gin.H{"blockchain_id": 1, "tags": []string{"Go", "Solidity"}})
+	goOnly = ts.createBountyWith(t, token, gin.H{"blockchain_id": 2, "tags": []string{"go"}})
+	rust = ts.createBountyWith(t, token, gin.H{"blockchain_id": 3, "tags": []string{"rust"}})
+
+	path := "/api/v1/bounties/" + strconv.FormatUint(uint64(claimed), 10) + "/claim"
+	if code := ts.do(t, http.MethodPost, path, ts.login(t, filterHunter), nil, nil); code != http.StatusOK {
+		t.Fatalf("claim: status %d", code)
+	}
+	return claimed, goOnly, rust
+}
+
+func TestListBountiesMatchesTags(t *testing.T) {
+	ts := newTestServer(t)
+	claimed, goOnly, rust := seedTaggedBounties(t, ts)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []uint
+	}{
+		{"any by default", url.Values{"tags": {"solidity,rust"}}, []uint{claimed, rust}},
+		{"any", url.Values{"tags": {"go", "rust"}, "tag_match": {"any"}}, []uint{claimed, goOnly, rust}},
+		{"all", url.Values{"tags": {"go,solidity"}, "tag_match": {"all"}}, []uint{claimed}},
+		{"all of one", url.Values{"tags": {"go"}, "tag_match": {"all"}}, []uint{claimed, goOnly}},
+		{"all without a match", url.Values{"tags": {"go,rust"}, "tag_match": {"all"}}, []uint{}},
+		{"normalised", url.Values{"tags": {" GO , Solidity"}, "tag_match": {"all"}}, []uint{claimed}},
+	}
+	for _, tt := range tests {
+		list := ts.list(t, tt.query)
+		if got := list.ids(); !equalIDs(got, tt.want) || list.Total != int64(len(tt.want)) {
+			t.Errorf("%s: ids = %v (total %d), want %v", tt.name, got, list.Total, tt.want)
+		}
+	}
+
+	for _, query := range []string{"tag_match=some", "tags=" + url.QueryEscape("not/a/tag")} {
+		if code := ts.do(t, http.MethodGet, "/api/v1/bounties?"+query, "", nil, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", query, code)
+		}
+	}
+}
+
+func TestListBountiesFacetsIgnoreTheirOwnField(t *testing.T) {
+	ts := newTestServer(t)
+	_, goOnly, _ := seedTaggedBounties(t, ts)
+
+	list := ts.list(t, url.Values{"status": {"open"}, "tags": {"go"}})
+	if got := list.ids(); !equalIDs(got, []uint{goOnly}) {
+		t.Fatalf("ids = %v, want [%d]", got, goOnly)
+	}
+
+	// The status facet keeps the tag filter but counts every status
+	wantStatus := []repository.FacetCount{{Value: "claimed", Count: 1}, {Value: "open", Count: 1}}
+	if !equalFacets(list.Facets.Status, wantStatus) {
+		t.Errorf("status facet = %v, want %v", list.Facets.Status, wantStatus)
+	}
+	// The tags facet keeps the status filter but counts every tag
+	wantTags := []repository.FacetCount{{Value: "go", Count: 1}, {Value: "rust", Count: 1}}
+	if !equalFacets(list.Facets.Tags, wantTags) {
+		t.Errorf("tags facet = %v, want %v", list.Facets.Tags, wantTags)
+	}
+
+	all := ts.list(t, nil)
+	wantTags = []repository.FacetCount{{Value: "go", Count: 2}, {Value: "rust", Count: 1}, {Value: "solidity", Count: 1}}
+	if !equalFacets(all.Facets.Tags, wantTags) {
+		t.Errorf("unfiltered tags facet = %v, want %v", all.Facets.Tags, wantTags)
+	}
+}
+
+func TestCreateBountyReusesNormalisedTags(t *testing.T) {
+	ts := newTestServer(t)
+	token := ts.login(t, filterCreator)
+	first := ts.createBountyWith(t, token, gin.H{"blockchain_id": 1, "tags": []string{"Smart Contracts"}})
+	second := ts.createBountyWith(t, token, gin.H{"blockchain_id": 2, "tags": []string{"smart-contracts", "SMART  contracts"}})
+
+	ctx := context.Background()
+	a, err := ts.store.Bounties().Get(ctx, first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ts.store.Bounties().Get(ctx, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Tags) != 1 || len(b.Tags) != 1 {
+		t.Fatalf("tags = %v and %v, want one tag each", a.Tags, b.Tags)
+	}
+	if a.Tags[0].Name != "smart-contracts" || a.Tags[0].ID != b.Tags[0].ID {
+		t.Errorf("tags = %+v and %+v, want the same smart-contracts tag", a.Tags[0], b.Tags[0])
+	}
+
+	want := []repository.FacetCount{{Value: "smart-contracts", Count: 2}}
+	if got := ts.list(t, nil).Facets.Tags; !equalFacets(got, want) {
+		t.Errorf("tags facet = %v, want %v", got, want)
+	}
+
+	if code := ts.do(t, http.MethodPost, "/api/v1/bounties", token, gin.H{
+		"blockchain_id": 3, "title": "Fix", "description": "It crashes", "reward": "1", "deadline": "2030-01-01T00:00:00Z", "txHash": "0xabc",
+		"tags": []string{"not/a/tag"},
+	}, nil); code != http.StatusBadRequest {
+		t.Errorf("invalid tag: status %d, want 400", code)
+	}
+}
+
+// seedRangedBounties creates bounties with rewards 1, 1.5 and 2 due at
+// midnight UTC on the 1st, 2nd and 3rd of January 2030.
+func seedRangedBounties(t *testing.T, ts *testServer) (a, b, c uint) {
+	t.Helper()
+	token := ts.login(t, filterCreator)
+	a = ts.createBountyWith(t, token, gin.H{"blockchain_id": 1, "reward": "1", "deadline": "2030-01-01T00:00:00Z"})
+	b = ts.createBountyWith(t, token, gin.H{"blockchain_id": 2, "reward": "1.5", "deadline": "2030-01-02T00:00:00Z"})
+	c = ts.createBountyWith(t, token, gin.H{"blockchain_id": 3, "reward": "2", "deadline": "2030-01-03T00:00:00Z"})
+	return a, b, c
+}
+
+func TestListBountiesByRewardAndDeadline(t *testing.T) {
+	ts := newTestServer(t)
+	a, b, c := seedRangedBounties(t, ts)
+
+	tests := []struct {
+		name  string
+		query url.Values
+		want  []uint
+	}{
+		{"min reward is inclusive", url.Values{"min_reward": {"1.5"}}, []uint{b, c}},
+		{"max reward is inclusive", url.Values{"max_reward": {"1.5"}}, []uint{a, b}},
+		{"equal bounds", url.Values{"min_reward": {"1.50"}, "max_reward": {"1.500000"}}, []uint{b}},
+		{"just above", url.Values{"min_reward": {"1.500000000000000001"}}, []uint{c}},
+		{"deadline after is inclusive", url.Values{"deadline_after": {"2030-01-02"}}, []uint{b, c}},
+		{"deadline before is exclusive", url.Values{"deadline_before": {"2030-01-02"}}, []uint{a}},
+		{"deadline window", url.Values{"deadline_after": {"2030-01-01"}, "deadline_before": {"2030-01-03"}}, []uint{a, b}},
+		{"offset timestamp", url.Values{"deadline_after": {"2030-01-02T01:00:00+01:00"}}, []uint{b, c}},
+		{"reward and deadline", url.Values{"min_reward": {"1.5"}, "deadline_before": {"2030-01-03"}}, []uint{b}},
+	}
+	for _, tt := range tests {
+		if got := ts.list(t, tt.query).ids(); !equalIDs(got, tt.want) {
+			t.Errorf("%s: ids = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestListBountiesRejectsBadRanges(t *testing.T) {
+	ts := newTestServer(t)
+	seedRangedBounties(t, ts)
+
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{"reward not a number", url.Values{"min_reward": {"lots"}}},
+		{"reward with a unit", url.Values{"max_reward": {"1.5eth"}}},
+		{"negative reward", url.Values{"min_reward": {"-1"}}},
+		{"too many decimals", url.Values{"max_reward": {"0.0000000000000000001"}}},
+		{"min above max", url.Values{"min_reward": {"2"}, "max_reward": {"1"}}},
+		{"date not a date", url.Values{"deadline_after": {"tomorrow"}}},
+		{"date out of range", url.Values{"deadline_before": {"2030-13-01"}}},
+		{"date in another format", url.Values{"deadline_after": {"01/02/2030"}}},
+		{"timestamp without a zone", url.Values{"deadline_after": {"2030-01-02T00:00:00"}}},
+		{"empty window", url.Values{"deadline_after": {"2030-01-02"}, "deadline_before": {"2030-01-02"}}},
+		{"reversed window", url.Values{"deadline_after": {"2030-01-03"}, "deadline_before": {"2030-01-01"}}},
+		{"created since not a date", url.Values{"created_since": {"last week"}}},
+	}
+	for _, tt := range tests {
+		if code := ts.do(t, http.MethodGet, "/api/v1/bounties?"+tt.query.Encode(), "", nil, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", tt.name, code)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+// maxBountyMetadataRequest caps the body of prepareBountyMetadata.
+const maxBountyMetadataRequest = 256 << 10
+
+// BountyMetadataResponse names a pinned metadata document. URI is the
+// metadata string to pass to the contract's createBounty.
+type BountyMetadataResponse struct {
+	CID      string                   `json:"cid"`
+	URI      string                   `json:"uri"`
+	Metadata *services.BountyMetadata `json:"metadata"`
+}
+
+// getBountyMetadataSchema serves the JSON Schema of metadata documents.
+func (s *Server) getBountyMetadataSchema(c *gin.Context) {
+	c.Data(http.StatusOK, "application/schema+json", services.BountyMetadataSchema())
+}
+
+// prepareBountyMetadata validates and pins a metadata document before the
+// bounty is created on-chain, so the contract can reference it by CID.
+// Version and creator default to the current version and the caller, and
+// labels are normalised.
+func (s *Server) prepareBountyMetadata(c *gin.Context) {
+	decoder := json.NewDecoder(http.MaxBytesReader(c.Writer, c.Request.Body, maxBountyMetadataRequest))
+	decoder.DisallowUnknownFields()
+	var metadata services.BountyMetadata
+	if err := decoder.Decode(&metadata); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	if metadata.Version == 0 {
+		metadata.Version = services.BountyMetadataVersion
+	}
+	if metadata.Creator == "" {
+		metadata.Creator = currentUser
+	}
+	if metadata.Creator != currentUser {
+		c.JSON(http.StatusForbidden, gin.H{"error": "creator must be the caller"})
+		return
+	}
+	metadata.Deadline = metadata.Deadline.UTC()
+	metadata.Category = strings.ToLower(metadata.Category)
+	var err error
+	if metadata.Tags, err = domain.NormalizeLabels(metadata.Tags, domain.MaxTagsPerBounty); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "tags: " + err.Error()})
+		return
+	}
+	if metadata.Skills, err = domain.NormalizeLabels(metadata.Skills, domain.MaxSkillsPerBounty); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "skills: " + err.Error()})
+		return
+	}
+
+	cid, err := services.NewBountyMetadataService(s.content).Pin(c.Request.Context(), &metadata)
+	if errors.Is(err, services.ErrInvalidBountyMetadata) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to pin bounty metadata: %v", err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to pin bounty metadata"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, BountyMetadataResponse{
+		CID:      cid,
+		URI:      services.MetadataURI(cid),
+		Metadata: &metadata,
+	})
+}
+
+// resolveBountyMetadata pins the metadata document of a new bounty and
+// returns its CID. If the client already pinned a document with
+// prepareBountyMetadata, its CID is given instead and the document must
+// describe the same bounty. It responds and returns false on failure.
+func (s *Server) resolveBountyMetadata(c *gin.Context, metadata *services.BountyMetadata, cid string) (string, bool) {
+	service := services.NewBountyMetadataService(s.content)
+	if cid == "" {
+		cid, err := service.Pin(c.Request.Context(), metadata)
+		if errors.Is(err, services.ErrInvalidBountyMetadata) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", false
+		}
+		if err != nil {
+			log.Printf("Failed to pin bounty metadata: %v", err)
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to pin bounty metadata"})
+			return "", false
+		}
+		return cid, true
+	}
+
+	pinned, _, err := service.Load(c.Request.Context(), cid)
+	switch {
+	case errors.Is(err, services.ErrBountyMetadataNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metadata_cid was not found"})
+		return "", false
+	case errors.Is(err, services.ErrInvalidBountyMetadata), errors.Is(err, storage.ErrUnsupportedCID):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metadata_cid: " + err.Error()})
+		return "", false
+	case err != nil:
+		log.Printf("Failed to load bounty metadata %s: %v", cid, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch bounty metadata"})
+		return "", false
+	}
+	if field := pinned.Mismatch(metadata); field != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "metadata_cid does not match the bounty's " + field})
+		return "", false
+	}
+
+	// Normalise the CID's encoding and make sure it stays pinned
+	parsed, _ := storage.ParseCID(cid)
+	if err := s.content.Pin(c.Request.Context(), parsed); err != nil {
+		log.Printf("Failed to pin bounty metadata %s: %v", cid, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to pin bounty metadata"})
+		return "", false
+	}
+	return parsed.String(), true
+}
+
+// getBountyMetadata serves a bounty's metadata document exactly as pinned,
+// so clients can check it against its CID.
+func (s *Server) getBountyMetadata(c *gin.Context) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+	bounty, ok := s.loadBounty(c, id)
+	if !ok {
+		return
+	}
+	if bounty.IPFSHash == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty has no metadata document"})
+		return
+	}
+
+	etag := `"` + bounty.IPFSHash + `"`
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	_, data, err := services.NewBountyMetadataService(s.content).Load(c.Request.Context(), bounty.IPFSHash)
+	if errors.Is(err, services.ErrBountyMetadataNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Bounty metadata not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to load metadata of bounty %d: %v", bounty.ID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch bounty metadata"})
+		return
+	}
+
+	c.Header("ETag", etag)
+	c.Header("X-Metadata-CID", bounty.IPFSHash)
+	c.Data(http.StatusOK, "application/json", data)
+}
@@ -0,0 +1,44 @@
+package v1
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *Server) RegisterNotificationRoutes(router *gin.RouterGroup) {
+	notifications := router.Group("/notifications")
+	{
+		notifications.GET("", s.listNotifications)
+		notifications.POST("/:id/read", s.markNotificationRead)
+	}
+}
+
+func (s *Server) listNotifications(c *gin.Context) {
+	unreadOnly := c.Query("unread") == "true"
+
+	notifications, err := s.notifications.List(c.Request.Context(), middleware.CurrentUserID(c), unreadOnly)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (s *Server) markNotificationRead(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := s.notifications.MarkRead(c.Request.Context(), middleware.CurrentUserID(c), uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/services"
+)
+
+func TestNotifications(t *testing.T) {
+	ts := newTestServer(t)
+	const alice = "0x00000000000000000000000000000000000000a1"
+	token := ts.login(t, alice)
+
+	notification := models.Notification{UserID: alice, Type: services.NotificationCommentMention, Message: "You were mentioned"}
+	if err := ts.store.Notifications().Create(context.Background(), &notification); err != nil {
+		t.Fatal(err)
+	}
+
+	var unread []models.Notification
+	if code := ts.do(t, http.MethodGet, "/api/v1/notifications?unread=true", token, nil, &unread); code != http.StatusOK {
+		t.Fatalf("list: status %d", code)
+	}
+	if len(unread) != 1 || unread[0].ID != notification.ID {
+		t.Fatalf("unread = %+v", unread)
+	}
+
+	path := "/api/v1/notifications/" + strconv.FormatUint(uint64(notification.ID), 10) + "/read"
+	if code := ts.do(t, http.MethodPost, path, token, nil, nil); code != http.StatusOK {
+		t.Fatalf("mark read: status %d", code)
+	}
+	if code := ts.do(t, http.MethodGet, "/api/v1/notifications?unread=true", token, nil, &unread); code != http.StatusOK || len(unread) != 0 {
+		t.Fatalf("unread after marking = %d, %+v", code, unread)
+	}
+
+	// Other users cannot see it
+	var others []models.Notification
+	ts.do(t, http.MethodGet, "/api/v1/notifications", ts.login(t, "0x00000000000000000000000000000000000000b0"), nil, &others)
+	if len(others) != 0 {
+		t.Fatalf("other user sees %+v", others)
+	}
+}
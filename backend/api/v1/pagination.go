@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/bountyBoard/internal/repository"
+	"github.com/gin-gonic/gin"
+)
+
+// parsePage reads the limit, cursor, page, sort and order query parameters,
+// responding with 400 if any is invalid. Only the listed sort fields are
+// accepted; the first one is the default.
+func parsePage(c *gin.Context, defaultDesc bool, fields ...repository.SortField) (repository.PageRequest, bool) {
+	page := repository.PageRequest{
+		Limit: repository.DefaultPageLimit,
+		Sort:  fields[0],
+		Desc:  defaultDesc,
+	}
+
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > repository.MaxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and " + strconv.Itoa(repository.MaxPageLimit)})
+			return page, false
+		}
+		page.Limit = limit
+	}
+
+	if v := c.Query("sort"); v != "" {
+		field, err := repository.ParseSortField(v)
+		if err == nil && !containsSortField(fields, field) {
+			err = repository.ErrInvalidSort
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort field"})
+			return page, false
+		}
+		page.Sort = field
+	}
+
+	switch c.Query("order") {
+	case "":
+	case "asc":
+		page.Desc = false
+	case "desc":
+		page.Desc = true
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+		return page, false
+	}
+
+	cursor, pageNumber := c.Query("cursor"), c.Query("page")
+	if cursor != "" && pageNumber != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use either cursor or page, not both"})
+		return page, false
+	}
+	if cursor != "" {
+		after, err := repository.DecodeCursor(cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return page, false
+		}
+		if !after.Matches(page) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Cursor was issued for a different sort or order"})
+			return page, false
+		}
+		page.After = after
+	}
+	if pageNumber != "" {
+		n, err := strconv.Atoi(pageNumber)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive number"})
+			return page, false
+		}
+		page.Offset = (n - 1) * page.Limit
+	}
+
+	return page, true
+}
+
+func containsSortField(fields []repository.SortField, field repository.SortField) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// isPageError reports whether err was caused by the client's page request,
+// e.g. a cursor that does not match the requested sort field.
+func isPageError(err error) bool {
+	return errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidSort)
+}
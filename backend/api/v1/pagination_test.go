@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+type bountyPage struct {
+	Items      []models.Bounty `json:"items"`
+	NextCursor *string         `json:"next_cursor"`
+	Total      int64           `json:"total"`
+}
+
+func TestListBountiesPages(t *testing.T) {
+	ts := newTestServer(t)
+	token := ts.login(t, "0x00000000000000000000000000000000000000c0")
+	for i := uint(1); i <= 5; i++ {
+		ts.createBounty(t, token, i)
+	}
+
+	seen := map[uint]bool{}
+	query := url.Values{"limit": {"2"}, "sort": {"reward"}, "order": {"asc"}}
+	for pages := 1; ; pages++ {
+		var page bountyPage
+		if code := ts.do(t, http.MethodGet, "/api/v1/bounties?"+query.Encode(), "", nil, &page); code != http.StatusOK {
+			t.Fatalf("page %d: status %d", pages, code)
+		}
+		if page.Total != 5 {
+			t.Fatalf("page %d: total = %d, want 5", pages, page.Total)
+		}
+		for _, b := range page.Items {
+			if seen[b.ID] {
+				t.Fatalf("page %d repeats bounty %d", pages, b.ID)
+			}
+			seen[b.ID] = true
+		}
+		if page.NextCursor == nil {
+			if pages != 3 || len(page.Items) != 1 {
+				t.Fatalf("last page is page %d with %d items, want page 3 with 1", pages, len(page.Items))
+			}
+			break
+		}
+		query.Set("cursor", *page.NextCursor)
+	}
+	if len(seen) != 5 {
+		t.Fatalf("walked %d bounties, want 5", len(seen))
+	}
+}
+
+func TestListBountiesRejectsBadCursors(t *testing.T) {
+	ts := newTestServer(t)
+	token := ts.login(t, "0x00000000000000000000000000000000000000c0")
+	for i := uint(1); i <= 3; i++ {
+		ts.createBounty(t, token, i)
+	}
+
+	var first bountyPage
+	if code := ts.do(t, http.MethodGet, "/api/v1/bounties?limit=1&sort=reward", "", nil, &first); code != http.StatusOK || first.NextCursor == nil {
+		t.Fatalf("first page: status %d, cursor %v", code, first.NextCursor)
+	}
+	cursor := url.QueryEscape(*first.NextCursor)
+	forged := repository.Cursor{Sort: repository.SortDeadline, Desc: true, Value: "not a time", ID: 1}.Encode()
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"other sort", "sort=deadline&cursor=" + cursor},
+		{"default sort", "cursor=" + cursor},
+		{"other order", "sort=reward&order=asc&cursor=" + cursor},
+		{"garbage", "sort=reward&cursor=" + url.QueryEscape("not-a-cursor")},
+		{"edited json", "sort=reward&cursor=" + base64.RawURLEncoding.EncodeToString([]byte(`{"s":"reward","d":true,"v":"1.5"}`))},
+		{"malformed value", "sort=deadline&cursor=" + forged},
+		{"cursor and page", "sort=reward&page=2&cursor=" + cursor},
+	}
+	for _, tt := range tests {
+		if code := ts.do(t, http.MethodGet, "/api/v1/bounties?limit=1&"+tt.query, "", nil, nil); code != http.StatusBadRequest {
+			t.Errorf("%s: status %d, want 400", tt.name, code)
+		}
+	}
+
+	if code := ts.do(t, http.MethodGet, "/api/v1/bounties?limit=1&sort=reward&cursor="+cursor, "", nil, nil); code != http.StatusOK {
+		t.Fatalf("matching cursor: status %d", code)
+	}
+}
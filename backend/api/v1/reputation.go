@@ -2,28 +2,39 @@ package v1
 
 import (
 	"net/http"
+	"strings"
 
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
 	"github.com/bountyBoard/internal/services"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterReputationRoutes(router *gin.Engine) {
+func (s *Server) RegisterReputationRoutes(router *gin.Engine) {
 	v1 := router.Group("/api/v1")
 	{
-		v1.GET("/reputation/:userId", getReputation)
-		v1.POST("/reputation/update", updateReputation)
+		v1.GET("/reputation/:userId", s.getReputation)
+		v1.GET("/reputation/:userId/history", s.getReputationHistory)
+	}
+
+	// Points are awarded automatically; manual adjustments are for admins
+	admin := router.Group("/api/v1")
+	admin.Use(middleware.WalletAuth(s.store), middleware.RequireRole(s.store, models.RoleAdmin))
+	{
+		admin.POST("/reputation/update", s.updateReputation)
 	}
 }
 
-func getReputation(c *gin.Context) {
+func (s *Server) getReputation(c *gin.Context) {
 	userID := c.Param("userId")
 	if userID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
 		return
 	}
 
-	reputationService := services.NewReputationService()
-	reputation, err := reputationService.GetUserReputation(userID)
+	reputationService := services.NewReputationService(s.store)
+	reputation, err := reputationService.GetUserReputation(c.Request.Context(), userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -32,27 +43,56 @@ func getReputation(c *gin.Context) {
 	c.JSON(http.StatusOK, reputation)
 }
 
+// getReputationHistory lists the ledger events behind a user's score, newest
+// first.
+func (s *Server) getReputationHistory(c *gin.Context) {
+	userID := c.Param("userId")
+
+	page, ok := parsePage(c, true, repository.SortCreatedAt)
+	if !ok {
+		return
+	}
+
+	history, err := services.NewReputationService(s.store).History(c.Request.Context(), userID, page)
+	if isPageError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch reputation history"})
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 type UpdateReputationRequest struct {
 	UserID string `json:"userId" binding:"required"`
 	Points int    `json:"points" binding:"required"`
 }
 
-func updateReputation(c *gin.Context) {
+// updateReputation records a manual adjustment by an admin.
+func (s *Server) updateReputation(c *gin.Context) {
 	var req UpdateReputationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	reputationService := services.NewReputationService()
-	reputation, err := reputationService.UpdateScore(req.UserID, req.Points)
+	reputationService := services.NewReputationService(s.store)
+	reputation, err := reputationService.Record(c.Request.Context(), &models.ReputationEvent{
+		UserID:  strings.ToLower(req.UserID),
+		Delta:   req.Points,
+		Reason:  models.ReasonManualAdjustment,
+		ActorID: middleware.CurrentUserID(c),
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Check and award milestone badges
-	if err := reputationService.CheckAndAwardMilestoneBadges(req.UserID, reputation); err != nil {
+	if _, err := reputationService.CheckAndAwardMilestoneBadges(c.Request.Context(), reputation.UserID, reputation); err != nil {
 		// Log error but don't fail the request
 		// TODO: Add proper error logging
 		c.JSON(http.StatusOK, gin.H{
@@ -0,0 +1,266 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/bountyBoard/pkg/sealed"
+	"github.com/gin-gonic/gin"
+)
+
+var errNoEncryptionKey = errors.New("user has no encryption key")
+
+// SealedSubmission is what a reader of an encrypted submission needs to
+// decrypt it: the ciphertext's CID and their own key envelope.
+type SealedSubmission struct {
+	SubmissionID  uint            `json:"submission_id"`
+	BountyID      uint            `json:"bounty_id"`
+	CiphertextCID string          `json:"ciphertext_cid"`
+	Envelope      sealed.Envelope `json:"envelope"`
+}
+
+// registeredKey returns the raw public key a user registered for private
+// submissions.
+func (s *Server) registeredKey(ctx context.Context, userID string) ([]byte, error) {
+	user, err := s.store.Users().Get(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, errNoEncryptionKey
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user.EncryptionPublicKey == nil {
+		return nil, errNoEncryptionKey
+	}
+	key, err := sealed.ParsePublicKey(*user.EncryptionPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.Bytes(), nil
+}
+
+// checkEnvelopeKey responds and returns false unless envelope is wrapped for
+// the key its recipient registered.
+func (s *Server) checkEnvelopeKey(c *gin.Context, envelope *sealed.Envelope) bool {
+	key, err := s.registeredKey(c.Request.Context(), envelope.RecipientID)
+	if errors.Is(err, errNoEncryptionKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipient " + envelope.RecipientID + " has no encryption key"})
+		return false
+	}
+	if err != nil {
+		log.Printf("Failed to load encryption key of %s: %v", envelope.RecipientID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch encryption key"})
+		return false
+	}
+	if !bytes.Equal(key, envelope.RecipientKey) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Envelope for " + envelope.RecipientID + " is not wrapped for their registered key"})
+		return false
+	}
+	return true
+}
+
+// checkSealedForm validates an encrypted submission. It must carry no
+// plaintext, and its envelopes must include one for the creator and may
+// include one for the hunter, each wrapped for the key they registered.
+// Arbiters are added later with reshareSubmission. It responds and returns
+// false on failure.
+func (s *Server) checkSealedForm(c *gin.Context, bounty *models.Bounty, hunterID string, form *submissionForm) ([]models.SubmissionKeyEnvelope, bool) {
+	if form.Content != "" || len(form.Files) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Encrypted submissions cannot include plaintext content or files"})
+		return nil, false
+	}
+	if form.CiphertextCID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ciphertext is required"})
+		return nil, false
+	}
+
+	participants := bounty.Participants()
+	envelopes := make([]models.SubmissionKeyEnvelope, 0, len(form.Envelopes))
+	seen := make(map[string]bool)
+	for i := range form.Envelopes {
+		envelope := &form.Envelopes[i]
+		if err := envelope.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return nil, false
+		}
+		envelope.RecipientID = strings.ToLower(envelope.RecipientID)
+		if !participants.IsParticipant(envelope.RecipientID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Envelopes can only be addressed to the bounty creator or the hunter"})
+			return nil, false
+		}
+		if seen[envelope.RecipientID] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Duplicate envelope for " + envelope.RecipientID})
+			return nil, false
+		}
+		seen[envelope.RecipientID] = true
+		if !s.checkEnvelopeKey(c, envelope) {
+			return nil, false
+		}
+		envelopes = append(envelopes, models.SubmissionKeyEnvelope{
+			RecipientID:  envelope.RecipientID,
+			RecipientKey: envelope.RecipientKey,
+			EphemeralKey: envelope.EphemeralKey,
+			WrappedKey:   envelope.WrappedKey,
+			CreatedBy:    strings.ToLower(hunterID),
+		})
+	}
+	if !seen[strings.ToLower(bounty.CreatorID)] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An envelope for the bounty creator is required"})
+		return nil, false
+	}
+	return envelopes, true
+}
+
+// loadSealedSubmission loads an encrypted submission and the caller's
+// envelope. Holding an envelope is what allows reading the submission. It
+// responds and returns false on failure.
+func (s *Server) loadSealedSubmission(c *gin.Context) (*models.Bounty, *models.BountySubmission, *models.SubmissionKeyEnvelope, bool) {
+	bounty, submission, ok := s.loadSubmission(c)
+	if !ok {
+		return nil, nil, nil, false
+	}
+	if !submission.Encrypted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Submission is not encrypted"})
+		return nil, nil, nil, false
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	envelope, err := s.store.Submissions().GetEnvelope(c.Request.Context(), submission.ID, currentUser)
+	if errors.Is(err, repository.ErrNotFound) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Submission is not shared with you"})
+		return nil, nil, nil, false
+	}
+	if err != nil {
+		log.Printf("Failed to load envelope of submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		return nil, nil, nil, false
+	}
+	return bounty, submission, envelope, true
+}
+
+func (s *Server) getSealedSubmission(c *gin.Context) {
+	_, submission, envelope, ok := s.loadSealedSubmission(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, SealedSubmission{
+		SubmissionID:  submission.ID,
+		BountyID:      submission.BountyID,
+		CiphertextCID: submission.CiphertextCID,
+		Envelope: sealed.Envelope{
+			RecipientID:  envelope.RecipientID,
+			RecipientKey: envelope.RecipientKey,
+			EphemeralKey: envelope.EphemeralKey,
+			WrappedKey:   envelope.WrappedKey,
+		},
+	})
+}
+
+// downloadSealedSubmission streams the ciphertext of an encrypted submission
+// to a reader holding an envelope.
+func (s *Server) downloadSealedSubmission(c *gin.Context) {
+	_, submission, _, ok := s.loadSealedSubmission(c)
+	if !ok {
+		return
+	}
+
+	cid, err := storage.ParseCID(submission.CiphertextCID)
+	if err != nil {
+		log.Printf("Invalid ciphertext CID on submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch submission"})
+		return
+	}
+	content, err := s.content.Get(c.Request.Context(), cid)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ciphertext not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open ciphertext %s: %v", submission.CiphertextCID, err)
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch ciphertext"})
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", content, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+	})
+	if err := c.Errors.Last(); err != nil {
+		log.Printf("Failed to send ciphertext %s: %v", submission.CiphertextCID, err)
+	}
+}
+
+// reshareSubmission adds an envelope for an arbiter while the bounty is
+// disputed. The envelope is made by the creator or hunter from their own, as
+// the server never holds the content key.
+func (s *Server) reshareSubmission(c *gin.Context) {
+	bounty, submission, _, ok := s.loadSealedSubmission(c)
+	if !ok {
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+	if err := domain.GuardReshareSubmission(bounty.Participants(), submission.HunterID, currentUser); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if bounty.Status != domain.StatusDisputed {
+		c.JSON(http.StatusConflict, gin.H{"error": "Submissions can only be shared while the bounty is disputed"})
+		return
+	}
+
+	var envelope sealed.Envelope
+	if err := c.ShouldBindJSON(&envelope); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := envelope.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	envelope.RecipientID = strings.ToLower(envelope.RecipientID)
+
+	isArbiter, err := s.roles.HasAnyRole(c.Request.Context(), envelope.RecipientID, models.RoleArbiter, models.RoleAdmin)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check roles"})
+		return
+	}
+	if !isArbiter {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Submissions can only be shared with arbiters"})
+		return
+	}
+	if !s.checkEnvelopeKey(c, &envelope) {
+		return
+	}
+
+	record := models.SubmissionKeyEnvelope{
+		SubmissionID: submission.ID,
+		RecipientID:  envelope.RecipientID,
+		RecipientKey: envelope.RecipientKey,
+		EphemeralKey: envelope.EphemeralKey,
+		WrappedKey:   envelope.WrappedKey,
+		CreatedBy:    currentUser,
+	}
+	err = s.store.Submissions().AddEnvelope(c.Request.Context(), &record)
+	if errors.Is(err, repository.ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Submission is already shared with " + envelope.RecipientID})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to save envelope for submission %d: %v", submission.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to share submission"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/internal/storage"
+)
+
+// Server holds the dependencies shared by the API handlers.
+type Server struct {
+	store   repository.Store
+	content storage.ContentStore
+	// verifier checks new bounties against the chain; nil when no chain is
+	// configured, in which case bounties open immediately.
+	verifier *services.BountyVerifier
+
+	users         *services.UserService
+	sessions      *services.SessionService
+	roles         *services.RoleService
+	nonces        *services.NonceService
+	notifications *services.NotificationService
+}
+
+func NewServer(store repository.Store, content storage.ContentStore, verifier *services.BountyVerifier) *Server {
+	return &Server{
+		store:         store,
+		content:       content,
+		verifier:      verifier,
+		users:         services.NewUserService(store),
+		sessions:      services.NewSessionService(store),
+		roles:         services.NewRoleService(store),
+		nonces:        services.NewNonceService(store),
+		notifications: services.NewNotificationService(store),
+	}
+}
@@ -0,0 +1,136 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	auth.InitTokens()
+	os.Exit(m.Run())
+}
+
+// testServer is a Server backed by in-memory stores with every route
+// registered the way main wires them.
+type testServer struct {
+	*Server
+	store  repository.Store
+	router *gin.Engine
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	return newTestServerWith(t, repository.NewMemoryStore())
+}
+
+// newTestServerWith is newTestServer backed by store instead.
+func newTestServerWith(t *testing.T, store repository.Store) *testServer {
+	t.Helper()
+
+	server := NewServer(store, storage.NewMemoryStore(), nil)
+
+	router := gin.New()
+	server.RegisterAuthRoutes(router)
+	server.RegisterBountyRoutes(router)
+	server.RegisterReputationRoutes(router)
+	server.RegisterAdminRoutes(router)
+	protected := router.Group("/api/v1")
+	protected.Use(middleware.WalletAuth(store))
+	server.RegisterUserRoutes(protected)
+	server.RegisterNotificationRoutes(protected)
+
+	return &testServer{Server: server, store: store, router: router}
+}
+
+// login signs address in and returns an access token for it.
+func (ts *testServer) login(t *testing.T, address string) string {
+	t.Helper()
+
+	ctx := context.Background()
+	user, err := ts.users.FindOrCreateByAddress(ctx, address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := ts.sessions.Create(ctx, user, services.ProviderWallet, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tokens.AccessToken
+}
+
+// do sends a JSON request and decodes the response into out if it is not nil.
+func (ts *testServer) do(t *testing.T, method, path, token string, body, out interface{}) int {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	ts.router.ServeHTTP(w, req)
+
+	if out != nil {
+		if err := json.Unmarshal(w.Body.Bytes(), out); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w.Code
+}
+
+// createBounty creates an open bounty as the holder of token.
+func (ts *testServer) createBounty(t *testing.T, token string, blockchainID uint) uint {
+	t.Helper()
+	return ts.createBountyWith(t, token, gin.H{"blockchain_id": blockchainID})
+}
+
+// createBountyWith creates an open bounty as the holder of token from a
+// default request overridden by fields.
+func (ts *testServer) createBountyWith(t *testing.T, token string, fields gin.H) uint {
+	t.Helper()
+
+	req := gin.H{
+		"title":       "Fix the parser",
+		"description": "It crashes on empty input",
+		"reward":      "1.5",
+		"deadline":    time.Now().Add(7 * 24 * time.Hour),
+		"txHash":      "0xabc",
+	}
+	for k, v := range fields {
+		req[k] = v
+	}
+
+	var bounty struct {
+		ID uint `json:"id"`
+	}
+	if code := ts.do(t, http.MethodPost, "/api/v1/bounties", token, req, &bounty); code != http.StatusCreated {
+		t.Fatalf("create bounty: status %d", code)
+	}
+	return bounty.ID
+}
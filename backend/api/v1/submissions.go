@@ -0,0 +1,139 @@
+package v1
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+var errSubmissionNotFound = errors.New("submission not found")
+
+type ReviewSubmissionRequest struct {
+	Reason string `json:"reason"`
+}
+
+func (s *Server) acceptSubmission(c *gin.Context) {
+	s.reviewSubmission(c, domain.DecisionAccept)
+}
+
+func (s *Server) rejectSubmission(c *gin.Context) {
+	s.reviewSubmission(c, domain.DecisionReject)
+}
+
+func (s *Server) requestSubmissionChanges(c *gin.Context) {
+	s.reviewSubmission(c, domain.DecisionRequestChanges)
+}
+
+// reviewSubmission records the creator's decision on a pending submission.
+// Accepting completes the bounty. Rejecting either reopens the bounty for
+// other hunters or leaves it with the current hunter to resubmit, depending on
+// the bounty's ReopenOnReject setting. Requesting changes always leaves it
+// with the current hunter. Only submissions from the current hunter can be
+// reviewed, and reopening marks the hunter's other pending submissions
+// obsolete.
+func (s *Server) reviewSubmission(c *gin.Context, decision domain.ReviewDecision) {
+	id, ok := parseBountyID(c)
+	if !ok {
+		return
+	}
+	submissionID, err := strconv.ParseUint(c.Param("submissionId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid submission ID"})
+		return
+	}
+
+	var req ReviewSubmissionRequest
+	// The body is optional when accepting
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if decision != domain.DecisionAccept && reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A reason is required"})
+		return
+	}
+
+	currentUser := strings.ToLower(middleware.CurrentUserID(c))
+
+	ctx := c.Request.Context()
+	var submission *models.BountySubmission
+	bounty, err := services.WithLockedBounty(ctx, s.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if err := domain.GuardReview(bounty.Participants(), currentUser); err != nil {
+			return err
+		}
+
+		var err error
+		submission, err = tx.Submissions().GetForUpdate(ctx, bounty.ID, uint(submissionID))
+		if errors.Is(err, repository.ErrNotFound) {
+			return errSubmissionNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := domain.GuardReviewedSubmission(bounty.Participants(), submission.HunterID); err != nil {
+			return err
+		}
+
+		status, err := domain.ReviewSubmission(submission.Status, decision)
+		if err != nil {
+			return err
+		}
+
+		// Move the bounty first so an invalid bounty state aborts the review
+		switch {
+		case decision == domain.DecisionAccept:
+			if err := tx.Bounties().Transition(ctx, bounty, domain.ActionComplete, currentUser); err != nil {
+				return err
+			}
+			if err := services.NewReputationService(tx).AwardCompletion(ctx, bounty, currentUser); err != nil {
+				return err
+			}
+		case decision == domain.DecisionReject && bounty.ReopenOnReject:
+			bounty.HunterID = nil
+			if err := tx.Bounties().Transition(ctx, bounty, domain.ActionReopen, currentUser); err != nil {
+				return err
+			}
+		default:
+			// The hunter keeps the bounty and must be able to resubmit
+			if !domain.CanTransition(bounty.Status, domain.ActionSubmit) {
+				return &domain.TransitionError{From: bounty.Status, Action: domain.ActionSubmit}
+			}
+		}
+
+		now := time.Now()
+		submission.Status = status
+		submission.ReviewedBy = &currentUser
+		submission.ReviewedAt = &now
+		if reason != "" {
+			submission.ReviewNote = &reason
+		}
+		if err := tx.Submissions().Save(ctx, submission); err != nil {
+			return err
+		}
+		if bounty.Status == domain.StatusOpen {
+			// The next hunter starts over, so nothing else is left to review
+			return tx.Submissions().ObsoletePending(ctx, bounty.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		respondLifecycleError(c, err, "Failed to review submission")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"submission": submission,
+		"bounty":     bounty,
+	})
+}
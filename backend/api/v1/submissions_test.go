@@ -0,0 +1,187 @@
+package v1
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type reviewResponse struct {
+	Submission models.BountySubmission `json:"submission"`
+	Bounty     models.Bounty           `json:"bounty"`
+}
+
+const (
+	reviewCreator = "0x00000000000000000000000000000000000000c0"
+	reviewHunter  = "0x00000000000000000000000000000000000000b0"
+)
+
+// claimAndSubmit creates a bounty, has the hunter claim it and submit n times
+// and returns the bounty ID, its path and the submission IDs.
+func claimAndSubmit(t *testing.T, ts *testServer, creatorToken, hunterToken string, fields gin.H, n int) (uint, string, []uint) {
+	t.Helper()
+
+	id := ts.createBountyWith(t, creatorToken, fields)
+	path := "/api/v1/bounties/" + strconv.FormatUint(uint64(id), 10)
+	if code := ts.do(t, http.MethodPost, path+"/claim", hunterToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("claim: status %d", code)
+	}
+
+	ids := make([]uint, n)
+	for i := range ids {
+		var submission models.BountySubmission
+		if code := ts.do(t, http.MethodPost, path+"/submit", hunterToken, gin.H{"content": "Fixed"}, &submission); code != http.StatusCreated {
+			t.Fatalf("submit: status %d", code)
+		}
+		ids[i] = submission.ID
+	}
+	return id, path, ids
+}
+
+func submissionPath(path string, id uint) string {
+	return path + "/submissions/" + strconv.FormatUint(uint64(id), 10)
+}
+
+func TestAcceptSubmission(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, reviewCreator)
+	hunterToken := ts.login(t, reviewHunter)
+	_, path, ids := claimAndSubmit(t, ts, creatorToken, hunterToken, gin.H{"blockchain_id": 1}, 1)
+
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/accept", hunterToken, nil, nil); code != http.StatusForbidden {
+		t.Fatalf("accept by hunter: status %d, want 403", code)
+	}
+
+	var resp reviewResponse
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/accept", creatorToken, nil, &resp); code != http.StatusOK {
+		t.Fatalf("accept: status %d", code)
+	}
+	if resp.Submission.Status != domain.SubmissionAccepted || resp.Submission.ReviewedBy == nil || *resp.Submission.ReviewedBy != reviewCreator {
+		t.Fatalf("submission = %+v, want accepted by the creator", resp.Submission)
+	}
+	if resp.Bounty.Status != domain.StatusCompleted {
+		t.Fatalf("bounty status = %s, want %s", resp.Bounty.Status, domain.StatusCompleted)
+	}
+
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/accept", creatorToken, nil, nil); code != http.StatusConflict {
+		t.Fatalf("second accept: status %d, want 409", code)
+	}
+}
+
+func TestRejectSubmissionReopensBounty(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, reviewCreator)
+	hunterToken := ts.login(t, reviewHunter)
+	_, path, ids := claimAndSubmit(t, ts, creatorToken, hunterToken, gin.H{"blockchain_id": 1, "reopen_on_reject": true}, 2)
+
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/reject", creatorToken, gin.H{}, nil); code != http.StatusBadRequest {
+		t.Fatalf("reject without a reason: status %d, want 400", code)
+	}
+
+	var resp reviewResponse
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/reject", creatorToken, gin.H{"reason": "Still crashes"}, &resp); code != http.StatusOK {
+		t.Fatalf("reject: status %d", code)
+	}
+	if resp.Submission.Status != domain.SubmissionRejected || resp.Submission.ReviewNote == nil || *resp.Submission.ReviewNote != "Still crashes" {
+		t.Fatalf("submission = %+v, want rejected with the reason", resp.Submission)
+	}
+	if resp.Bounty.Status != domain.StatusOpen || resp.Bounty.HunterID != nil {
+		t.Fatalf("bounty = %+v, want open without a hunter", resp.Bounty)
+	}
+
+	other, err := ts.store.Submissions().Get(context.Background(), resp.Bounty.ID, ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Status != domain.SubmissionObsolete {
+		t.Fatalf("other submission status = %s, want %s", other.Status, domain.SubmissionObsolete)
+	}
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[1])+"/accept", creatorToken, nil, nil); code != http.StatusConflict {
+		t.Fatalf("accept obsolete submission: status %d, want 409", code)
+	}
+
+	// The next hunter can claim the reopened bounty
+	if code := ts.do(t, http.MethodPost, path+"/claim", ts.login(t, "0x00000000000000000000000000000000000000b1"), nil, nil); code != http.StatusOK {
+		t.Fatalf("claim reopened bounty: status %d", code)
+	}
+}
+
+func TestRejectSubmissionKeepsHunter(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, reviewCreator)
+	hunterToken := ts.login(t, reviewHunter)
+	_, path, ids := claimAndSubmit(t, ts, creatorToken, hunterToken, gin.H{"blockchain_id": 1}, 2)
+
+	var resp reviewResponse
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/reject", creatorToken, gin.H{"reason": "Still crashes"}, &resp); code != http.StatusOK {
+		t.Fatalf("reject: status %d", code)
+	}
+	if resp.Bounty.Status != domain.StatusClaimed || resp.Bounty.HunterID == nil || *resp.Bounty.HunterID != reviewHunter {
+		t.Fatalf("bounty = %+v, want claimed by the hunter", resp.Bounty)
+	}
+
+	other, err := ts.store.Submissions().Get(context.Background(), resp.Bounty.ID, ids[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other.Status != domain.SubmissionPending {
+		t.Fatalf("other submission status = %s, want %s", other.Status, domain.SubmissionPending)
+	}
+}
+
+func TestRequestSubmissionChanges(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, reviewCreator)
+	hunterToken := ts.login(t, reviewHunter)
+	_, path, ids := claimAndSubmit(t, ts, creatorToken, hunterToken, gin.H{"blockchain_id": 1, "reopen_on_reject": true}, 1)
+
+	var resp reviewResponse
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/request-changes", creatorToken, gin.H{"reason": "Add a test"}, &resp); code != http.StatusOK {
+		t.Fatalf("request changes: status %d", code)
+	}
+	if resp.Submission.Status != domain.SubmissionChangesRequested {
+		t.Fatalf("submission status = %s, want %s", resp.Submission.Status, domain.SubmissionChangesRequested)
+	}
+	// Requesting changes never reopens the bounty
+	if resp.Bounty.Status != domain.StatusClaimed || resp.Bounty.HunterID == nil || *resp.Bounty.HunterID != reviewHunter {
+		t.Fatalf("bounty = %+v, want claimed by the hunter", resp.Bounty)
+	}
+
+	if code := ts.do(t, http.MethodPost, path+"/submit", hunterToken, gin.H{"content": "Fixed, with a test"}, nil); code != http.StatusCreated {
+		t.Fatalf("resubmit: status %d", code)
+	}
+}
+
+func TestReviewEarlierHuntersSubmission(t *testing.T) {
+	ts := newTestServer(t)
+	creatorToken := ts.login(t, reviewCreator)
+	hunterToken := ts.login(t, reviewHunter)
+	id, path, ids := claimAndSubmit(t, ts, creatorToken, hunterToken, gin.H{"blockchain_id": 1}, 1)
+
+	// A pending submission left by someone who no longer holds the bounty
+	stale := models.BountySubmission{
+		BountyID: id,
+		HunterID: "0x00000000000000000000000000000000000000b1",
+		Content:  "Fixed earlier",
+		Status:   domain.SubmissionPending,
+	}
+	if err := ts.store.Submissions().Create(context.Background(), &stale); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, decision := range []string{"accept", "reject", "request-changes"} {
+		code := ts.do(t, http.MethodPost, submissionPath(path, stale.ID)+"/"+decision, creatorToken, gin.H{"reason": "Late"}, nil)
+		if code != http.StatusConflict {
+			t.Errorf("%s: status %d, want 409", decision, code)
+		}
+	}
+
+	if code := ts.do(t, http.MethodPost, submissionPath(path, ids[0])+"/accept", creatorToken, nil, nil); code != http.StatusOK {
+		t.Fatalf("accept current hunter's submission: status %d", code)
+	}
+}
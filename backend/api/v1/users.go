@@ -1,35 +1,36 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 
-	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/middleware"
 	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/pkg/sealed"
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterUserRoutes(router *gin.RouterGroup) {
+func (s *Server) RegisterUserRoutes(router *gin.RouterGroup) {
 	users := router.Group("/users")
 	{
-		users.POST("", createUser)
-		users.GET("/:id", getUser)
-		users.PUT("/:id", updateUser)
+		users.POST("", s.createUser)
+		users.GET("/:id", s.getUser)
+		users.PUT("/:id", s.updateUser)
+		users.GET("/:id/encryption-key", s.getEncryptionKey)
+		users.PUT("/:id/encryption-key", s.setEncryptionKey)
 	}
 }
 
-func createUser(c *gin.Context) {
+func (s *Server) createUser(c *gin.Context) {
 	var user models.User
 	if err := c.ShouldBindJSON(&user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Start transaction
-	tx := database.DB.Begin()
-	if tx.Error != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start transaction"})
-		return
-	}
+	// Encryption keys are validated and set through their own endpoint
+	user.EncryptionPublicKey = nil
 
 	// Create initial reputation for the user
 	reputation := models.Reputation{
@@ -39,21 +40,18 @@ func createUser(c *gin.Context) {
 	}
 
 	// Create user with associated reputation
-	if err := tx.Create(&user).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
-		return
-	}
-
-	if err := tx.Create(&reputation).Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create reputation"})
+	err := s.store.Transaction(c.Request.Context(), func(tx repository.Store) error {
+		if err := tx.Users().Create(c.Request.Context(), &user); err != nil {
+			return err
+		}
+		return tx.Reputations().Create(c.Request.Context(), &reputation)
+	})
+	if errors.Is(err, repository.ErrDuplicate) {
+		c.JSON(http.StatusConflict, gin.H{"error": "User already exists"})
 		return
 	}
-
-	if err := tx.Commit().Error; err != nil {
-		tx.Rollback()
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to commit transaction"})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
@@ -63,15 +61,11 @@ func createUser(c *gin.Context) {
 	})
 }
 
-func getUser(c *gin.Context) {
+func (s *Server) getUser(c *gin.Context) {
 	id := c.Param("id")
 
-	var user models.User
-	if err := database.DB.Preload("Reputation").
-		Preload("Reputation.Badges").
-		Preload("CreatedBounties").
-		Preload("HuntedBounties").
-		First(&user, "id = ?", id).Error; err != nil {
+	user, err := s.store.Users().GetProfile(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
@@ -79,21 +73,23 @@ func getUser(c *gin.Context) {
 	c.JSON(http.StatusOK, user)
 }
 
-func updateUser(c *gin.Context) {
+func (s *Server) updateUser(c *gin.Context) {
 	id := c.Param("id")
 
-	var user models.User
-	if err := database.DB.First(&user, "id = ?", id).Error; err != nil {
+	user, err := s.store.Users().Get(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
 	}
 
-	if err := c.ShouldBindJSON(&user); err != nil {
+	encryptionKey := user.EncryptionPublicKey
+	if err := c.ShouldBindJSON(user); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	user.EncryptionPublicKey = encryptionKey
 
-	if err := database.DB.Save(&user).Error; err != nil {
+	if err := s.store.Users().Save(c.Request.Context(), user); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
 		return
 	}
@@ -103,3 +99,64 @@ func updateUser(c *gin.Context) {
 		"message": "User updated successfully",
 	})
 }
+
+type EncryptionKeyRequest struct {
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+// getEncryptionKey returns the X25519 public key private submissions to the
+// user are encrypted to.
+func (s *Server) getEncryptionKey(c *gin.Context) {
+	user, err := s.store.Users().Get(c.Request.Context(), strings.ToLower(c.Param("id")))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	if user.EncryptionPublicKey == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User has no encryption key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    user.ID,
+		"public_key": *user.EncryptionPublicKey,
+	})
+}
+
+// setEncryptionKey registers the caller's public key. Submissions already
+// encrypted to a previous key stay readable only with that key.
+func (s *Server) setEncryptionKey(c *gin.Context) {
+	id := strings.ToLower(c.Param("id"))
+	if !strings.EqualFold(id, middleware.CurrentUserID(c)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only set your own encryption key"})
+		return
+	}
+
+	var req EncryptionKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	key, err := sealed.ParsePublicKey(req.PublicKey)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "public_key must be a base64 X25519 public key"})
+		return
+	}
+
+	user, err := s.store.Users().Get(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	encoded := sealed.EncodePublicKey(key)
+	user.EncryptionPublicKey = &encoded
+	if err := s.store.Users().Save(c.Request.Context(), user); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save encryption key"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":    user.ID,
+		"public_key": encoded,
+	})
+}
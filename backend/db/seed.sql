@@ -1,10 +1,15 @@
 -- Seed data for BountyBoard application
 -- Generated on 2025-01-07
+-- Run `migrate up` first; this file only loads data into the migrated schema.
 
 -- Clear existing data
+TRUNCATE TABLE notifications CASCADE;
+TRUNCATE TABLE user_roles CASCADE;
+TRUNCATE TABLE bounty_status_history CASCADE;
 TRUNCATE TABLE bounty_submissions CASCADE;
 TRUNCATE TABLE bounties CASCADE;
 TRUNCATE TABLE badges CASCADE;
+TRUNCATE TABLE reputation_events CASCADE;
 TRUNCATE TABLE reputations CASCADE;
 TRUNCATE TABLE users CASCADE;
 
@@ -24,6 +29,18 @@ VALUES
   ('u3', 520, 5, '2025-01-06 17:26:43', '2025-01-06 17:26:43'),
   ('0x15b5bdf7a5e0305b9a4be413383c9b1500c8fcf2', 0, 1, '2025-01-07 02:46:32', '2025-01-07 02:46:32');
 
+-- Ledger events behind the seeded scores
+INSERT INTO reputation_events (user_id, delta, reason, created_at)
+VALUES
+  ('u1', 350, 'opening_balance', '2025-01-06 17:26:43'),
+  ('u2', 180, 'opening_balance', '2025-01-06 17:26:43'),
+  ('u3', 520, 'opening_balance', '2025-01-06 17:26:43');
+
+-- Insert Roles
+INSERT INTO user_roles (user_id, role, granted_by, created_at)
+VALUES 
+  ('0x15b5bdf7a5e0305b9a4be413383c9b1500c8fcf2', 'admin', 'seed', '2025-01-07 02:46:32');
+
 -- Insert Badges
 INSERT INTO badges (user_id, name, description, token_uri, tx_hash, created_at)
 VALUES 
@@ -68,7 +85,7 @@ VALUES
     '2.8', -- 2.8 ETH
     'u3',
     'u1',
-    'in_progress',
+    'claimed',
     '2025-02-20 17:26:43',
     '2025-01-06 17:26:43',
     '2025-01-06 17:26:43',
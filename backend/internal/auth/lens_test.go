@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testLensOwner = "0x00000000000000000000000000000000000000A1"
+
+// fakeLensToken builds an unsigned token with the claims Lens puts in its
+// access tokens.
+func fakeLensToken(t *testing.T, profileID, owner string, exp time.Time) string {
+	t.Helper()
+	payload, err := json.Marshal(map[string]interface{}{
+		"id":         profileID,
+		"evmAddress": owner,
+		"exp":        exp.Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "e30." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+// lensAPI serves a Lens verify endpoint that answers with response.
+func lensAPI(t *testing.T, status int, response string) (*LensAPIVerifier, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+	return &LensAPIVerifier{endpoint: srv.URL, client: srv.Client()}, &calls
+}
+
+func TestLensAPIVerifier(t *testing.T) {
+	ctx := context.Background()
+	valid := fakeLensToken(t, "0x01", testLensOwner, time.Now().Add(time.Hour))
+
+	t.Run("valid", func(t *testing.T) {
+		verifier, _ := lensAPI(t, http.StatusOK, `{"data":{"verify":true}}`)
+		identity, err := verifier.Verify(ctx, valid)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if identity.ProfileID != "0x01" || identity.OwnedBy.Hex() != testLensOwner {
+			t.Fatalf("identity = %+v", identity)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		verifier, calls := lensAPI(t, http.StatusOK, `{"data":{"verify":true}}`)
+		expired := fakeLensToken(t, "0x01", testLensOwner, time.Now().Add(-time.Minute))
+		if _, err := verifier.Verify(ctx, expired); !errors.Is(err, ErrInvalidLensToken) {
+			t.Fatalf("err = %v, want ErrInvalidLensToken", err)
+		}
+		if *calls != 0 {
+			t.Fatal("expired token was sent to the Lens API")
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		verifier, _ := lensAPI(t, http.StatusOK, `{"data":{"verify":true}}`)
+		for _, token := range []string{"", "a.b", fakeLensToken(t, "", testLensOwner, time.Now().Add(time.Hour)), fakeLensToken(t, "0x01", "nope", time.Now().Add(time.Hour))} {
+			if _, err := verifier.Verify(ctx, token); !errors.Is(err, ErrInvalidLensToken) {
+				t.Errorf("Verify(%q): err = %v, want ErrInvalidLensToken", token, err)
+			}
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		verifier, _ := lensAPI(t, http.StatusOK, `{"data":{"verify":false}}`)
+		if _, err := verifier.Verify(ctx, valid); !errors.Is(err, ErrInvalidLensToken) {
+			t.Fatalf("err = %v, want ErrInvalidLensToken", err)
+		}
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		verifier, _ := lensAPI(t, http.StatusOK, `{"errors":[{"message":"boom"}]}`)
+		if _, err := verifier.Verify(ctx, valid); err == nil {
+			t.Fatal("expected an error")
+		}
+		verifier, _ = lensAPI(t, http.StatusBadGateway, ``)
+		if _, err := verifier.Verify(ctx, valid); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
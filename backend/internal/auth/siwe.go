@@ -0,0 +1,268 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+var (
+	ErrInvalidMessage   = errors.New("invalid SIWE message")
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrMessageExpired   = errors.New("SIWE message has expired")
+	ErrMessageNotYet    = errors.New("SIWE message is not yet valid")
+	ErrDomainMismatch   = errors.New("SIWE domain does not match")
+	ErrURIMismatch      = errors.New("SIWE URI does not match the domain")
+	ErrChainMismatch    = errors.New("SIWE chain ID does not match")
+)
+
+// SIWEConfig is what a Sign-In with Ethereum message must name to be
+// accepted by this server.
+type SIWEConfig struct {
+	// Domain is the host, with an optional port, that requests signatures.
+	Domain string
+	// ChainID is the chain the wallet must sign in on.
+	ChainID int64
+}
+
+var siweConfig SIWEConfig
+
+// InitSIWE loads the expected domain and chain ID from the environment. Both
+// are required so messages signed for another site or chain are rejected.
+func InitSIWE() {
+	domain := strings.TrimSpace(os.Getenv("SIWE_DOMAIN"))
+	if domain == "" {
+		log.Fatal("SIWE_DOMAIN environment variable is required")
+	}
+
+	chainID, err := strconv.ParseInt(os.Getenv("SIWE_CHAIN_ID"), 10, 64)
+	if err != nil || chainID <= 0 {
+		log.Fatal("SIWE_CHAIN_ID environment variable must be a positive chain ID")
+	}
+
+	siweConfig = SIWEConfig{Domain: domain, ChainID: chainID}
+}
+
+// GetSIWEConfig returns the domain and chain ID expected in SIWE messages.
+func GetSIWEConfig() SIWEConfig {
+	return siweConfig
+}
+
+// SetSIWEConfig replaces the expected domain and chain ID, e.g. in tests.
+func SetSIWEConfig(cfg SIWEConfig) {
+	siweConfig = cfg
+}
+
+// SIWEMessage is a parsed EIP-4361 Sign-In with Ethereum message.
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// ParseSIWEMessage parses the plain-text form of an EIP-4361 message.
+func ParseSIWEMessage(raw string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(raw, "\r\n", "\n"), "\n")
+	if len(lines) < 5 {
+		return nil, fmt.Errorf("%w: message is too short", ErrInvalidMessage)
+	}
+
+	if !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, fmt.Errorf("%w: missing header", ErrInvalidMessage)
+	}
+	msg := &SIWEMessage{Domain: strings.TrimSuffix(lines[0], siweHeaderSuffix)}
+	if i := strings.Index(msg.Domain, "://"); i >= 0 {
+		msg.Domain = msg.Domain[i+3:]
+	}
+	if msg.Domain == "" {
+		return nil, fmt.Errorf("%w: missing domain", ErrInvalidMessage)
+	}
+
+	if !common.IsHexAddress(lines[1]) || !strings.HasPrefix(lines[1], "0x") {
+		return nil, fmt.Errorf("%w: invalid address", ErrInvalidMessage)
+	}
+	msg.Address = common.HexToAddress(lines[1])
+
+	if lines[2] != "" {
+		return nil, fmt.Errorf("%w: expected blank line after address", ErrInvalidMessage)
+	}
+
+	// The statement is optional; when present it is followed by a blank line.
+	next := 4
+	if lines[3] != "" {
+		msg.Statement = lines[3]
+		if lines[4] != "" {
+			return nil, fmt.Errorf("%w: expected blank line after statement", ErrInvalidMessage)
+		}
+		next = 5
+	}
+
+	var err error
+	seen := map[string]bool{}
+	for i := next; i < len(lines); i++ {
+		line := lines[i]
+		if line == "" && i == len(lines)-1 {
+			break
+		}
+
+		if line == "Resources:" {
+			for i+1 < len(lines) && strings.HasPrefix(lines[i+1], "- ") {
+				i++
+				msg.Resources = append(msg.Resources, strings.TrimPrefix(lines[i], "- "))
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			return nil, fmt.Errorf("%w: malformed line %q", ErrInvalidMessage, line)
+		}
+		seen[key] = true
+
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			msg.ChainID, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid chain ID", ErrInvalidMessage)
+			}
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			msg.IssuedAt, err = time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid issued-at time", ErrInvalidMessage)
+			}
+		case "Expiration Time":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid expiration time", ErrInvalidMessage)
+			}
+			msg.ExpirationTime = &t
+		case "Not Before":
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: invalid not-before time", ErrInvalidMessage)
+			}
+			msg.NotBefore = &t
+		case "Request ID":
+			msg.RequestID = value
+		default:
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidMessage, key)
+		}
+	}
+
+	for _, required := range []string{"URI", "Version", "Chain ID", "Nonce", "Issued At"} {
+		if !seen[required] {
+			return nil, fmt.Errorf("%w: missing %s", ErrInvalidMessage, required)
+		}
+	}
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("%w: unsupported version %q", ErrInvalidMessage, msg.Version)
+	}
+	if len(msg.Nonce) < 8 {
+		return nil, fmt.Errorf("%w: nonce is too short", ErrInvalidMessage)
+	}
+
+	return msg, nil
+}
+
+// Validate checks the message against the expected domain, URI and chain and
+// the current time. The URI must be an absolute URL on the expected domain.
+func (m *SIWEMessage) Validate(cfg SIWEConfig, now time.Time) error {
+	if cfg.Domain == "" || !strings.EqualFold(m.Domain, cfg.Domain) {
+		return ErrDomainMismatch
+	}
+	uri, err := url.Parse(m.URI)
+	if err != nil || uri.Scheme == "" || !strings.EqualFold(uri.Host, cfg.Domain) {
+		return ErrURIMismatch
+	}
+	if m.ChainID != cfg.ChainID {
+		return ErrChainMismatch
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return ErrMessageExpired
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return ErrMessageNotYet
+	}
+	return nil
+}
+
+// RecoverAddress returns the address that produced an EIP-191 personal_sign
+// signature over message.
+func RecoverAddress(message, signature string) (common.Address, error) {
+	sig, err := hexutil.Decode(signature)
+	if err != nil || len(sig) != crypto.SignatureLength {
+		return common.Address{}, ErrInvalidSignature
+	}
+
+	// Wallets return v as 27/28; go-ethereum expects 0/1.
+	if sig[crypto.RecoveryIDOffset] >= 27 {
+		sig[crypto.RecoveryIDOffset] -= 27
+	}
+
+	pub, err := crypto.SigToPub(hashPersonalMessage(message), sig)
+	if err != nil {
+		return common.Address{}, ErrInvalidSignature
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// VerifySignature checks that the message was signed by the address it names.
+func (m *SIWEMessage) VerifySignature(raw, signature string) error {
+	signer, err := RecoverAddress(raw, signature)
+	if err != nil {
+		return err
+	}
+	if signer != m.Address {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func hashPersonalMessage(message string) []byte {
+	prefixed := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	return crypto.Keccak256([]byte(prefixed))
+}
+
+const nonceAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// GenerateNonce returns a random alphanumeric nonce as required by EIP-4361.
+func GenerateNonce() (string, error) {
+	b := make([]byte, 17)
+	max := big.NewInt(int64(len(nonceAlphabet)))
+	for i := range b {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b[i] = nonceAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testSIWEConfig = SIWEConfig{Domain: "bounties.example", ChainID: 37111}
+
+func siweMessage(domain, address, uri string, chainID string, expires time.Time) string {
+	return strings.Join([]string{
+		domain + " wants you to sign in with your Ethereum account:",
+		address,
+		"",
+		"Sign in to BountyBoard.",
+		"",
+		"URI: " + uri,
+		"Version: 1",
+		"Chain ID: " + chainID,
+		"Nonce: abcdefgh12345678",
+		"Issued At: 2026-01-01T00:00:00.000Z",
+		"Expiration Time: " + expires.UTC().Format(time.RFC3339),
+	}, "\n")
+}
+
+func TestSIWEValidate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 1, 0, 0, time.UTC)
+	const address = "0x00000000000000000000000000000000000000A1"
+	expires := now.Add(time.Hour)
+
+	tests := []struct {
+		name    string
+		message string
+		want    error
+	}{
+		{"valid", siweMessage("bounties.example", address, "https://bounties.example/login", "37111", expires), nil},
+		{"other domain", siweMessage("evil.example", address, "https://evil.example", "37111", expires), ErrDomainMismatch},
+		{"URI on another host", siweMessage("bounties.example", address, "https://evil.example", "37111", expires), ErrURIMismatch},
+		{"relative URI", siweMessage("bounties.example", address, "/login", "37111", expires), ErrURIMismatch},
+		{"other chain", siweMessage("bounties.example", address, "https://bounties.example", "1", expires), ErrChainMismatch},
+		{"expired", siweMessage("bounties.example", address, "https://bounties.example", "37111", now.Add(-time.Second)), ErrMessageExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseSIWEMessage(tt.message)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := msg.Validate(testSIWEConfig, now); !errors.Is(err, tt.want) {
+				t.Fatalf("Validate = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	msg, err := ParseSIWEMessage(siweMessage("bounties.example", address, "https://bounties.example", "37111", expires))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.Validate(SIWEConfig{ChainID: 37111}, now); !errors.Is(err, ErrDomainMismatch) {
+		t.Fatalf("empty domain: Validate = %v, want ErrDomainMismatch", err)
+	}
+}
+
+func TestSIWEVerifySignature(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	raw := siweMessage("bounties.example", address, "https://bounties.example", "37111", time.Now().Add(time.Hour))
+
+	sig, err := crypto.Sign(hashPersonalMessage(raw), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig[crypto.RecoveryIDOffset] += 27 // as returned by wallets
+
+	msg, err := ParseSIWEMessage(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := msg.VerifySignature(raw, hexutil.Encode(sig)); err != nil {
+		t.Fatalf("VerifySignature = %v", err)
+	}
+	tampered := strings.Replace(raw, "Chain ID: 37111", "Chain ID: 1", 1)
+	if err := msg.VerifySignature(tampered, hexutil.Encode(sig)); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("tampered message: VerifySignature = %v, want ErrInvalidSignature", err)
+	}
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const tokenIssuer = "bountyboard"
+
+var (
+	ErrInvalidToken = errors.New("invalid or expired token")
+
+	signingKey      []byte
+	AccessTokenTTL  = 15 * time.Minute
+	RefreshTokenTTL = 30 * 24 * time.Hour
+)
+
+// Claims are carried by every access token and placed in the gin context by
+// the auth middleware once the token has been verified.
+type Claims struct {
+	Address   string `json:"addr"`
+	Provider  string `json:"prv"`
+	ProfileID string `json:"lens,omitempty"`
+	SessionID uint   `json:"sid"`
+	jwt.RegisteredClaims
+}
+
+// UserID returns the ID of the authenticated user.
+func (c *Claims) UserID() string {
+	return c.Subject
+}
+
+// InitTokens loads the signing key and token lifetimes from the environment.
+func InitTokens() {
+	secret := os.Getenv("JWT_SECRET")
+	if len(secret) < 32 {
+		log.Fatal("JWT_SECRET environment variable must be at least 32 characters")
+	}
+	signingKey = []byte(secret)
+
+	if ttl := os.Getenv("ACCESS_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatal("Invalid ACCESS_TOKEN_TTL: ", err)
+		}
+		AccessTokenTTL = d
+	}
+	if ttl := os.Getenv("REFRESH_TOKEN_TTL"); ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil {
+			log.Fatal("Invalid REFRESH_TOKEN_TTL: ", err)
+		}
+		RefreshTokenTTL = d
+	}
+}
+
+// IssueAccessToken signs a short-lived access token for the given user.
+func IssueAccessToken(userID string, claims Claims, now time.Time) (string, time.Time, error) {
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := now.Add(AccessTokenTTL)
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    tokenIssuer,
+		Subject:   userID,
+		ID:        jti,
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(signingKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ParseAccessToken verifies the signature and lifetime of an access token.
+func ParseAccessToken(token string) (*Claims, error) {
+	var claims Claims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return signingKey, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(tokenIssuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" || claims.SessionID == 0 {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// NewRefreshToken returns a random refresh token and the hash to persist for it.
+func NewRefreshToken() (token string, hash string, err error) {
+	token, err = randomHex(32)
+	if err != nil {
+		return "", "", err
+	}
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hex-encoded SHA-256 digest of an opaque token.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,182 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// bountyBoardABI covers the events and view functions of the BountyBoard
+// contract that the backend consumes.
+const bountyBoardABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"bountyId","type":"uint256"},{"indexed":true,"name":"creator","type":"address"},{"indexed":false,"name":"reward","type":"uint256"}],"name":"BountyCreated","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"bountyId","type":"uint256"},{"indexed":true,"name":"hunter","type":"address"}],"name":"BountyClaimed","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"bountyId","type":"uint256"},{"indexed":true,"name":"hunter","type":"address"}],"name":"BountyCompleted","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"bountyId","type":"uint256"},{"indexed":true,"name":"raiser","type":"address"},{"indexed":false,"name":"reason","type":"string"}],"name":"DisputeRaised","type":"event"},
+{"anonymous":false,"inputs":[{"indexed":true,"name":"bountyId","type":"uint256"},{"indexed":true,"name":"winner","type":"address"},{"indexed":false,"name":"resolution","type":"string"}],"name":"DisputeResolved","type":"event"},
+{"inputs":[{"name":"_reward","type":"uint256"},{"name":"_metadata","type":"string"}],"name":"createBounty","outputs":[],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"_bountyId","type":"uint256"}],"name":"getBounty","outputs":[{"name":"id","type":"uint256"},{"name":"creator","type":"address"},{"name":"reward","type":"uint256"},{"name":"status","type":"uint8"},{"name":"hunter","type":"address"},{"name":"metadata","type":"string"},{"name":"disputeReason","type":"string"}],"stateMutability":"view","type":"function"}
+]`
+
+var ErrUnknownEvent = errors.New("unknown BountyBoard event")
+
+// BountyCreated is emitted when a bounty is funded on-chain.
+type BountyCreated struct {
+	BountyID *big.Int
+	Creator  common.Address
+	Reward   *big.Int
+}
+
+// BountyClaimed is emitted when a hunter claims a bounty.
+type BountyClaimed struct {
+	BountyID *big.Int
+	Hunter   common.Address
+}
+
+// BountyCompleted is emitted when the creator releases the reward.
+type BountyCompleted struct {
+	BountyID *big.Int
+	Hunter   common.Address
+}
+
+// DisputeRaised is emitted when the creator disputes a claimed bounty.
+type DisputeRaised struct {
+	BountyID *big.Int
+	Raiser   common.Address
+	Reason   string
+}
+
+// DisputeResolved is emitted when the contract owner settles a dispute.
+type DisputeResolved struct {
+	BountyID   *big.Int
+	Winner     common.Address
+	Resolution string
+}
+
+// OnChainBounty is the result of the contract's getBounty view.
+type OnChainBounty struct {
+	ID            *big.Int       `abi:"id"`
+	Creator       common.Address `abi:"creator"`
+	Reward        *big.Int       `abi:"reward"`
+	Status        uint8          `abi:"status"`
+	Hunter        common.Address `abi:"hunter"`
+	Metadata      string         `abi:"metadata"`
+	DisputeReason string         `abi:"disputeReason"`
+}
+
+// BountyBoard decodes logs from and calls views on a deployed BountyBoard contract.
+type BountyBoard struct {
+	Address common.Address
+	abi     abi.ABI
+}
+
+func NewBountyBoard(address common.Address) (*BountyBoard, error) {
+	parsed, err := abi.JSON(strings.NewReader(bountyBoardABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse BountyBoard ABI: %w", err)
+	}
+	return &BountyBoard{Address: address, abi: parsed}, nil
+}
+
+// EventTopics returns the topic0 values of every event the backend consumes.
+func (b *BountyBoard) EventTopics() []common.Hash {
+	names := []string{"BountyCreated", "BountyClaimed", "BountyCompleted", "DisputeRaised", "DisputeResolved"}
+	topics := make([]common.Hash, len(names))
+	for i, name := range names {
+		topics[i] = b.abi.Events[name].ID
+	}
+	return topics
+}
+
+// FilterQuery returns a log filter for the consumed events in [from, to].
+func (b *BountyBoard) FilterQuery(from, to uint64) ethereum.FilterQuery {
+	return ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: []common.Address{b.Address},
+		Topics:    [][]common.Hash{b.EventTopics()},
+	}
+}
+
+// ParseLog decodes a log into one of the event types in this package.
+func (b *BountyBoard) ParseLog(log types.Log) (interface{}, error) {
+	if log.Address != b.Address || len(log.Topics) == 0 {
+		return nil, ErrUnknownEvent
+	}
+
+	event, err := b.abi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, ErrUnknownEvent
+	}
+
+	values := map[string]interface{}{}
+	if len(log.Data) > 0 {
+		if err := b.abi.UnpackIntoMap(values, event.Name, log.Data); err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %w", event.Name, err)
+		}
+	}
+
+	var indexed abi.Arguments
+	for _, arg := range event.Inputs {
+		if arg.Indexed {
+			indexed = append(indexed, arg)
+		}
+	}
+	if err := abi.ParseTopicsIntoMap(values, indexed, log.Topics[1:]); err != nil {
+		return nil, fmt.Errorf("failed to decode %s topics: %w", event.Name, err)
+	}
+
+	id, _ := values["bountyId"].(*big.Int)
+	if id == nil {
+		return nil, fmt.Errorf("%s is missing bountyId", event.Name)
+	}
+
+	switch event.Name {
+	case "BountyCreated":
+		reward, _ := values["reward"].(*big.Int)
+		creator, _ := values["creator"].(common.Address)
+		return &BountyCreated{BountyID: id, Creator: creator, Reward: reward}, nil
+	case "BountyClaimed":
+		hunter, _ := values["hunter"].(common.Address)
+		return &BountyClaimed{BountyID: id, Hunter: hunter}, nil
+	case "BountyCompleted":
+		hunter, _ := values["hunter"].(common.Address)
+		return &BountyCompleted{BountyID: id, Hunter: hunter}, nil
+	case "DisputeRaised":
+		raiser, _ := values["raiser"].(common.Address)
+		reason, _ := values["reason"].(string)
+		return &DisputeRaised{BountyID: id, Raiser: raiser, Reason: reason}, nil
+	case "DisputeResolved":
+		winner, _ := values["winner"].(common.Address)
+		resolution, _ := values["resolution"].(string)
+		return &DisputeResolved{BountyID: id, Winner: winner, Resolution: resolution}, nil
+	}
+	return nil, ErrUnknownEvent
+}
+
+// GetBounty calls the contract's getBounty view at the given block, or at the
+// latest block if blockNumber is nil.
+func (b *BountyBoard) GetBounty(ctx context.Context, caller ethereum.ContractCaller, id *big.Int, blockNumber *big.Int) (*OnChainBounty, error) {
+	input, err := b.abi.Pack("getBounty", id)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{To: &b.Address, Data: input}, blockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("getBounty call failed: %w", err)
+	}
+
+	var result OnChainBounty
+	if err := b.abi.UnpackIntoInterface(&result, "getBounty", output); err != nil {
+		return nil, fmt.Errorf("failed to decode getBounty: %w", err)
+	}
+	return &result, nil
+}
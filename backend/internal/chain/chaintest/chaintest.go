@@ -0,0 +1,203 @@
+// Package chaintest runs the chain-facing code against go-ethereum's
+// simulated backend. Solidity cannot be compiled in tests, so the contracts
+// are small hand-assembled stand-ins that emit whatever logs a test asks for.
+package chaintest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ChainID is the chain ID of every simulated backend.
+var ChainID = big.NewInt(1337)
+
+// Chain is a simulated chain with one funded account.
+type Chain struct {
+	*backends.SimulatedBackend
+	Key  *ecdsa.PrivateKey
+	From common.Address
+}
+
+// New starts a simulated chain with the given contracts deployed at genesis.
+func New(t testing.TB, contracts map[common.Address][]byte) *Chain {
+	t.Helper()
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+
+	alloc := types.GenesisAlloc{
+		from: {Balance: new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))},
+	}
+	for address, code := range contracts {
+		alloc[address] = types.Account{Code: code, Balance: new(big.Int)}
+	}
+
+	sim := backends.NewSimulatedBackend(alloc, 30_000_000)
+	t.Cleanup(func() { sim.Close() })
+	return &Chain{SimulatedBackend: sim, Key: key, From: from}
+}
+
+// Head returns the number of the latest block.
+func (c *Chain) Head(t testing.TB) uint64 {
+	t.Helper()
+	head, err := c.BlockNumber(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return head
+}
+
+// Mine commits n empty blocks.
+func (c *Chain) Mine(n int) {
+	for i := 0; i < n; i++ {
+		c.Commit()
+	}
+}
+
+// Send submits a transaction calling contract with data and returns it. It
+// is mined by the next Commit.
+func (c *Chain) Send(t testing.TB, contract common.Address, data []byte) *types.Transaction {
+	t.Helper()
+	return c.send(t, contract, data, nil)
+}
+
+// Replace submits a transaction with the nonce of orphan and a higher gas
+// price, so after a Fork the orphaned transaction cannot be mined again.
+func (c *Chain) Replace(t testing.TB, orphan *types.Transaction, contract common.Address, data []byte) *types.Transaction {
+	t.Helper()
+	return c.send(t, contract, data, orphan)
+}
+
+func (c *Chain) send(t testing.TB, contract common.Address, data []byte, replaces *types.Transaction) *types.Transaction {
+	t.Helper()
+
+	opts, err := bind.NewKeyedTransactorWithChainID(c.Key, ChainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replaces != nil {
+		opts.Nonce = new(big.Int).SetUint64(replaces.Nonce())
+		opts.GasFeeCap = new(big.Int).Mul(replaces.GasFeeCap(), big.NewInt(2))
+		opts.GasTipCap = new(big.Int).Mul(replaces.GasTipCap(), big.NewInt(2))
+	}
+	bound := bind.NewBoundContract(contract, abi.ABI{}, c, c, c)
+	tx, err := bound.RawTransact(opts, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+// Emit makes an emitter contract log topics and data. Every topic is a
+// 32-byte word, so addresses and IDs must be padded with AddressTopic and
+// IntTopic.
+func (c *Chain) Emit(t testing.TB, contract common.Address, topics [3]common.Hash, data []byte) *types.Transaction {
+	t.Helper()
+	return c.Send(t, contract, EmitData(topics, data))
+}
+
+// EmitData returns the calldata that makes an emitter contract log topics
+// and data.
+func EmitData(topics [3]common.Hash, data []byte) []byte {
+	calldata := make([]byte, 0, 3*32+len(data))
+	for _, topic := range topics {
+		calldata = append(calldata, topic.Bytes()...)
+	}
+	return append(calldata, data...)
+}
+
+// AddressTopic pads an address into an indexed event topic.
+func AddressTopic(address common.Address) common.Hash {
+	return common.BytesToHash(address.Bytes())
+}
+
+// IntTopic encodes a uint256 as an indexed event topic.
+func IntTopic(n int64) common.Hash {
+	return common.BigToHash(big.NewInt(n))
+}
+
+// EventID returns topic0 of an event with the given signature, e.g.
+// "BountyCreated(uint256,address,uint256)".
+func EventID(signature string) common.Hash {
+	return crypto.Keccak256Hash([]byte(signature))
+}
+
+// Selector returns the 4-byte selector of a function signature.
+func Selector(signature string) [4]byte {
+	var selector [4]byte
+	copy(selector[:], crypto.Keccak256([]byte(signature)))
+	return selector
+}
+
+// EmitterCode returns runtime bytecode for a contract that answers calls to
+// view with the constant ABI-encoded result and turns every other call into
+// LOG3, reading its three topics from the first 96 bytes of calldata and its
+// data from the rest.
+func EmitterCode(view [4]byte, result []byte) []byte {
+	const blobOffset = 0x36
+	size := make([]byte, 2)
+	binary.BigEndian.PutUint16(size, uint16(len(result)))
+	offset := []byte{0x00, blobOffset}
+
+	code := []byte{
+		// selector := calldata[0:4]; if selector == view { goto view }
+		0x60, 0x00, 0x35, 0x60, 0xe0, 0x1c,
+		0x63, view[0], view[1], view[2], view[3],
+		0x14, 0x60, 0x26, 0x57,
+		// LOG3(memory[0:size], calldata[0:32], calldata[32:64], calldata[64:96])
+		// with size := calldatasize - 96 copied from calldata[96:]
+		0x60, 0x40, 0x35, 0x60, 0x20, 0x35, 0x60, 0x00, 0x35,
+		0x60, 0x60, 0x36, 0x03, 0x80, 0x60, 0x60, 0x60, 0x00, 0x37,
+		0x60, 0x00, 0xa3, 0x00,
+		// view: return the result blob appended to the code
+		0x5b,
+		0x61, size[0], size[1], 0x61, offset[0], offset[1], 0x60, 0x00, 0x39,
+		0x61, size[0], size[1], 0x60, 0x00, 0xf3,
+	}
+	if len(code) != blobOffset {
+		panic("chaintest: emitter code layout changed")
+	}
+	return append(code, result...)
+}
+
+// MintCode returns runtime bytecode for a stand-in NFT contract. A call with
+// calldata logs event with the 32-byte argument at calldata[4:36] as its
+// first topic and a token ID counting up from 1 as its second, the way a
+// mint(address,...) function would. A call without calldata toggles whether
+// mints revert, so a test can make a mint fail after it was signed.
+func MintCode(event common.Hash) []byte {
+	code := []byte{
+		// if calldatasize == 0 { goto toggle }; if storage[0] != 0 { goto revert }
+		0x36, 0x15, 0x60, 0x45, 0x57,
+		0x60, 0x00, 0x54, 0x60, 0x3f, 0x57,
+		// id := storage[1] + 1; storage[1] = id
+		0x60, 0x01, 0x54, 0x60, 0x01, 0x01, 0x80, 0x60, 0x01, 0x55,
+		// LOG3(memory[0:0], event, calldata[4:36], id)
+		0x60, 0x04, 0x35, 0x7f,
+	}
+	code = append(code, event.Bytes()...)
+	code = append(code,
+		0x60, 0x00, 0x60, 0x00, 0xa3, 0x00,
+		// revert: REVERT(0, 0)
+		0x5b, 0x60, 0x00, 0x60, 0x00, 0xfd,
+		// toggle: storage[0] = !storage[0]
+		0x5b, 0x60, 0x00, 0x54, 0x15, 0x60, 0x00, 0x55, 0x00,
+	)
+	if code[0x3f] != 0x5b || code[0x45] != 0x5b {
+		panic("chaintest: mint code layout changed")
+	}
+	return code
+}
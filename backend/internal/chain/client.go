@@ -0,0 +1,77 @@
+package chain
+
+import (
+	"context"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// Backend is the subset of an Ethereum client the backend relies on. Both
+// *ethclient.Client and the go-ethereum simulated backend's client satisfy it.
+type Backend interface {
+	ethereum.BlockNumberReader
+	ethereum.LogFilterer
+	ethereum.ContractCaller
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// TxBackend is a Backend that can also send transactions.
+type TxBackend interface {
+	Backend
+	bind.ContractTransactor
+	ChainID(ctx context.Context) (*big.Int, error)
+	// NonceAt returns the nonce of account as of blockNumber, or the latest
+	// block if it is nil.
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+}
+
+var (
+	// Client is nil when no CHAIN_RPC_URL is configured.
+	Client Backend
+	// TxClient is the same connection as Client, for sending transactions.
+	TxClient TxBackend
+	// Board is the deployed BountyBoard contract at BOUNTY_BOARD_ADDRESS.
+	Board *BountyBoard
+)
+
+// InitChain connects to CHAIN_RPC_URL. Chain features are disabled with a
+// warning when it is unset.
+func InitChain() {
+	url := os.Getenv("CHAIN_RPC_URL")
+	if url == "" {
+		log.Printf("Warning: CHAIN_RPC_URL not set, chain features disabled")
+		return
+	}
+
+	address := os.Getenv("BOUNTY_BOARD_ADDRESS")
+	if !common.IsHexAddress(address) {
+		log.Fatal("BOUNTY_BOARD_ADDRESS environment variable must be a contract address")
+	}
+
+	client, err := ethclient.DialContext(context.Background(), url)
+	if err != nil {
+		log.Fatal("Failed to connect to chain: ", err)
+	}
+
+	board, err := NewBountyBoard(common.HexToAddress(address))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	Client = client
+	TxClient = client
+	Board = board
+}
+
+// Enabled reports whether a chain connection is configured.
+func Enabled() bool {
+	return Client != nil && Board != nil
+}
@@ -0,0 +1,69 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// reputationABI covers the badge minting function and event of the Reputation
+// contract.
+const reputationABI = `[
+{"anonymous":false,"inputs":[{"indexed":true,"name":"to","type":"address"},{"indexed":true,"name":"tokenId","type":"uint256"},{"indexed":false,"name":"tokenURI","type":"string"}],"name":"BadgeMinted","type":"event"},
+{"inputs":[{"name":"to","type":"address"},{"name":"uri","type":"string"}],"name":"mintBadge","outputs":[{"name":"","type":"uint256"}],"stateMutability":"nonpayable","type":"function"},
+{"inputs":[{"name":"tokenId","type":"uint256"}],"name":"tokenURI","outputs":[{"name":"","type":"string"}],"stateMutability":"view","type":"function"}
+]`
+
+var ErrBadgeNotMinted = errors.New("receipt has no BadgeMinted event")
+
+// ReputationContract builds badge mints for a deployed Reputation contract
+// and reads their results.
+type ReputationContract struct {
+	Address common.Address
+	abi     abi.ABI
+}
+
+func NewReputationContract(address common.Address) (*ReputationContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(reputationABI))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Reputation ABI: %w", err)
+	}
+	return &ReputationContract{Address: address, abi: parsed}, nil
+}
+
+// SignMint signs a mintBadge transaction without broadcasting it, so the
+// caller can record the transaction hash before it can be mined.
+func (r *ReputationContract) SignMint(ctx context.Context, backend bind.ContractTransactor, signer Signer, to common.Address, tokenURI string) (*types.Transaction, error) {
+	opts, err := signer.TransactOpts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts.NoSend = true
+
+	contract := bind.NewBoundContract(r.Address, r.abi, nil, backend, nil)
+	tx, err := contract.Transact(opts, "mintBadge", to, tokenURI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign mintBadge: %w", err)
+	}
+	return tx, nil
+}
+
+// MintedTokenID returns the token ID from the BadgeMinted event in a mint
+// receipt.
+func (r *ReputationContract) MintedTokenID(receipt *types.Receipt) (*big.Int, error) {
+	event := r.abi.Events["BadgeMinted"]
+	for _, l := range receipt.Logs {
+		if l.Address != r.Address || len(l.Topics) != 3 || l.Topics[0] != event.ID {
+			continue
+		}
+		return new(big.Int).SetBytes(l.Topics[2].Bytes()), nil
+	}
+	return nil, ErrBadgeNotMinted
+}
@@ -0,0 +1,56 @@
+package chain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs the transactions the backend sends, such as badge mints. The
+// key may live in memory or behind a remote signing service.
+type Signer interface {
+	Address() common.Address
+	// TransactOpts returns options that sign transactions from Address.
+	TransactOpts(ctx context.Context) (*bind.TransactOpts, error)
+}
+
+// KeySigner signs with a private key held in memory.
+type KeySigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+func NewKeySigner(key *ecdsa.PrivateKey, chainID *big.Int) *KeySigner {
+	return &KeySigner{
+		key:     key,
+		chainID: chainID,
+	}
+}
+
+// ParseKeySigner reads a hex private key, with or without a 0x prefix.
+func ParseKeySigner(hexKey string, chainID *big.Int) (*KeySigner, error) {
+	key, err := crypto.HexToECDSA(strings.TrimPrefix(hexKey, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key: %w", err)
+	}
+	return NewKeySigner(key, chainID), nil
+}
+
+func (s *KeySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *KeySigner) TransactOpts(ctx context.Context) (*bind.TransactOpts, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(s.key, s.chainID)
+	if err != nil {
+		return nil, err
+	}
+	opts.Context = ctx
+	return opts, nil
+}
@@ -0,0 +1,25 @@
+package clock
+
+import (
+	"time"
+)
+
+// Clock abstracts the current time so time-based logic can be tested with a
+// fixed or manually advanced clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is a Clock backed by time.Now.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Func adapts a plain function to the Clock interface.
+type Func func() time.Time
+
+func (f Func) Now() time.Time {
+	return f()
+}
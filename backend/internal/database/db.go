@@ -4,7 +4,6 @@ import (
 	"log"
 	"os"
 
-	"github.com/bountyBoard/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -18,21 +17,10 @@ func InitDB() {
 	}
 
 	var err error
-	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
+		TranslateError: true,
+	})
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
-
-	// Auto migrate the schema
-	err = DB.AutoMigrate(
-		&models.User{},
-		&models.Bounty{},
-		&models.BountySubmission{},
-		&models.Reputation{},
-		&models.Badge{},
-		&models.BountyComment{},
-	)
-	if err != nil {
-		log.Fatal("Failed to migrate database: ", err)
-	}
 }
@@ -0,0 +1,148 @@
+// Package dbtest opens throwaway databases with the application schema for
+// tests of code that talks to gorm directly.
+package dbtest
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/models"
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// PostgresEnv names the variable holding the DSN of a Postgres database for
+// tests that need real row locks or the SQL migrations.
+const PostgresEnv = "TEST_DATABASE_URL"
+
+// Open returns a SQLite database in a temporary directory with every model
+// migrated and foreign keys enforced. It is closed when the test ends.
+func Open(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := filepath.Join(t.TempDir(), "test.db") + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)"
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.AutoMigrate(Models()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// Models returns every model the application stores, in an order that
+// satisfies their foreign keys.
+func Models() []interface{} {
+	return []interface{}{
+		&models.User{},
+		&models.Reputation{},
+		&models.Badge{},
+		&models.BadgeMintJob{},
+		&models.ReputationEvent{},
+		&models.UserRole{},
+		&models.AuthNonce{},
+		&models.Session{},
+		&models.Category{},
+		&models.Tag{},
+		&models.Skill{},
+		&models.Bounty{},
+		&models.BountyStatusHistory{},
+		&models.BountySubmission{},
+		&models.SubmissionKeyEnvelope{},
+		&models.BountyComment{},
+		&models.BountyCommentRevision{},
+		&models.Notification{},
+		&models.IndexerCursor{},
+	}
+}
+
+// OpenPostgres returns a connection to a fresh, empty schema of the database
+// in TEST_DATABASE_URL, skipping the test if it is not set. The schema is
+// dropped when the test ends. Callers apply the migrations they need.
+func OpenPostgres(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(PostgresEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", PostgresEnv)
+	}
+	config := &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Default.LogMode(logger.Silent),
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if err := admin.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema)), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.Close()
+		}
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE")
+		if sqlDB, err := admin.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+	return db
+}
+
+// OpenPostgresMigrated is OpenPostgres with every embedded migration applied.
+func OpenPostgresMigrated(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	db := OpenPostgres(t)
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// withSearchPath makes every connection of dsn, in URL or keyword form, use
+// schema. The public schema stays on the path for extensions.
+func withSearchPath(dsn, schema string) string {
+	path := schema + ",public"
+	if strings.Contains(dsn, "://") {
+		if u, err := url.Parse(dsn); err == nil {
+			q := u.Query()
+			q.Set("search_path", path)
+			u.RawQuery = q.Encode()
+			return u.String()
+		}
+	}
+	return dsn + " search_path=" + path
+}
@@ -0,0 +1,101 @@
+package database_test
+
+import (
+	"testing"
+
+	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/database/dbtest"
+	"gorm.io/gorm"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := database.LoadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range migrations {
+		if m.Version != uint(i+1) {
+			t.Errorf("migration %d_%s: versions must count up from 1 without gaps", m.Version, m.Name)
+		}
+	}
+}
+
+// TestMigrationsMatchModels applies the embedded migrations to Postgres, the
+// way `migrate up` does in production, and checks that they are idempotent,
+// that they revert cleanly and that they create every table and column the
+// models use.
+func TestMigrationsMatchModels(t *testing.T) {
+	db := dbtest.OpenPostgres(t)
+	migrations, err := database.LoadMigrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	applied, err := migrator.Up(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("applied %d migrations, want %d", len(applied), len(migrations))
+	}
+	if err := database.CheckSchema(db); err != nil {
+		t.Fatal(err)
+	}
+
+	// Running them again applies nothing
+	applied, err = migrator.Up(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 0 {
+		t.Fatalf("second run applied %d migrations", len(applied))
+	}
+
+	// Every down script undoes its up script, so the full set applies again
+	if _, err := migrator.Down(len(migrations)); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := migrator.Pending(); err != nil || len(pending) != len(migrations) {
+		t.Fatalf("after reverting everything %d migrations are pending, want %d (%v)", len(pending), len(migrations), err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, model := range dbtest.Models() {
+		checkModelSchema(t, db, model)
+	}
+}
+
+// checkModelSchema reports tables and columns model needs that the migrations
+// did not create.
+func checkModelSchema(t *testing.T, db *gorm.DB, model interface{}) {
+	t.Helper()
+
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		t.Fatal(err)
+	}
+	table := stmt.Schema.Table
+	if !db.Migrator().HasTable(table) {
+		t.Errorf("table %s of %s is missing", table, stmt.Schema.Name)
+		return
+	}
+	for _, field := range stmt.Schema.Fields {
+		if field.DBName == "" {
+			continue
+		}
+		if !db.Migrator().HasColumn(model, field.DBName) {
+			t.Errorf("column %s.%s of %s.%s is missing", table, field.DBName, stmt.Schema.Name, field.Name)
+		}
+	}
+	for _, rel := range stmt.Schema.Relationships.Many2Many {
+		if !db.Migrator().HasTable(rel.JoinTable.Table) {
+			t.Errorf("join table %s of %s.%s is missing", rel.JoinTable.Table, stmt.Schema.Name, rel.Name)
+		}
+	}
+}
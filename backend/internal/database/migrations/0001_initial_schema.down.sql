@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS indexer_cursors;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS auth_nonces;
+DROP TABLE IF EXISTS bounty_comments;
+DROP TABLE IF EXISTS bounty_submissions;
+DROP TABLE IF EXISTS bounty_status_history;
+DROP TABLE IF EXISTS bounties;
+DROP TABLE IF EXISTS badges;
+DROP TABLE IF EXISTS reputations;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,153 @@
+-- Initial schema, equivalent to what AutoMigrate created before versioned
+-- migrations were introduced. Every statement is guarded so databases that
+-- were previously auto-migrated can adopt this migration in place.
+
+CREATE TABLE IF NOT EXISTS users (
+    id              text PRIMARY KEY,
+    username        text,
+    address         text,
+    lens_profile_id text,
+    bio             text,
+    avatar          text,
+    created_at      timestamptz,
+    updated_at      timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username) WHERE username IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_address ON users (address);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_lens_profile_id ON users (lens_profile_id) WHERE lens_profile_id IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS reputations (
+    id         bigserial PRIMARY KEY,
+    user_id    text,
+    score      bigint,
+    level      bigint,
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT fk_users_reputation FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reputations_user_id ON reputations (user_id);
+
+CREATE TABLE IF NOT EXISTS badges (
+    id          bigserial PRIMARY KEY,
+    user_id     text,
+    name        text,
+    description text,
+    token_uri   text,
+    tx_hash     text,
+    created_at  timestamptz,
+    CONSTRAINT fk_reputations_badges FOREIGN KEY (user_id) REFERENCES reputations (user_id)
+);
+
+CREATE TABLE IF NOT EXISTS bounties (
+    id                 bigserial PRIMARY KEY,
+    blockchain_id      bigint,
+    title              text,
+    description        text,
+    reward             decimal(32,18),
+    creator_id         text,
+    hunter_id          text,
+    status             text,
+    deadline           timestamptz,
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    tx_hash            text,
+    ipfs_hash          text,
+    dispute_reason     text,
+    dispute_winner     text,
+    dispute_resolution text,
+    resolved_at        timestamptz,
+    verified_at        timestamptz,
+    verification_error text,
+    reopen_on_reject   boolean,
+    CONSTRAINT fk_users_created_bounties FOREIGN KEY (creator_id) REFERENCES users (id),
+    CONSTRAINT fk_users_hunted_bounties FOREIGN KEY (hunter_id) REFERENCES users (id)
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bounties_blockchain_id ON bounties (blockchain_id);
+
+-- Bounties claimed before the status machine existed were marked in_progress
+UPDATE bounties SET status = 'claimed' WHERE status = 'in_progress';
+
+CREATE TABLE IF NOT EXISTS bounty_status_history (
+    id          bigserial PRIMARY KEY,
+    bounty_id   bigint,
+    from_status text,
+    to_status   text,
+    action      text,
+    actor_id    text,
+    created_at  timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_bounty_status_history_bounty_id ON bounty_status_history (bounty_id);
+
+CREATE TABLE IF NOT EXISTS bounty_submissions (
+    id          bigserial PRIMARY KEY,
+    bounty_id   bigint,
+    hunter_id   text,
+    content     text,
+    ipfs_hash   text,
+    status      text,
+    review_note text,
+    reviewed_by text,
+    reviewed_at timestamptz,
+    created_at  timestamptz,
+    updated_at  timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS bounty_comments (
+    id         bigserial PRIMARY KEY,
+    bounty_id  bigint,
+    user_id    text,
+    content    text,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS auth_nonces (
+    id         bigserial PRIMARY KEY,
+    nonce      text,
+    expires_at timestamptz,
+    used_at    timestamptz,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_nonces_nonce ON auth_nonces (nonce);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id                 bigserial PRIMARY KEY,
+    refresh_token_hash text,
+    user_id            text,
+    address            text,
+    provider           text,
+    profile_id         text,
+    expires_at         timestamptz,
+    last_used_at       timestamptz,
+    revoked_at         timestamptz,
+    created_at         timestamptz,
+    updated_at         timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sessions_refresh_token_hash ON sessions (refresh_token_hash);
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions (user_id);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    id         bigserial PRIMARY KEY,
+    user_id    text,
+    role       text,
+    granted_by text,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_roles_user_role ON user_roles (user_id, role);
+
+CREATE TABLE IF NOT EXISTS indexer_cursors (
+    name       text PRIMARY KEY,
+    next_block bigint,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         bigserial PRIMARY KEY,
+    user_id    text,
+    type       text,
+    bounty_id  bigint,
+    message    text,
+    read_at    timestamptz,
+    created_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications (user_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_bounty_submissions_bounty_created_at;
+DROP INDEX IF EXISTS idx_bounty_comments_bounty_created_at;
+DROP INDEX IF EXISTS idx_bounties_deadline_id;
+DROP INDEX IF EXISTS idx_bounties_reward_id;
+DROP INDEX IF EXISTS idx_bounties_created_at_id;
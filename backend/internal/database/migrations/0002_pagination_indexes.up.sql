@@ -0,0 +1,6 @@
+-- Keyset pagination orders by (sort column, id)
+CREATE INDEX idx_bounties_created_at_id ON bounties (created_at, id);
+CREATE INDEX idx_bounties_reward_id ON bounties (reward, id);
+CREATE INDEX idx_bounties_deadline_id ON bounties (deadline, id);
+CREATE INDEX idx_bounty_comments_bounty_created_at ON bounty_comments (bounty_id, created_at, id);
+CREATE INDEX idx_bounty_submissions_bounty_created_at ON bounty_submissions (bounty_id, created_at, id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_bounties_search_vector;
+ALTER TABLE bounties DROP COLUMN IF EXISTS search_vector;
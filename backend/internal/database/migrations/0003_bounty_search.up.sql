@@ -0,0 +1,9 @@
+-- Full-text search over bounty titles and descriptions. Title matches rank
+-- above description matches.
+ALTER TABLE bounties ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX idx_bounties_search_vector ON bounties USING GIN (search_vector);
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS bounty_skills;
+DROP TABLE IF EXISTS skills;
+DROP TABLE IF EXISTS bounty_tags;
+DROP TABLE IF EXISTS tags;
+ALTER TABLE bounties DROP COLUMN IF EXISTS category_id;
+DROP TABLE IF EXISTS categories;
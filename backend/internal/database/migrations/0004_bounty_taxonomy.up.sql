@@ -0,0 +1,51 @@
+-- Curated categories, free-form tags and required skills on bounties
+
+CREATE TABLE categories (
+    id          bigserial PRIMARY KEY,
+    slug        text NOT NULL,
+    name        text NOT NULL,
+    description text NOT NULL DEFAULT '',
+    created_at  timestamptz
+);
+CREATE UNIQUE INDEX idx_categories_slug ON categories (slug);
+
+INSERT INTO categories (slug, name, description, created_at) VALUES
+    ('development', 'Development', 'Application, backend and frontend development', now()),
+    ('smart-contracts', 'Smart Contracts', 'Writing and upgrading on-chain contracts', now()),
+    ('security', 'Security', 'Audits, reviews and vulnerability research', now()),
+    ('design', 'Design', 'UI, UX and visual design', now()),
+    ('documentation', 'Documentation', 'Guides, references and technical writing', now()),
+    ('research', 'Research', 'Analysis, specifications and proofs of concept', now()),
+    ('community', 'Community', 'Content, outreach and moderation', now());
+
+ALTER TABLE bounties ADD COLUMN category_id bigint
+    CONSTRAINT fk_bounties_category REFERENCES categories (id);
+CREATE INDEX idx_bounties_category_id ON bounties (category_id);
+
+CREATE TABLE tags (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_tags_name ON tags (name);
+
+CREATE TABLE bounty_tags (
+    bounty_id bigint NOT NULL REFERENCES bounties (id) ON DELETE CASCADE,
+    tag_id    bigint NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+    PRIMARY KEY (bounty_id, tag_id)
+);
+CREATE INDEX idx_bounty_tags_tag_id ON bounty_tags (tag_id);
+
+CREATE TABLE skills (
+    id         bigserial PRIMARY KEY,
+    name       text NOT NULL,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX idx_skills_name ON skills (name);
+
+CREATE TABLE bounty_skills (
+    bounty_id bigint NOT NULL REFERENCES bounties (id) ON DELETE CASCADE,
+    skill_id  bigint NOT NULL REFERENCES skills (id) ON DELETE CASCADE,
+    PRIMARY KEY (bounty_id, skill_id)
+);
+CREATE INDEX idx_bounty_skills_skill_id ON bounty_skills (skill_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reputation_events;
+DROP FUNCTION IF EXISTS reputation_events_append_only();
@@ -0,0 +1,29 @@
+-- Append-only reputation ledger. reputations.score becomes a projection of
+-- the sum of a user's event deltas.
+CREATE TABLE reputation_events (
+    id         bigserial PRIMARY KEY,
+    user_id    text NOT NULL,
+    delta      bigint NOT NULL,
+    reason     text NOT NULL,
+    bounty_id  bigint,
+    actor_id   text NOT NULL DEFAULT '',
+    created_at timestamptz NOT NULL
+);
+CREATE INDEX idx_reputation_events_user_created_at ON reputation_events (user_id, created_at, id);
+
+-- Carry existing scores over so rebuilding the projection keeps them
+INSERT INTO reputation_events (user_id, delta, reason, created_at)
+SELECT user_id, score, 'opening_balance', now()
+FROM reputations
+WHERE score IS NOT NULL AND score <> 0;
+
+-- Reject edits to recorded events
+CREATE FUNCTION reputation_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'reputation_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER reputation_events_append_only
+    BEFORE UPDATE OR DELETE ON reputation_events
+    FOR EACH ROW EXECUTE FUNCTION reputation_events_append_only();
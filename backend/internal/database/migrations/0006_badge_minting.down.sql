@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS badge_mint_jobs;
+ALTER TABLE badges DROP COLUMN IF EXISTS token_id;
@@ -0,0 +1,28 @@
+-- Milestone badges are minted as Reputation NFTs by a background worker.
+ALTER TABLE badges ADD COLUMN token_id text;
+
+-- Durable queue of badge mints. A job moves from pending to submitted once
+-- its transaction is signed, and to minted once the mint is confirmed; it is
+-- failed after too many attempts.
+CREATE TABLE badge_mint_jobs (
+    id              bigserial PRIMARY KEY,
+    badge_id        bigint NOT NULL UNIQUE REFERENCES badges (id) ON DELETE CASCADE,
+    status          text NOT NULL DEFAULT 'pending',
+    attempts        integer NOT NULL DEFAULT 0,
+    next_attempt_at timestamptz NOT NULL,
+    metadata_uri    text NOT NULL DEFAULT '',
+    tx_hash         text NOT NULL DEFAULT '',
+    raw_tx          bytea,
+    last_error      text NOT NULL DEFAULT '',
+    created_at      timestamptz NOT NULL,
+    updated_at      timestamptz NOT NULL
+);
+CREATE INDEX idx_badge_mint_jobs_status_next_attempt_at ON badge_mint_jobs (status, next_attempt_at);
+
+-- Queue badges awarded before minting existed
+INSERT INTO badge_mint_jobs (badge_id, next_attempt_at, created_at, updated_at)
+SELECT id, now(), now(), now()
+FROM badges
+WHERE coalesce(tx_hash, '') = '';
+
+UPDATE badges SET token_uri = '' WHERE coalesce(tx_hash, '') = '';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS submission_key_envelopes;
+ALTER TABLE bounty_submissions
+    DROP COLUMN IF EXISTS encrypted,
+    DROP COLUMN IF EXISTS ciphertext_cid;
+ALTER TABLE users DROP COLUMN IF EXISTS encryption_public_key;
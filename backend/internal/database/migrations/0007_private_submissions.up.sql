@@ -0,0 +1,20 @@
+-- X25519 public keys that private submissions are encrypted to
+ALTER TABLE users ADD COLUMN encryption_public_key text;
+
+-- Private submissions keep no plaintext: the content is an encrypted blob in
+-- the content store and each reader holds a wrapped copy of its key.
+ALTER TABLE bounty_submissions
+    ADD COLUMN encrypted boolean NOT NULL DEFAULT false,
+    ADD COLUMN ciphertext_cid text NOT NULL DEFAULT '';
+
+CREATE TABLE submission_key_envelopes (
+    id            bigserial PRIMARY KEY,
+    submission_id bigint NOT NULL REFERENCES bounty_submissions (id) ON DELETE CASCADE,
+    recipient_id  text NOT NULL,
+    recipient_key bytea NOT NULL,
+    ephemeral_key bytea NOT NULL,
+    wrapped_key   bytea NOT NULL,
+    created_by    text NOT NULL,
+    created_at    timestamptz NOT NULL,
+    UNIQUE (submission_id, recipient_id)
+);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS bounty_comment_revisions;
+DROP INDEX IF EXISTS idx_bounty_comments_bounty_parent;
+ALTER TABLE bounty_comments
+    DROP COLUMN IF EXISTS parent_id,
+    DROP COLUMN IF EXISTS content_html,
+    DROP COLUMN IF EXISTS edited_at,
+    DROP COLUMN IF EXISTS deleted_at,
+    DROP COLUMN IF EXISTS deleted_by;
+ALTER TABLE bounties DROP COLUMN IF EXISTS comment_policy;
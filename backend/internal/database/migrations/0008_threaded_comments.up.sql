@@ -0,0 +1,23 @@
+-- Who may comment on a bounty: 'participants' (creator and hunter, the
+-- previous fixed rule) or 'everyone'
+ALTER TABLE bounties ADD COLUMN comment_policy text NOT NULL DEFAULT 'participants';
+
+ALTER TABLE bounty_comments
+    ADD COLUMN parent_id    bigint REFERENCES bounty_comments (id),
+    ADD COLUMN content_html text NOT NULL DEFAULT '',
+    ADD COLUMN edited_at    timestamptz,
+    ADD COLUMN deleted_at   timestamptz,
+    ADD COLUMN deleted_by   text;
+
+CREATE INDEX IF NOT EXISTS idx_bounty_comments_bounty_parent ON bounty_comments (bounty_id, parent_id);
+
+-- Earlier versions of edited comments, oldest first
+CREATE TABLE bounty_comment_revisions (
+    id         bigserial PRIMARY KEY,
+    comment_id bigint NOT NULL REFERENCES bounty_comments (id) ON DELETE CASCADE,
+    content    text NOT NULL,
+    edited_by  text NOT NULL,
+    created_at timestamptz NOT NULL
+);
+
+CREATE INDEX idx_bounty_comment_revisions_comment_id ON bounty_comment_revisions (comment_id);
@@ -0,0 +1,121 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// BountyStatus is the lifecycle state of a bounty.
+type BountyStatus string
+
+const (
+	StatusPendingConfirmation BountyStatus = "pending_confirmation"
+	StatusRejected            BountyStatus = "rejected"
+	StatusOpen                BountyStatus = "open"
+	StatusClaimed             BountyStatus = "claimed"
+	StatusDisputed            BountyStatus = "disputed"
+	StatusCompleted           BountyStatus = "completed"
+	StatusExpired             BountyStatus = "expired"
+)
+
+// BountyAction is an event that moves a bounty between statuses.
+type BountyAction string
+
+const (
+	ActionConfirm  BountyAction = "confirm"
+	ActionReject   BountyAction = "reject"
+	ActionClaim    BountyAction = "claim"
+	ActionSubmit   BountyAction = "submit"
+	ActionComplete BountyAction = "complete"
+	ActionReopen   BountyAction = "reopen"
+	ActionExpire   BountyAction = "expire"
+	ActionUnclaim  BountyAction = "unclaim"
+	ActionDispute  BountyAction = "dispute"
+	ActionResolve  BountyAction = "resolve"
+)
+
+var (
+	ErrInvalidTransition = errors.New("invalid bounty status transition")
+	ErrNotAllowed        = errors.New("action not allowed for this user")
+	ErrInvalidWinner     = errors.New("winner must be creator or hunter")
+)
+
+// transitions lists, for each status, the actions allowed from it and the
+// status each one leads to. Anything not listed is rejected.
+var transitions = map[BountyStatus]map[BountyAction]BountyStatus{
+	StatusPendingConfirmation: {
+		ActionConfirm: StatusOpen,
+		ActionReject:  StatusRejected,
+	},
+	StatusRejected: {
+		ActionConfirm: StatusOpen,
+	},
+	StatusOpen: {
+		ActionClaim:  StatusClaimed,
+		ActionExpire: StatusExpired,
+	},
+	StatusClaimed: {
+		ActionSubmit:   StatusClaimed,
+		ActionComplete: StatusCompleted,
+		ActionReopen:   StatusOpen,
+		ActionUnclaim:  StatusOpen,
+		ActionDispute:  StatusDisputed,
+	},
+	StatusDisputed: {
+		ActionResolve: StatusCompleted,
+	},
+}
+
+// TransitionError describes an action that is not allowed from a status.
+type TransitionError struct {
+	From   BountyStatus
+	Action BountyAction
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot %s a bounty that is %s", e.Action, e.From)
+}
+
+func (e *TransitionError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// ParseBountyStatus validates a status string.
+func ParseBountyStatus(s string) (BountyStatus, error) {
+	status := BountyStatus(strings.ToLower(s))
+	if !status.Valid() {
+		return "", fmt.Errorf("unknown bounty status %q", s)
+	}
+	return status, nil
+}
+
+// Valid reports whether s is a declared status.
+func (s BountyStatus) Valid() bool {
+	switch s {
+	case StatusPendingConfirmation, StatusRejected, StatusOpen, StatusClaimed, StatusDisputed, StatusCompleted, StatusExpired:
+		return true
+	}
+	return false
+}
+
+// Terminal reports whether no further actions are possible from s.
+func (s BountyStatus) Terminal() bool {
+	return len(transitions[s]) == 0
+}
+
+// CanTransition reports whether action is allowed from status from.
+func CanTransition(from BountyStatus, action BountyAction) bool {
+	_, ok := transitions[from][action]
+	return ok
+}
+
+// Transition returns the status reached by applying action to from, or a
+// *TransitionError if the action is not allowed.
+func Transition(from BountyStatus, action BountyAction) (BountyStatus, error) {
+	to, ok := transitions[from][action]
+	if !ok {
+		return "", &TransitionError{From: from, Action: action}
+	}
+	return to, nil
+}
@@ -0,0 +1,163 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var (
+	allStatuses = []BountyStatus{
+		StatusPendingConfirmation, StatusRejected, StatusOpen, StatusClaimed,
+		StatusDisputed, StatusCompleted, StatusExpired,
+	}
+	allActions = []BountyAction{
+		ActionConfirm, ActionReject, ActionClaim, ActionSubmit, ActionComplete,
+		ActionReopen, ActionExpire, ActionUnclaim, ActionDispute, ActionResolve,
+	}
+)
+
+// allowedTransitions is the whole lifecycle, written out independently of
+// the transitions table. Every pair not listed here must be rejected.
+var allowedTransitions = map[BountyStatus]map[BountyAction]BountyStatus{
+	StatusPendingConfirmation: {ActionConfirm: StatusOpen, ActionReject: StatusRejected},
+	StatusRejected:            {ActionConfirm: StatusOpen},
+	StatusOpen:                {ActionClaim: StatusClaimed, ActionExpire: StatusExpired},
+	StatusClaimed: {
+		ActionSubmit:   StatusClaimed,
+		ActionComplete: StatusCompleted,
+		ActionReopen:   StatusOpen,
+		ActionUnclaim:  StatusOpen,
+		ActionDispute:  StatusDisputed,
+	},
+	StatusDisputed: {ActionResolve: StatusCompleted},
+}
+
+func TestTransitions(t *testing.T) {
+	for _, from := range allStatuses {
+		for _, action := range allActions {
+			want, allowed := allowedTransitions[from][action]
+
+			if got := CanTransition(from, action); got != allowed {
+				t.Errorf("CanTransition(%s, %s) = %v, want %v", from, action, got, allowed)
+			}
+			to, err := Transition(from, action)
+			if allowed {
+				if err != nil || to != want {
+					t.Errorf("Transition(%s, %s) = %q, %v, want %s", from, action, to, err, want)
+				}
+				continue
+			}
+			var transitionErr *TransitionError
+			if !errors.As(err, &transitionErr) || !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("Transition(%s, %s) = %q, %v, want a TransitionError", from, action, to, err)
+			} else if transitionErr.From != from || transitionErr.Action != action {
+				t.Errorf("Transition(%s, %s) error = %+v", from, action, transitionErr)
+			}
+		}
+	}
+}
+
+func TestTerminalStatuses(t *testing.T) {
+	for _, status := range allStatuses {
+		want := status == StatusCompleted || status == StatusExpired
+		if got := status.Terminal(); got != want {
+			t.Errorf("%s.Terminal() = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestParseBountyStatus(t *testing.T) {
+	for _, status := range allStatuses {
+		if got, err := ParseBountyStatus(string(status)); err != nil || got != status {
+			t.Errorf("ParseBountyStatus(%q) = %q, %v", status, got, err)
+		}
+	}
+	if got, err := ParseBountyStatus("CLAIMED"); err != nil || got != StatusClaimed {
+		t.Errorf("ParseBountyStatus(CLAIMED) = %q, %v", got, err)
+	}
+	if _, err := ParseBountyStatus("archived"); err == nil {
+		t.Error("ParseBountyStatus(archived) succeeded")
+	}
+}
+
+func TestReviewSubmission(t *testing.T) {
+	statuses := []SubmissionStatus{SubmissionPending, SubmissionAccepted, SubmissionRejected, SubmissionChangesRequested, SubmissionObsolete}
+	outcomes := map[ReviewDecision]SubmissionStatus{
+		DecisionAccept:         SubmissionAccepted,
+		DecisionReject:         SubmissionRejected,
+		DecisionRequestChanges: SubmissionChangesRequested,
+		"approve":              "",
+	}
+	for _, from := range statuses {
+		for decision, want := range outcomes {
+			to, err := ReviewSubmission(from, decision)
+			if from == SubmissionPending && want != "" {
+				if err != nil || to != want {
+					t.Errorf("ReviewSubmission(%s, %s) = %q, %v, want %s", from, decision, to, err, want)
+				}
+				continue
+			}
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("ReviewSubmission(%s, %s) = %q, %v, want ErrInvalidTransition", from, decision, to, err)
+			}
+		}
+	}
+}
+
+func TestGuards(t *testing.T) {
+	const creator, hunter, other = "0xC0", "0xa1", "0xb0"
+	hunterID := hunter
+	claimed := Participants{CreatorID: creator, HunterID: &hunterID}
+	open := Participants{CreatorID: creator}
+
+	tests := []struct {
+		name    string
+		guard   func(actorID string) error
+		allowed []string
+	}{
+		// Signing in is enforced before GuardClaim runs
+		{"claim", func(a string) error { return GuardClaim(open, a) }, []string{hunter, other, ""}},
+		{"submit", func(a string) error { return GuardSubmit(claimed, a) }, []string{hunter}},
+		{"complete", func(a string) error { return GuardComplete(claimed, a) }, []string{creator}},
+		{"review", func(a string) error { return GuardReview(claimed, a) }, []string{creator}},
+		{"dispute", func(a string) error { return GuardDispute(claimed, a) }, []string{creator}},
+		{"view submission", func(a string) error { return GuardViewSubmission(claimed, hunter, a) }, []string{creator, hunter}},
+		{"reshare submission", func(a string) error { return GuardReshareSubmission(claimed, hunter, a) }, []string{creator, hunter}},
+	}
+	for _, tt := range tests {
+		// IDs are addresses, so the creator in lowercase is still the creator
+		for _, actor := range []string{creator, strings.ToLower(creator), hunter, other, ""} {
+			allowed := false
+			for _, a := range tt.allowed {
+				if strings.EqualFold(a, actor) {
+					allowed = true
+				}
+			}
+			err := tt.guard(actor)
+			if allowed && err != nil {
+				t.Errorf("%s by %q: %v", tt.name, actor, err)
+			}
+			if !allowed && !errors.Is(err, ErrNotAllowed) {
+				t.Errorf("%s by %q: err = %v, want ErrNotAllowed", tt.name, actor, err)
+			}
+		}
+	}
+
+	if err := GuardResolve(claimed, hunter); err != nil {
+		t.Errorf("resolve for hunter: %v", err)
+	}
+	if err := GuardResolve(claimed, other); !errors.Is(err, ErrInvalidWinner) {
+		t.Errorf("resolve for outsider: err = %v, want ErrInvalidWinner", err)
+	}
+
+	if err := GuardReviewedSubmission(claimed, strings.ToUpper(hunter)); err != nil {
+		t.Errorf("review the hunter's submission: %v", err)
+	}
+	if err := GuardReviewedSubmission(claimed, other); !errors.Is(err, ErrStaleSubmission) {
+		t.Errorf("review an earlier hunter's submission: err = %v, want ErrStaleSubmission", err)
+	}
+	if err := GuardReviewedSubmission(open, hunter); !errors.Is(err, ErrStaleSubmission) {
+		t.Errorf("review a submission of a reopened bounty: err = %v, want ErrStaleSubmission", err)
+	}
+}
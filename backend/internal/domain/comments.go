@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CommentPolicy says who may comment on a bounty.
+type CommentPolicy string
+
+const (
+	// CommentsParticipants allows only the creator and the hunter.
+	CommentsParticipants CommentPolicy = "participants"
+	// CommentsEveryone allows any signed-in user.
+	CommentsEveryone CommentPolicy = "everyone"
+)
+
+func (p CommentPolicy) Valid() bool {
+	return p == CommentsParticipants || p == CommentsEveryone
+}
+
+// MaxMentionsPerComment caps how many users one comment can notify.
+const MaxMentionsPerComment = 10
+
+// GuardComment applies the bounty's comment policy. Bounties without a
+// policy fall back to CommentsParticipants.
+func GuardComment(p Participants, policy CommentPolicy, actorID string) error {
+	if actorID == "" {
+		return fmt.Errorf("%w: sign in to comment", ErrNotAllowed)
+	}
+	if policy == CommentsEveryone {
+		return nil
+	}
+	if !p.IsParticipant(actorID) {
+		return fmt.Errorf("%w: only the bounty creator or hunter can comment", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardSetCommentPolicy allows only the creator to change who may comment.
+func GuardSetCommentPolicy(p Participants, actorID string) error {
+	if !p.IsCreator(actorID) {
+		return fmt.Errorf("%w: only the bounty creator can change the comment policy", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardEditComment allows only the author to edit a comment.
+func GuardEditComment(authorID, actorID string) error {
+	if actorID == "" || !strings.EqualFold(authorID, actorID) {
+		return fmt.Errorf("%w: only the author can edit a comment", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardDeleteComment allows the author and moderators to delete a comment.
+func GuardDeleteComment(authorID, actorID string, isModerator bool) error {
+	if isModerator || (actorID != "" && strings.EqualFold(authorID, actorID)) {
+		return nil
+	}
+	return fmt.Errorf("%w: only the author or a moderator can delete a comment", ErrNotAllowed)
+}
+
+// mentionPattern matches @0x-addresses and @usernames that are not part of a
+// word or an email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@(0x[0-9a-fA-F]{40}|[A-Za-z0-9_]{3,32})\b`)
+
+// codePattern matches fenced code blocks and inline code spans, whose
+// contents are never mentions.
+var codePattern = regexp.MustCompile("(?s)```.*?(?:```|$)|`[^`\n]*`")
+
+// Mentions are the users mentioned in a comment.
+type Mentions struct {
+	Addresses []string // lowercase
+	Usernames []string
+}
+
+// Empty reports whether nobody is mentioned.
+func (m Mentions) Empty() bool {
+	return len(m.Addresses) == 0 && len(m.Usernames) == 0
+}
+
+// ExtractMentions finds distinct @0xaddress and @username mentions in
+// content, in order of appearance, up to MaxMentionsPerComment. Usernames
+// are compared case-insensitively and code is skipped.
+func ExtractMentions(content string) Mentions {
+	var mentions Mentions
+	seen := make(map[string]bool)
+	content = codePattern.ReplaceAllString(content, " ")
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		if len(mentions.Addresses)+len(mentions.Usernames) == MaxMentionsPerComment {
+			break
+		}
+		name := strings.ToLower(match[1])
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if strings.HasPrefix(name, "0x") && len(name) == 42 {
+			mentions.Addresses = append(mentions.Addresses, name)
+		} else {
+			mentions.Usernames = append(mentions.Usernames, match[1])
+		}
+	}
+	return mentions
+}
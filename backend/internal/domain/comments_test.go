@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractMentions(t *testing.T) {
+	const addr = "0xAbCdEf0123456789aBcDeF0123456789AbCdEf01"
+	tests := []struct {
+		name      string
+		content   string
+		addresses []string
+		usernames []string
+	}{
+		{"username and address", "thanks @alice and @" + addr + "!",
+			[]string{strings.ToLower(addr)}, []string{"alice"}},
+		{"start of line", "@bob_99 please review", nil, []string{"bob_99"}},
+		{"duplicates ignoring case", "@Alice @alice @ALICE @" + addr + " @" + strings.ToLower(addr),
+			[]string{strings.ToLower(addr)}, []string{"Alice"}},
+		{"email addresses", "mail alice@example.com or bob.smith@example.org", nil, nil},
+		{"inside words", "foo@bar and x@@carol", nil, nil},
+		{"too short", "@ab", nil, nil},
+		{"code span", "run `@dave` then ping @erin", nil, []string{"erin"}},
+		{"code block", "```\n@frank\n```\n@grace", nil, []string{"grace"}},
+		{"unterminated code block", "@heidi\n```\n@ivan", nil, []string{"heidi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExtractMentions(tt.content)
+			if !reflect.DeepEqual(got.Addresses, tt.addresses) || !reflect.DeepEqual(got.Usernames, tt.usernames) {
+				t.Fatalf("ExtractMentions(%q) = %+v, want addresses %v and usernames %v",
+					tt.content, got, tt.addresses, tt.usernames)
+			}
+			if got.Empty() != (len(tt.addresses)+len(tt.usernames) == 0) {
+				t.Fatalf("Empty() = %v", got.Empty())
+			}
+		})
+	}
+}
+
+func TestExtractMentionsLimit(t *testing.T) {
+	var content []string
+	for i := 0; i < MaxMentionsPerComment+5; i++ {
+		content = append(content, fmt.Sprintf("@user%02d", i))
+	}
+	got := ExtractMentions(strings.Join(content, " "))
+	if len(got.Usernames) != MaxMentionsPerComment {
+		t.Fatalf("%d mentions, want %d", len(got.Usernames), MaxMentionsPerComment)
+	}
+	if got.Usernames[0] != "user00" || got.Usernames[MaxMentionsPerComment-1] != fmt.Sprintf("user%02d", MaxMentionsPerComment-1) {
+		t.Fatalf("kept %v, want the first %d", got.Usernames, MaxMentionsPerComment)
+	}
+}
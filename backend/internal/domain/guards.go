@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Participants identifies who is involved in a bounty. IDs are compared
+// case-insensitively because they are wallet addresses.
+type Participants struct {
+	CreatorID string
+	HunterID  *string
+}
+
+// IsCreator reports whether userID created the bounty.
+func (p Participants) IsCreator(userID string) bool {
+	return userID != "" && strings.EqualFold(userID, p.CreatorID)
+}
+
+// IsHunter reports whether userID is the bounty's hunter.
+func (p Participants) IsHunter(userID string) bool {
+	return userID != "" && p.HunterID != nil && strings.EqualFold(userID, *p.HunterID)
+}
+
+// IsParticipant reports whether userID is the creator or the hunter.
+func (p Participants) IsParticipant(userID string) bool {
+	return p.IsCreator(userID) || p.IsHunter(userID)
+}
+
+// GuardClaim allows anyone but the creator to claim a bounty.
+func GuardClaim(p Participants, actorID string) error {
+	if p.IsCreator(actorID) {
+		return fmt.Errorf("%w: creators cannot claim their own bounty", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardSubmit allows only the hunter to submit work.
+func GuardSubmit(p Participants, actorID string) error {
+	if !p.IsHunter(actorID) {
+		return fmt.Errorf("%w: only the hunter can submit work", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardViewSubmission allows the bounty creator and the hunter who made a
+// submission to see its attachments.
+func GuardViewSubmission(p Participants, submissionHunterID, actorID string) error {
+	if !p.IsCreator(actorID) && (actorID == "" || !strings.EqualFold(actorID, submissionHunterID)) {
+		return fmt.Errorf("%w: only the bounty creator or the submitting hunter can view attachments", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardReshareSubmission allows the bounty creator and the hunter who made an
+// encrypted submission to share its key with an arbiter.
+func GuardReshareSubmission(p Participants, submissionHunterID, actorID string) error {
+	if !p.IsCreator(actorID) && (actorID == "" || !strings.EqualFold(actorID, submissionHunterID)) {
+		return fmt.Errorf("%w: only the bounty creator or the submitting hunter can share a submission", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardReviewedSubmission requires a submission under review to come from the
+// bounty's current hunter.
+func GuardReviewedSubmission(p Participants, submissionHunterID string) error {
+	if !p.IsHunter(submissionHunterID) {
+		return ErrStaleSubmission
+	}
+	return nil
+}
+
+// GuardComplete allows only the creator to complete a bounty.
+func GuardComplete(p Participants, actorID string) error {
+	if !p.IsCreator(actorID) {
+		return fmt.Errorf("%w: only the bounty creator can complete bounties", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardReview allows only the creator to review submissions.
+func GuardReview(p Participants, actorID string) error {
+	if !p.IsCreator(actorID) {
+		return fmt.Errorf("%w: only the bounty creator can review submissions", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardDispute allows only the creator to raise a dispute.
+func GuardDispute(p Participants, actorID string) error {
+	if !p.IsCreator(actorID) {
+		return fmt.Errorf("%w: only the bounty creator can raise a dispute", ErrNotAllowed)
+	}
+	return nil
+}
+
+// GuardResolve requires the dispute winner to be the creator or the hunter.
+// Whether the actor may resolve disputes at all is a role check.
+func GuardResolve(p Participants, winnerID string) error {
+	if !p.IsParticipant(winnerID) {
+		return ErrInvalidWinner
+	}
+	return nil
+}
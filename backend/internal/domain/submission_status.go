@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrStaleSubmission is returned when reviewing a submission from someone who
+// is no longer the bounty's hunter.
+var ErrStaleSubmission = errors.New("submission is not from the bounty's current hunter")
+
+// SubmissionStatus is the review state of a bounty submission.
+type SubmissionStatus string
+
+const (
+	SubmissionPending          SubmissionStatus = "pending"
+	SubmissionAccepted         SubmissionStatus = "accepted"
+	SubmissionRejected         SubmissionStatus = "rejected"
+	SubmissionChangesRequested SubmissionStatus = "changes_requested"
+	// SubmissionObsolete marks a pending submission left behind when its
+	// bounty was reopened for other hunters.
+	SubmissionObsolete SubmissionStatus = "obsolete"
+)
+
+// ReviewDecision is the creator's verdict on a submission.
+type ReviewDecision string
+
+const (
+	DecisionAccept         ReviewDecision = "accept"
+	DecisionReject         ReviewDecision = "reject"
+	DecisionRequestChanges ReviewDecision = "request_changes"
+)
+
+var reviewOutcomes = map[ReviewDecision]SubmissionStatus{
+	DecisionAccept:         SubmissionAccepted,
+	DecisionReject:         SubmissionRejected,
+	DecisionRequestChanges: SubmissionChangesRequested,
+}
+
+// ReviewError describes a decision on a submission that was already reviewed.
+type ReviewError struct {
+	From     SubmissionStatus
+	Decision ReviewDecision
+}
+
+func (e *ReviewError) Error() string {
+	return fmt.Sprintf("cannot %s a submission that is %s", e.Decision, e.From)
+}
+
+func (e *ReviewError) Unwrap() error {
+	return ErrInvalidTransition
+}
+
+// ReviewSubmission returns the status a submission reaches after decision.
+// Only pending submissions can be reviewed; a hunter answers a rejection or a
+// change request with a new submission.
+func ReviewSubmission(from SubmissionStatus, decision ReviewDecision) (SubmissionStatus, error) {
+	to, ok := reviewOutcomes[decision]
+	if !ok || from != SubmissionPending {
+		return "", &ReviewError{From: from, Decision: decision}
+	}
+	return to, nil
+}
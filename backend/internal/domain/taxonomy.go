@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	MaxTagsPerBounty   = 10
+	MaxSkillsPerBounty = 10
+)
+
+var ErrInvalidLabel = errors.New("invalid label")
+
+// labelPattern matches a normalised tag or skill: lowercase words joined by
+// hyphens, allowing names like "c++", "node.js" and "c#".
+var labelPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#.]*(-[a-z0-9+#.]+)*$`)
+
+const maxLabelLength = 32
+
+// NormalizeLabel lowercases a tag or skill name and joins its words with
+// hyphens, so "Smart Contracts" and "smart-contracts" are the same tag.
+func NormalizeLabel(s string) (string, error) {
+	label := strings.Join(strings.Fields(strings.ToLower(s)), "-")
+	if len(label) > maxLabelLength || !labelPattern.MatchString(label) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidLabel, s)
+	}
+	return label, nil
+}
+
+// NormalizeLabels normalises names with NormalizeLabel, dropping duplicates
+// and rejecting more than max distinct labels.
+func NormalizeLabels(names []string, max int) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	labels := make([]string, 0, len(names))
+	for _, name := range names {
+		label, err := NormalizeLabel(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[label] {
+			continue
+		}
+		seen[label] = true
+		labels = append(labels, label)
+	}
+	if len(labels) > max {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrInvalidLabel, max)
+	}
+	return labels, nil
+}
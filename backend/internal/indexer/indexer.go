@@ -0,0 +1,502 @@
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+)
+
+const cursorName = "bounty_board"
+
+// metadataFetchTimeout bounds loading a metadata document from the content
+// store before a block range is indexed.
+const metadataFetchTimeout = 30 * time.Second
+
+type Config struct {
+	StartBlock    uint64        // first block to index when no cursor is stored
+	Confirmations uint64        // blocks behind head considered safe from reorgs
+	BatchSize     uint64        // maximum blocks per log query
+	PollInterval  time.Duration // delay between polls once caught up
+}
+
+// ConfigFromEnv reads INDEXER_START_BLOCK, INDEXER_CONFIRMATIONS,
+// INDEXER_BATCH_SIZE and INDEXER_POLL_INTERVAL, falling back to defaults.
+func ConfigFromEnv() (Config, error) {
+	cfg := Config{
+		Confirmations: 12,
+		BatchSize:     1000,
+		PollInterval:  15 * time.Second,
+	}
+
+	var err error
+	if v := os.Getenv("INDEXER_START_BLOCK"); v != "" {
+		if cfg.StartBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return cfg, fmt.Errorf("invalid INDEXER_START_BLOCK: %w", err)
+		}
+	}
+	if v := os.Getenv("INDEXER_CONFIRMATIONS"); v != "" {
+		if cfg.Confirmations, err = strconv.ParseUint(v, 10, 64); err != nil {
+			return cfg, fmt.Errorf("invalid INDEXER_CONFIRMATIONS: %w", err)
+		}
+	}
+	if v := os.Getenv("INDEXER_BATCH_SIZE"); v != "" {
+		if cfg.BatchSize, err = strconv.ParseUint(v, 10, 64); err != nil || cfg.BatchSize == 0 {
+			return cfg, fmt.Errorf("invalid INDEXER_BATCH_SIZE: %q", v)
+		}
+	}
+	if v := os.Getenv("INDEXER_POLL_INTERVAL"); v != "" {
+		if cfg.PollInterval, err = time.ParseDuration(v); err != nil {
+			return cfg, fmt.Errorf("invalid INDEXER_POLL_INTERVAL: %w", err)
+		}
+	}
+	return cfg, nil
+}
+
+// Indexer follows BountyBoard events and mirrors them into the bounties table.
+// Only blocks at least Confirmations deep are processed, so logs that could
+// still be reorged out are never written. Bounties are updated under the same
+// row lock as API requests, so the two never interleave.
+type Indexer struct {
+	store    repository.Store
+	backend  chain.Backend
+	contract *chain.BountyBoard
+	metadata *services.BountyMetadataService
+	cfg      Config
+}
+
+func New(store repository.Store, backend chain.Backend, contract *chain.BountyBoard, metadata *services.BountyMetadataService, cfg Config) *Indexer {
+	return &Indexer{
+		store:    store,
+		backend:  backend,
+		contract: contract,
+		metadata: metadata,
+		cfg:      cfg,
+	}
+}
+
+// Run polls the chain until ctx is cancelled.
+func (ix *Indexer) Run(ctx context.Context) {
+	log.Printf("Indexing BountyBoard events from %s", ix.contract.Address.Hex())
+	for {
+		if err := ix.Sync(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			log.Printf("Indexer sync failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ix.cfg.PollInterval):
+		}
+	}
+}
+
+// Sync processes every confirmed block after the stored cursor.
+func (ix *Indexer) Sync(ctx context.Context) error {
+	head, err := ix.backend.BlockNumber(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch head block: %w", err)
+	}
+	if head < ix.cfg.Confirmations {
+		return nil
+	}
+	safe := head - ix.cfg.Confirmations
+
+	next, err := ix.nextBlock(ctx)
+	if err != nil {
+		return err
+	}
+
+	for next <= safe {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		to := next + ix.cfg.BatchSize - 1
+		if to > safe {
+			to = safe
+		}
+		if err := ix.processRange(ctx, next, to); err != nil {
+			return err
+		}
+		next = to + 1
+	}
+	return nil
+}
+
+func (ix *Indexer) nextBlock(ctx context.Context) (uint64, error) {
+	next, err := ix.store.Cursors().Get(ctx, cursorName)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ix.cfg.StartBlock, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to load indexer cursor: %w", err)
+	}
+	return next, nil
+}
+
+// processRange applies all logs in [from, to] and advances the cursor in the
+// same transaction, so a crash never skips or double-applies a block.
+func (ix *Indexer) processRange(ctx context.Context, from, to uint64) error {
+	logs, err := ix.backend.FilterLogs(ctx, ix.contract.FilterQuery(from, to))
+	if err != nil {
+		return fmt.Errorf("failed to fetch logs for blocks %d-%d: %w", from, to, err)
+	}
+	sort.Slice(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	// Metadata can take a while to load from the content store, so it is read
+	// before the transaction opens
+	details, err := ix.resolveMetadata(ctx, logs)
+	if err != nil {
+		return err
+	}
+
+	return ix.store.Transaction(ctx, func(tx repository.Store) error {
+		for _, l := range logs {
+			if l.Removed {
+				continue
+			}
+			if err := ix.apply(ctx, tx, l, details); err != nil {
+				return fmt.Errorf("failed to apply log %s#%d: %w", l.TxHash.Hex(), l.Index, err)
+			}
+		}
+
+		return tx.Cursors().Save(ctx, cursorName, to+1)
+	})
+}
+
+func (ix *Indexer) apply(ctx context.Context, tx repository.Store, l types.Log, details map[uint]bountyDetails) error {
+	event, err := ix.contract.ParseLog(l)
+	if errors.Is(err, chain.ErrUnknownEvent) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var (
+		blockchainID uint
+		action       domain.BountyAction
+		created      *models.Bounty
+		// update copies the event onto the locked bounty and returns the
+		// status the chain reports
+		update func(bounty *models.Bounty) domain.BountyStatus
+	)
+
+	switch e := event.(type) {
+	case *chain.BountyCreated:
+		blockchainID, action = uint(e.BountyID.Uint64()), domain.ActionConfirm
+		creatorID, err := ensureUser(ctx, tx, e.Creator)
+		if err != nil {
+			return err
+		}
+		created = &models.Bounty{
+			BlockchainID: blockchainID,
+			CreatorID:    creatorID,
+			Reward:       decimal.NewFromBigInt(e.Reward, -18),
+			Status:       domain.StatusOpen,
+			TxHash:       l.TxHash.Hex(),
+		}
+		details[blockchainID].apply(created)
+
+		// The chain is authoritative: a row created through the API that is
+		// still pending or was rejected is corrected and opened
+		update = func(bounty *models.Bounty) domain.BountyStatus {
+			bounty.CreatorID = created.CreatorID
+			bounty.Reward = created.Reward
+			bounty.TxHash = created.TxHash
+			if bounty.Status == domain.StatusPendingConfirmation || bounty.Status == domain.StatusRejected {
+				return domain.StatusOpen
+			}
+			return bounty.Status
+		}
+
+	case *chain.BountyClaimed:
+		blockchainID, action = uint(e.BountyID.Uint64()), domain.ActionClaim
+		hunter, err := ensureUser(ctx, tx, e.Hunter)
+		if err != nil {
+			return err
+		}
+		update = func(bounty *models.Bounty) domain.BountyStatus {
+			bounty.HunterID = &hunter
+			return domain.StatusClaimed
+		}
+
+	case *chain.BountyCompleted:
+		blockchainID, action = uint(e.BountyID.Uint64()), domain.ActionComplete
+		hunter, err := ensureUser(ctx, tx, e.Hunter)
+		if err != nil {
+			return err
+		}
+		update = func(bounty *models.Bounty) domain.BountyStatus {
+			bounty.HunterID = &hunter
+			return domain.StatusCompleted
+		}
+
+	case *chain.DisputeRaised:
+		blockchainID, action = uint(e.BountyID.Uint64()), domain.ActionDispute
+		update = func(bounty *models.Bounty) domain.BountyStatus {
+			bounty.DisputeReason = &e.Reason
+			return domain.StatusDisputed
+		}
+
+	case *chain.DisputeResolved:
+		blockchainID, action = uint(e.BountyID.Uint64()), domain.ActionResolve
+		header, err := ix.backend.HeaderByNumber(ctx, new(big.Int).SetUint64(l.BlockNumber))
+		if err != nil {
+			return fmt.Errorf("failed to fetch block %d: %w", l.BlockNumber, err)
+		}
+		winner := addressID(e.Winner)
+		resolvedAt := time.Unix(int64(header.Time), 0)
+		update = func(bounty *models.Bounty) domain.BountyStatus {
+			bounty.DisputeWinner = &winner
+			bounty.DisputeResolution = &e.Resolution
+			bounty.ResolvedAt = &resolvedAt
+			return domain.StatusCompleted
+		}
+
+	default:
+		return nil
+	}
+
+	id, err := tx.Bounties().IDByBlockchainID(ctx, blockchainID)
+	if errors.Is(err, repository.ErrNotFound) {
+		if created == nil {
+			// The bounty was created before the indexer's start block, so it
+			// is stored as the contract describes it before the event applies
+			if created, err = discovered(ctx, tx, blockchainID, details); err != nil {
+				return err
+			}
+		}
+		id, err = insertBounty(ctx, tx, created)
+	}
+	if err != nil {
+		return err
+	}
+
+	// The chain is mirrored as-is rather than checked against the transition
+	// table, but every resulting status change is still recorded
+	_, err = services.WithLockedBounty(ctx, tx, id, func(tx repository.Store, bounty *models.Bounty) error {
+		from := bounty.Status
+		to := update(bounty)
+		if err := tx.Bounties().Mirror(ctx, bounty, to, action, services.ActorIndexer); err != nil {
+			return err
+		}
+		if to == domain.StatusCompleted && from != domain.StatusCompleted {
+			return awardReputation(ctx, tx, bounty, action)
+		}
+		return nil
+	})
+	return err
+}
+
+// discovered builds the row of a bounty whose first event seen is not its
+// creation from the contract's view of it, loaded by resolveMetadata.
+func discovered(ctx context.Context, tx repository.Store, blockchainID uint, details map[uint]bountyDetails) (*models.Bounty, error) {
+	d, ok := details[blockchainID]
+	if !ok {
+		return nil, fmt.Errorf("bounty %d is not stored and was not loaded from the contract", blockchainID)
+	}
+	creatorID, err := ensureUser(ctx, tx, d.Creator)
+	if err != nil {
+		return nil, err
+	}
+	bounty := &models.Bounty{
+		BlockchainID: blockchainID,
+		CreatorID:    creatorID,
+		Reward:       decimal.NewFromBigInt(d.Reward, -18),
+		Status:       domain.StatusOpen,
+	}
+	d.apply(bounty)
+	return bounty, nil
+}
+
+// insertBounty stores a bounty first seen on-chain and records its creation.
+// A row inserted concurrently through the API fails the range, which is
+// retried on the next poll and then updates that row instead.
+func insertBounty(ctx context.Context, tx repository.Store, bounty *models.Bounty) (uint, error) {
+	if err := tx.Bounties().Create(ctx, bounty); err != nil {
+		return 0, err
+	}
+	err := tx.Bounties().AddHistory(ctx, &models.BountyStatusHistory{
+		BountyID: bounty.ID,
+		ToStatus: bounty.Status,
+		Action:   domain.ActionConfirm,
+		ActorID:  services.ActorIndexer,
+	})
+	return bounty.ID, err
+}
+
+// awardReputation credits the participants of a bounty completed on-chain.
+// The bounty is locked and was not completed before, so a bounty completed
+// through the API is never awarded twice.
+func awardReputation(ctx context.Context, tx repository.Store, bounty *models.Bounty, action domain.BountyAction) error {
+	reputation := services.NewReputationService(tx)
+	if action == domain.ActionResolve {
+		return reputation.AwardDisputeResolution(ctx, bounty, services.ActorIndexer)
+	}
+	return reputation.AwardCompletion(ctx, bounty, services.ActorIndexer)
+}
+
+// ensureUser provisions the user behind an address seen on-chain, so events
+// from wallets that never signed in still satisfy the bounties' foreign keys.
+// The transaction that emitted the event proves the address took part.
+func ensureUser(ctx context.Context, tx repository.Store, address common.Address) (string, error) {
+	user, err := services.NewUserService(tx).FindOrCreateByAddress(ctx, addressID(address))
+	if err != nil {
+		return "", fmt.Errorf("failed to provision user %s: %w", addressID(address), err)
+	}
+	return user.ID, nil
+}
+
+// bountyDetails is the part of a bounty described by the contract and its
+// metadata rather than by events. Creator and Reward are only needed for
+// bounties whose creation was not indexed.
+type bountyDetails struct {
+	Creator     common.Address
+	Reward      *big.Int
+	Title       string
+	Description string
+	Deadline    time.Time
+	IPFSHash    string
+}
+
+func (d bountyDetails) apply(bounty *models.Bounty) {
+	bounty.Title = d.Title
+	bounty.Description = d.Description
+	bounty.Deadline = d.Deadline
+	bounty.IPFSHash = d.IPFSHash
+}
+
+// resolveMetadata loads the details of every bounty in logs that is not
+// stored yet, keyed by BlockchainID. Rows that already exist keep their
+// details, so their metadata is not fetched. A document that cannot be loaded
+// fails the range, which is retried on the next poll rather than stored
+// without a title.
+func (ix *Indexer) resolveMetadata(ctx context.Context, logs []types.Log) (map[uint]bountyDetails, error) {
+	details := map[uint]bountyDetails{}
+	for _, l := range logs {
+		if l.Removed {
+			continue
+		}
+		event, err := ix.contract.ParseLog(l)
+		if err != nil {
+			continue
+		}
+		id := eventBountyID(event)
+		if id == nil {
+			continue
+		}
+
+		blockchainID := uint(id.Uint64())
+		if _, ok := details[blockchainID]; ok {
+			continue
+		}
+		_, err = ix.store.Bounties().IDByBlockchainID(ctx, blockchainID)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+
+		d, err := ix.loadMetadata(ctx, id, l.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve metadata of bounty %d: %w", blockchainID, err)
+		}
+		details[blockchainID] = d
+	}
+	return details, nil
+}
+
+// eventBountyID returns the on-chain ID of the bounty an event is about.
+func eventBountyID(event interface{}) *big.Int {
+	switch e := event.(type) {
+	case *chain.BountyCreated:
+		return e.BountyID
+	case *chain.BountyClaimed:
+		return e.BountyID
+	case *chain.BountyCompleted:
+		return e.BountyID
+	case *chain.DisputeRaised:
+		return e.BountyID
+	case *chain.DisputeResolved:
+		return e.BountyID
+	}
+	return nil
+}
+
+// loadMetadata reads the creator and reward of a bounty and the title,
+// description and deadline from the metadata string stored on-chain: an
+// ipfs:// reference to a metadata document, or inline JSON from older
+// clients. Inline metadata that does not parse is ignored, since retrying
+// cannot fix it.
+func (ix *Indexer) loadMetadata(ctx context.Context, id *big.Int, blockNumber uint64) (bountyDetails, error) {
+	onChain, err := ix.contract.GetBounty(ctx, ix.backend, id, new(big.Int).SetUint64(blockNumber))
+	if err != nil {
+		return bountyDetails{}, err
+	}
+	if onChain.Creator == (common.Address{}) {
+		return bountyDetails{}, fmt.Errorf("bounty %s does not exist on-chain", id)
+	}
+	details := bountyDetails{Creator: onChain.Creator, Reward: onChain.Reward}
+
+	if cid, ok := services.ParseMetadataURI(onChain.Metadata); ok {
+		ctx, cancel := context.WithTimeout(ctx, metadataFetchTimeout)
+		defer cancel()
+		document, _, err := ix.metadata.Load(ctx, cid)
+		if err != nil {
+			return bountyDetails{}, fmt.Errorf("failed to load %s: %w", cid, err)
+		}
+		details.Title = document.Title
+		details.Description = document.Description
+		details.Deadline = document.Deadline
+		details.IPFSHash = cid
+		return details, nil
+	}
+
+	var metadata struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		Deadline    string `json:"deadline"`
+	}
+	if err := json.Unmarshal([]byte(onChain.Metadata), &metadata); err != nil {
+		log.Printf("Indexer ignored malformed metadata of bounty %s: %v", id, err)
+		return details, nil
+	}
+
+	details.Title = metadata.Title
+	details.Description = metadata.Description
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, metadata.Deadline); err == nil {
+			details.Deadline = t
+			break
+		}
+	}
+	return details, nil
+}
+
+func addressID(address common.Address) string {
+	return strings.ToLower(address.Hex())
+}
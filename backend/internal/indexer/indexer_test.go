@@ -0,0 +1,486 @@
+package indexer
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/chain/chaintest"
+	"github.com/bountyBoard/internal/database/dbtest"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"gorm.io/gorm"
+)
+
+var (
+	boardAddress = common.HexToAddress("0x00000000000000000000000000000000000b0a2d")
+	creator      = common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	hunter       = common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	otherCreator = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+)
+
+const inlineMetadata = `{"title":"Indexed bounty","description":"Created on-chain","deadline":"2030-01-02"}`
+
+// getBountyResult ABI-encodes a getBounty response carrying metadata.
+func getBountyResult(t *testing.T, metadata string) []byte {
+	t.Helper()
+
+	newType := func(name string) abi.Type {
+		typ, err := abi.NewType(name, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return typ
+	}
+	args := abi.Arguments{
+		{Type: newType("uint256")}, {Type: newType("address")}, {Type: newType("uint256")},
+		{Type: newType("uint8")}, {Type: newType("address")}, {Type: newType("string")}, {Type: newType("string")},
+	}
+	result, err := args.Pack(big.NewInt(1), creator, big.NewInt(1e18), uint8(0), common.Address{}, metadata, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+type testChain struct {
+	*chaintest.Chain
+	db      *gorm.DB
+	board   *chain.BountyBoard
+	content storage.ContentStore
+}
+
+func newTestChain(t *testing.T, metadata string) *testChain {
+	t.Helper()
+
+	code := chaintest.EmitterCode(chaintest.Selector("getBounty(uint256)"), getBountyResult(t, metadata))
+	board, err := chain.NewBountyBoard(boardAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testChain{
+		Chain:   chaintest.New(t, map[common.Address][]byte{boardAddress: code}),
+		db:      dbtest.Open(t),
+		board:   board,
+		content: storage.NewMemoryStore(),
+	}
+}
+
+func (tc *testChain) indexer(confirmations uint64) *Indexer {
+	return New(repository.NewGormStore(tc.db), tc.SimulatedBackend, tc.board, services.NewBountyMetadataService(tc.content), Config{
+		Confirmations: confirmations,
+		BatchSize:     4,
+		PollInterval:  time.Second,
+	})
+}
+
+func createdData(id int64, by common.Address) []byte {
+	reward := common.BigToHash(new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)))
+	return chaintest.EmitData([3]common.Hash{
+		chaintest.EventID("BountyCreated(uint256,address,uint256)"), chaintest.IntTopic(id), chaintest.AddressTopic(by),
+	}, reward.Bytes())
+}
+
+func (tc *testChain) created(t *testing.T, id int64, by common.Address) *types.Transaction {
+	t.Helper()
+	return tc.Send(t, boardAddress, createdData(id, by))
+}
+
+func (tc *testChain) hunterEvent(t *testing.T, signature string, id int64, who common.Address) {
+	t.Helper()
+	tc.Emit(t, boardAddress, [3]common.Hash{
+		chaintest.EventID(signature), chaintest.IntTopic(id), chaintest.AddressTopic(who),
+	}, nil)
+}
+
+func (tc *testChain) disputeRaised(t *testing.T, id int64, reason string) {
+	t.Helper()
+	stringType, _ := abi.NewType("string", "", nil)
+	data, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.Emit(t, boardAddress, [3]common.Hash{
+		chaintest.EventID("DisputeRaised(uint256,address,string)"), chaintest.IntTopic(id), chaintest.AddressTopic(creator),
+	}, data)
+}
+
+func (tc *testChain) disputeResolved(t *testing.T, id int64, winner common.Address, resolution string) {
+	t.Helper()
+	stringType, _ := abi.NewType("string", "", nil)
+	data, err := abi.Arguments{{Type: stringType}}.Pack(resolution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tc.Emit(t, boardAddress, [3]common.Hash{
+		chaintest.EventID("DisputeResolved(uint256,address,string)"), chaintest.IntTopic(id), chaintest.AddressTopic(winner),
+	}, data)
+}
+
+// awards returns the reputation events recorded for a bounty by user.
+func (tc *testChain) awards(t *testing.T, bountyID uint) map[string][]models.ReputationReason {
+	t.Helper()
+	var events []models.ReputationEvent
+	if err := tc.db.Order("id").Find(&events, "bounty_id = ?", bountyID).Error; err != nil {
+		t.Fatal(err)
+	}
+	awards := map[string][]models.ReputationReason{}
+	for _, event := range events {
+		awards[event.UserID] = append(awards[event.UserID], event.Reason)
+	}
+	return awards
+}
+
+func (tc *testChain) bounty(t *testing.T, blockchainID uint) *models.Bounty {
+	t.Helper()
+	var bounty models.Bounty
+	if err := tc.db.First(&bounty, "blockchain_id = ?", blockchainID).Error; err != nil {
+		t.Fatalf("bounty %d: %v", blockchainID, err)
+	}
+	return &bounty
+}
+
+func (tc *testChain) history(t *testing.T, bountyID uint) []domain.BountyStatus {
+	t.Helper()
+	var rows []models.BountyStatusHistory
+	if err := tc.db.Order("id").Find(&rows, "bounty_id = ?", bountyID).Error; err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]domain.BountyStatus, len(rows))
+	for i, row := range rows {
+		statuses[i] = row.ToStatus
+	}
+	return statuses
+}
+
+func (tc *testChain) cursor(t *testing.T) uint64 {
+	t.Helper()
+	var cursor models.IndexerCursor
+	if err := tc.db.First(&cursor, "name = ?", cursorName).Error; err != nil {
+		t.Fatal(err)
+	}
+	return cursor.NextBlock
+}
+
+func TestIndexerMirrorsLifecycle(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+
+	tc.created(t, 1, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyCompleted(uint256,address)", 1, hunter)
+	tc.Commit()
+
+	tc.created(t, 2, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 2, hunter)
+	tc.Commit()
+	tc.disputeRaised(t, 2, "work was not delivered")
+	tc.Commit()
+
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	completed := tc.bounty(t, 1)
+	if completed.Status != domain.StatusCompleted || completed.CreatorID != addressID(creator) ||
+		completed.HunterID == nil || *completed.HunterID != addressID(hunter) {
+		t.Fatalf("bounty 1 = %+v", completed)
+	}
+	if completed.Title != "Indexed bounty" || completed.Deadline.Format("2006-01-02") != "2030-01-02" {
+		t.Fatalf("metadata not applied: %q %v", completed.Title, completed.Deadline)
+	}
+	if completed.Reward.String() != "2" {
+		t.Fatalf("reward = %s, want 2", completed.Reward)
+	}
+	want := []domain.BountyStatus{domain.StatusOpen, domain.StatusClaimed, domain.StatusCompleted}
+	if got := tc.history(t, completed.ID); !equalStatuses(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+
+	disputed := tc.bounty(t, 2)
+	if disputed.Status != domain.StatusDisputed || disputed.DisputeReason == nil || *disputed.DisputeReason != "work was not delivered" {
+		t.Fatalf("bounty 2 = %+v", disputed)
+	}
+
+	// Completing on-chain awards reputation to both participants
+	var events int64
+	if err := tc.db.Model(&models.ReputationEvent{}).Where("bounty_id = ?", completed.ID).Count(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	if events == 0 {
+		t.Fatal("no reputation awarded for the completed bounty")
+	}
+}
+
+func TestIndexerProvisionsUnknownWallets(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+
+	// Neither wallet has ever signed in
+	tc.created(t, 1, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, address := range []common.Address{creator, hunter} {
+		var user models.User
+		if err := tc.db.First(&user, "id = ?", addressID(address)).Error; err != nil {
+			t.Fatalf("user %s not provisioned: %v", address.Hex(), err)
+		}
+		var reputation models.Reputation
+		if err := tc.db.First(&reputation, "user_id = ?", user.ID).Error; err != nil {
+			t.Fatalf("reputation of %s not provisioned: %v", address.Hex(), err)
+		}
+	}
+	if bounty := tc.bounty(t, 1); bounty.Status != domain.StatusClaimed {
+		t.Fatalf("status = %s, want claimed", bounty.Status)
+	}
+}
+
+func TestIndexerWaitsForConfirmations(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+	ix := tc.indexer(3)
+
+	tc.Mine(3)
+	parent := tc.Head(t)
+	parentHeader, err := tc.HeaderByNumber(ctx, new(big.Int).SetUint64(parent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// This block is reorged out before it has enough confirmations
+	orphan := tc.created(t, 1, creator)
+	tc.Commit()
+	tc.Mine(1)
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	var count int64
+	tc.db.Model(&models.Bounty{}).Count(&count)
+	if count != 0 {
+		t.Fatal("unconfirmed log was indexed")
+	}
+
+	if err := tc.Fork(ctx, parentHeader.Hash()); err != nil {
+		t.Fatal(err)
+	}
+	// The replacement chain creates the bounty from another wallet instead
+	tc.Replace(t, orphan, boardAddress, createdData(1, otherCreator))
+	tc.Mine(6)
+	if err := ix.Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	bounty := tc.bounty(t, 1)
+	if bounty.CreatorID != addressID(otherCreator) {
+		t.Fatalf("creator = %s, want the creator from the canonical chain", bounty.CreatorID)
+	}
+	var orphaned int64
+	tc.db.Model(&models.User{}).Where("id = ?", addressID(creator)).Count(&orphaned)
+	if orphaned != 0 {
+		t.Fatal("the creator from the reorged block was provisioned")
+	}
+	if got, want := tc.cursor(t), tc.Head(t)-3+1; got != want {
+		t.Fatalf("cursor = %d, want %d", got, want)
+	}
+}
+
+func TestIndexerResumesFromCursor(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+
+	tc.created(t, 1, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tc.cursor(t), tc.Head(t)+1; got != want {
+		t.Fatalf("cursor = %d, want %d", got, want)
+	}
+
+	// A restarted indexer picks up after the cursor without replaying
+	tc.hunterEvent(t, "BountyCompleted(uint256,address)", 1, hunter)
+	tc.Commit()
+	tc.Mine(5)
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	bounty := tc.bounty(t, 1)
+	want := []domain.BountyStatus{domain.StatusOpen, domain.StatusClaimed, domain.StatusCompleted}
+	if got := tc.history(t, bounty.ID); !equalStatuses(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+	if got, want := tc.cursor(t), tc.Head(t)+1; got != want {
+		t.Fatalf("cursor = %d, want %d", got, want)
+	}
+}
+
+func equalStatuses(a, b []domain.BountyStatus) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestIndexerRetriesUnavailableMetadata(t *testing.T) {
+	ctx := context.Background()
+	document := &services.BountyMetadata{
+		Version:     services.BountyMetadataVersion,
+		Title:       "Pinned bounty",
+		Description: "Described in a pinned document",
+		Reward:      "2",
+		Deadline:    time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+		Creator:     addressID(creator),
+	}
+	cid, err := services.NewBountyMetadataService(storage.NewMemoryStore()).Pin(ctx, document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc := newTestChain(t, services.MetadataURI(cid))
+	tc.created(t, 1, creator)
+	tc.Commit()
+
+	// The document is not in the content store yet
+	if err := tc.indexer(0).Sync(ctx); err == nil {
+		t.Fatal("Sync succeeded without the metadata document")
+	}
+	var count int64
+	tc.db.Model(&models.Bounty{}).Count(&count)
+	if count != 0 {
+		t.Fatal("bounty was stored without its metadata")
+	}
+
+	if _, err := services.NewBountyMetadataService(tc.content).Pin(ctx, document); err != nil {
+		t.Fatal(err)
+	}
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+	bounty := tc.bounty(t, 1)
+	if bounty.Title != document.Title || bounty.Description != document.Description || bounty.IPFSHash != cid {
+		t.Fatalf("bounty = %+v, want the pinned metadata", bounty)
+	}
+}
+
+func TestIndexerDoesNotAwardBountiesCompletedThroughTheAPI(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+	store := repository.NewGormStore(tc.db)
+
+	tc.created(t, 1, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// The creator accepts the work before the completion is mined
+	id := tc.bounty(t, 1).ID
+	_, err := services.WithLockedBounty(ctx, store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if err := tx.Bounties().Transition(ctx, bounty, domain.ActionComplete, addressID(creator)); err != nil {
+			return err
+		}
+		return services.NewReputationService(tx).AwardCompletion(ctx, bounty, addressID(creator))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc.hunterEvent(t, "BountyCompleted(uint256,address)", 1, hunter)
+	tc.Commit()
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	awards := tc.awards(t, id)
+	if got := awards[addressID(hunter)]; len(got) != 1 || got[0] != models.ReasonBountyCompleted {
+		t.Errorf("hunter awards = %v, want one completion", got)
+	}
+	if got := awards[addressID(creator)]; len(got) != 1 || got[0] != models.ReasonBountyFunded {
+		t.Errorf("creator awards = %v, want one funding", got)
+	}
+}
+
+func TestIndexerAwardsResolvedDisputes(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+
+	tc.created(t, 1, creator)
+	tc.Commit()
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+	tc.disputeRaised(t, 1, "work was not delivered")
+	tc.Commit()
+	tc.disputeResolved(t, 1, hunter, "the work was delivered")
+	tc.Commit()
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	bounty := tc.bounty(t, 1)
+	if bounty.Status != domain.StatusCompleted || bounty.DisputeWinner == nil || *bounty.DisputeWinner != addressID(hunter) ||
+		bounty.ResolvedAt == nil {
+		t.Fatalf("bounty = %+v, want completed in the hunter's favour", bounty)
+	}
+
+	awards := tc.awards(t, bounty.ID)
+	if got := awards[addressID(hunter)]; len(got) != 1 || got[0] != models.ReasonDisputeWon {
+		t.Errorf("hunter awards = %v, want one dispute won", got)
+	}
+	if got := awards[addressID(creator)]; len(got) != 1 || got[0] != models.ReasonDisputeLost {
+		t.Errorf("creator awards = %v, want one dispute lost", got)
+	}
+}
+
+func TestIndexerLoadsBountiesCreatedBeforeItsStartBlock(t *testing.T) {
+	ctx := context.Background()
+	tc := newTestChain(t, inlineMetadata)
+
+	// The creation is not indexed, so the claim is the first event seen
+	tc.hunterEvent(t, "BountyClaimed(uint256,address)", 1, hunter)
+	tc.Commit()
+	if err := tc.indexer(0).Sync(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	bounty := tc.bounty(t, 1)
+	if bounty.CreatorID != addressID(creator) || bounty.Reward.String() != "1" {
+		t.Fatalf("creator and reward = %q %s, want the contract's %q 1", bounty.CreatorID, bounty.Reward, addressID(creator))
+	}
+	if bounty.Title != "Indexed bounty" || bounty.Deadline.Format("2006-01-02") != "2030-01-02" {
+		t.Fatalf("metadata not applied: %q %v", bounty.Title, bounty.Deadline)
+	}
+	if bounty.Status != domain.StatusClaimed || bounty.HunterID == nil || *bounty.HunterID != addressID(hunter) {
+		t.Fatalf("bounty = %+v, want claimed by the hunter", bounty)
+	}
+	want := []domain.BountyStatus{domain.StatusOpen, domain.StatusClaimed}
+	if got := tc.history(t, bounty.ID); !equalStatuses(got, want) {
+		t.Fatalf("history = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	os.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	auth.InitTokens()
+	os.Exit(m.Run())
+}
+
+const (
+	testOwner   = "0x00000000000000000000000000000000000000a1"
+	testProfile = "0x01"
+)
+
+// lensSession signs up the owner of testProfile and starts a Lens session.
+func lensSession(t *testing.T, store repository.Store) (*models.User, *services.TokenPair) {
+	t.Helper()
+	ctx := context.Background()
+	users := services.NewUserService(store)
+
+	user, err := users.FindOrCreateByAddress(ctx, testOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.LinkLensProfile(ctx, user, &auth.LensIdentity{ProfileID: testProfile, OwnedBy: common.HexToAddress(testOwner)}); err != nil {
+		t.Fatal(err)
+	}
+	tokens, err := services.NewSessionService(store).Create(ctx, user, services.ProviderLens, testProfile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, tokens
+}
+
+func serveLensAuth(store repository.Store, token string) *httptest.ResponseRecorder {
+	router := gin.New()
+	router.GET("/", LensAuth(store), func(c *gin.Context) {
+		c.String(http.StatusOK, CurrentUserID(c))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestLensAuth(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		store := repository.NewMemoryStore()
+		_, tokens := lensSession(t, store)
+		w := serveLensAuth(store, tokens.AccessToken)
+		if w.Code != http.StatusOK || w.Body.String() != testOwner {
+			t.Fatalf("status %d, body %q", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := repository.NewMemoryStore()
+		user, tokens := lensSession(t, store)
+		claims, err := services.NewSessionService(store).Authenticate(ctx, tokens.AccessToken)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expired, _, err := auth.IssueAccessToken(user.ID, auth.Claims{
+			Address:   user.Address,
+			Provider:  services.ProviderLens,
+			ProfileID: testProfile,
+			SessionID: claims.SessionID,
+		}, time.Now().Add(-2*auth.AccessTokenTTL))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := serveLensAuth(store, expired); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+	})
+
+	t.Run("wallet session", func(t *testing.T) {
+		store := repository.NewMemoryStore()
+		user, _ := lensSession(t, store)
+		tokens, err := services.NewSessionService(store).Create(ctx, user, services.ProviderWallet, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w := serveLensAuth(store, tokens.AccessToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+	})
+
+	t.Run("profile now owned by another user", func(t *testing.T) {
+		store := repository.NewMemoryStore()
+		_, tokens := lensSession(t, store)
+
+		// The profile was transferred and linked to its new owner
+		users := services.NewUserService(store)
+		other, err := users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000b0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := users.LinkLensProfile(ctx, other, &auth.LensIdentity{ProfileID: testProfile, OwnedBy: common.HexToAddress(other.Address)}); err != nil {
+			t.Fatal(err)
+		}
+
+		if w := serveLensAuth(store, tokens.AccessToken); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		if w := serveLensAuth(repository.NewMemoryStore(), ""); w.Code != http.StatusUnauthorized {
+			t.Fatalf("status %d, want 401", w.Code)
+		}
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole allows the request through only if the authenticated user holds
+// at least one of the given roles. It must run after WalletAuth or LensAuth.
+func RequireRole(store repository.Store, roles ...models.Role) gin.HandlerFunc {
+	roleService := services.NewRoleService(store)
+	return func(c *gin.Context) {
+		userID := CurrentUserID(c)
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			c.Abort()
+			return
+		}
+
+		ok, err := roleService.HasAnyRole(c.Request.Context(), userID, roles...)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
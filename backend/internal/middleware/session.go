@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/services"
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimsKey is the gin context key holding the verified *auth.Claims.
+const ClaimsKey = "claims"
+
+// GetClaims returns the claims verified by the auth middleware, or nil on
+// unauthenticated routes.
+func GetClaims(c *gin.Context) *auth.Claims {
+	if v, ok := c.Get(ClaimsKey); ok {
+		if claims, ok := v.(*auth.Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
+
+// CurrentUserID returns the authenticated user's ID, or "" if there is none.
+func CurrentUserID(c *gin.Context) string {
+	if claims := GetClaims(c); claims != nil {
+		return claims.UserID()
+	}
+	return ""
+}
+
+// authenticate verifies the bearer access token and stores its claims in the
+// context. It aborts the request and returns false on failure.
+func authenticate(c *gin.Context, sessions *services.SessionService) (*auth.Claims, bool) {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		c.Abort()
+		return nil, false
+	}
+
+	// Bearer token format
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || parts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization format"})
+		c.Abort()
+		return nil, false
+	}
+
+	claims, err := sessions.Authenticate(c.Request.Context(), parts[1])
+	if err != nil {
+		if errors.Is(err, services.ErrSessionInvalid) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session"})
+		} else {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error"})
+		}
+		c.Abort()
+		return nil, false
+	}
+
+	c.Set(ClaimsKey, claims)
+	return claims, true
+}
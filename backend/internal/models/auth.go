@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// AuthNonce is a single-use challenge handed out for Sign-In with Ethereum.
+type AuthNonce struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	Nonce     string     `json:"nonce" gorm:"uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Session is created on login and backs a refresh token. Access tokens carry
+// the session ID so that revoking the session also revokes them. Only the
+// SHA-256 hash of the refresh token is stored.
+type Session struct {
+	ID               uint       `json:"id" gorm:"primaryKey"`
+	RefreshTokenHash string     `json:"-" gorm:"uniqueIndex"`
+	UserID           string     `json:"user_id" gorm:"index"`
+	Address          string     `json:"address"`
+	Provider         string     `json:"provider"`
+	ProfileID        string     `json:"profile_id,omitempty"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	LastUsedAt       *time.Time `json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// Active reports whether the session can still be used at the given time.
+func (s *Session) Active(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
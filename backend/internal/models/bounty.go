@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/bountyBoard/internal/domain"
 	"github.com/shopspring/decimal"
 )
 
@@ -13,7 +15,7 @@ type Bounty struct {
 	Reward          decimal.Decimal `json:"reward" gorm:"type:decimal(32,18)"` // 18 decimals for ETH compatibility
 	CreatorID       string         `json:"creator_id"`
 	HunterID        *string        `json:"hunter_id,omitempty"`
-	Status          string         `json:"status"`
+	Status          domain.BountyStatus `json:"status"`
 	Deadline        time.Time      `json:"deadline"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
@@ -23,6 +25,35 @@ type Bounty struct {
 	DisputeWinner   *string        `json:"dispute_winner,omitempty"`
 	DisputeResolution *string      `json:"dispute_resolution,omitempty"`
 	ResolvedAt      *time.Time     `json:"resolved_at,omitempty"`
+	VerifiedAt      *time.Time     `json:"verified_at,omitempty"`
+	VerificationError *string      `json:"verification_error,omitempty"`
+	ReopenOnReject  bool           `json:"reopen_on_reject"` // Reopen for other hunters when a submission is rejected
+	CommentPolicy   domain.CommentPolicy `json:"comment_policy" gorm:"default:participants"`
+	CategoryID      *uint          `json:"-"`
+	Category        *Category      `json:"category,omitempty"`
+	Tags            []Tag          `json:"tags" gorm:"many2many:bounty_tags"`
+	Skills          []Skill        `json:"skills" gorm:"many2many:bounty_skills"`
+}
+
+// Participants returns the creator and hunter for use with domain guards.
+func (b *Bounty) Participants() domain.Participants {
+	return domain.Participants{CreatorID: b.CreatorID, HunterID: b.HunterID}
+}
+
+// BountyStatusHistory records every status transition of a bounty.
+type BountyStatusHistory struct {
+	ID         uint                `json:"id" gorm:"primaryKey"`
+	BountyID   uint                `json:"bounty_id" gorm:"index"`
+	FromStatus domain.BountyStatus `json:"from_status"`
+	ToStatus   domain.BountyStatus `json:"to_status"`
+	Action     domain.BountyAction `json:"action"`
+	ActorID    string              `json:"actor_id"`
+	CreatedAt  time.Time           `json:"created_at"`
+}
+
+// TableName keeps the history table name singular.
+func (BountyStatusHistory) TableName() string {
+	return "bounty_status_history"
 }
 
 type BountySubmission struct {
@@ -31,16 +62,57 @@ type BountySubmission struct {
 	HunterID  string    `json:"hunter_id"`
 	Content   string    `json:"content"`
 	IPFSHash  string    `json:"ipfs_hash"`
-	Status    string    `json:"status"`
+	// Encrypted submissions have no Content or IPFSHash. Their content is
+	// sealed to the readers' keys and stored under CiphertextCID.
+	Encrypted     bool   `json:"encrypted"`
+	CiphertextCID string `json:"ciphertext_cid,omitempty"`
+	Status    domain.SubmissionStatus `json:"status"`
+	ReviewNote *string  `json:"review_note,omitempty"`
+	ReviewedBy *string  `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// SubmissionKeyEnvelope holds the content key of an encrypted submission,
+// wrapped for one reader's public key.
+type SubmissionKeyEnvelope struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	SubmissionID uint      `json:"submission_id"`
+	RecipientID  string    `json:"recipient_id"`
+	RecipientKey []byte    `json:"recipient_key"`
+	EphemeralKey []byte    `json:"ephemeral_key"`
+	WrappedKey   []byte    `json:"wrapped_key"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 type BountyComment struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	BountyID  uint      `json:"bounty_id"`
+	ParentID  *uint     `json:"parent_id,omitempty"` // Comment this one replies to
 	UserID    string    `json:"user_id"`
-	Content   string    `json:"content"`
+	Content   string    `json:"content"`      // Markdown as written
+	ContentHTML string  `json:"content_html"` // Rendered and sanitised
+	EditedAt  *time.Time `json:"edited_at,omitempty"`
+	// Deleted comments stay in place so replies keep their parent, but their
+	// content is hidden.
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	DeletedBy *string    `json:"deleted_by,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+// Deleted reports whether the comment was soft-deleted.
+func (c *BountyComment) Deleted() bool {
+	return c.DeletedAt != nil
+}
+
+// BountyCommentRevision is an earlier version of an edited comment.
+type BountyCommentRevision struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CommentID uint      `json:"comment_id"`
+	Content   string    `json:"content"`
+	EditedBy  string    `json:"edited_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
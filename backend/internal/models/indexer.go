@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+// IndexerCursor records how far a chain indexer has progressed.
+type IndexerCursor struct {
+	Name      string    `json:"name" gorm:"primaryKey"`
+	NextBlock uint64    `json:"next_block"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
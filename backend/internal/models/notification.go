@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// Notification is a message shown to a user about activity on a bounty.
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    string     `json:"user_id" gorm:"index"`
+	Type      string     `json:"type"`
+	BountyID  *uint      `json:"bounty_id,omitempty"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
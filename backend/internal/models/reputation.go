@@ -20,15 +20,68 @@ type Badge struct {
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
 	TokenURI    string    `json:"tokenUri"`
+	TokenID     *string   `json:"tokenId,omitempty"` // Set once the badge NFT is minted
 	TxHash      string    `json:"txHash"`
 	CreatedAt   time.Time `json:"createdAt"`
 }
 
-// CalculateLevel calculates the user's level based on their score
-func (r *Reputation) CalculateLevel() {
-	// Simple level calculation: level = score / 100
-	r.Level = r.Score / 100
-	if r.Level < 1 {
-		r.Level = 1
+// BadgeMintStatus is the state of a badge mint job.
+type BadgeMintStatus string
+
+const (
+	MintPending   BadgeMintStatus = "pending"   // waiting to be signed
+	MintSubmitted BadgeMintStatus = "submitted" // signed and broadcast, waiting for a receipt
+	MintMinted    BadgeMintStatus = "minted"
+	MintFailed    BadgeMintStatus = "failed" // gave up after too many attempts
+)
+
+// BadgeMintJob queues a badge to be minted as a Reputation NFT.
+type BadgeMintJob struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	BadgeID       uint            `json:"badgeId" gorm:"uniqueIndex"`
+	Status        BadgeMintStatus `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"nextAttemptAt"`
+	MetadataURI   string          `json:"metadataUri"`
+	TxHash        string          `json:"txHash"`
+	RawTx         []byte          `json:"-"` // Signed transaction, kept for rebroadcasting
+	LastError     string          `json:"lastError"`
+	CreatedAt     time.Time       `json:"createdAt"`
+	UpdatedAt     time.Time       `json:"updatedAt"`
+}
+
+// ReputationReason says why a user's reputation changed.
+type ReputationReason string
+
+const (
+	// ReasonOpeningBalance carries over scores from before the ledger existed.
+	ReasonOpeningBalance   ReputationReason = "opening_balance"
+	ReasonManualAdjustment ReputationReason = "manual_adjustment"
+	ReasonBountyCompleted  ReputationReason = "bounty_completed" // hunter finished a bounty
+	ReasonBountyFunded     ReputationReason = "bounty_funded"    // creator's bounty was completed
+	ReasonDisputeWon       ReputationReason = "dispute_won"
+	ReasonDisputeLost      ReputationReason = "dispute_lost"
+)
+
+// ReputationEvent is one entry in the append-only reputation ledger.
+// Reputation.Score is the sum of a user's event deltas.
+type ReputationEvent struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    string           `json:"userId" gorm:"index"`
+	Delta     int              `json:"delta"`
+	Reason    ReputationReason `json:"reason"`
+	BountyID  *uint            `json:"bountyId,omitempty"`
+	ActorID   string           `json:"actorId"` // Empty for system events
+	CreatedAt time.Time        `json:"createdAt"`
+}
+
+// CalculateLevel sets the level from the score. thresholds[i] is the score
+// needed to reach level i+1; scores below every threshold are level 1.
+func (r *Reputation) CalculateLevel(thresholds []int) {
+	r.Level = 1
+	for i, threshold := range thresholds {
+		if r.Score >= threshold {
+			r.Level = i + 1
+		}
 	}
 }
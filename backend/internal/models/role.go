@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+)
+
+type Role string
+
+const (
+	RoleAdmin     Role = "admin"
+	RoleArbiter   Role = "arbiter"
+	RoleModerator Role = "moderator"
+	RoleUser      Role = "user"
+)
+
+// Valid reports whether r is one of the known roles.
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleArbiter, RoleModerator, RoleUser:
+		return true
+	}
+	return false
+}
+
+// UserRole grants a role to a user. Every authenticated user implicitly holds
+// RoleUser, so only elevated roles need to be stored.
+type UserRole struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    string    `json:"user_id" gorm:"uniqueIndex:idx_user_roles_user_role"`
+	Role      Role      `json:"role" gorm:"uniqueIndex:idx_user_roles_user_role"`
+	GrantedBy string    `json:"granted_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Category is one of a curated list of bounty categories. Categories are
+// managed by admins; bounties reference them by slug.
+type Category struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Slug        string    `json:"slug" gorm:"uniqueIndex"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Tag is a free-form label describing the work, such as "audit" or
+// "frontend". Names are normalised by domain.NormalizeLabel.
+type Tag struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	CreatedAt time.Time
+}
+
+// MarshalJSON encodes a tag as its name.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.Name)
+}
+
+// Skill is a skill a hunter needs to complete a bounty, such as "solidity".
+type Skill struct {
+	ID        uint   `gorm:"primaryKey"`
+	Name      string `gorm:"uniqueIndex"`
+	CreatedAt time.Time
+}
+
+// MarshalJSON encodes a skill as its name.
+func (s Skill) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Name)
+}
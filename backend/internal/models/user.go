@@ -8,8 +8,12 @@ type User struct {
 	ID          string    `json:"id" gorm:"primaryKey"`
 	Username    *string   `json:"username" gorm:"uniqueIndex:idx_users_username,where:username is not null"`
 	Address     string    `json:"address" gorm:"uniqueIndex"`
+	LensProfileID *string `json:"lens_profile_id,omitempty" gorm:"uniqueIndex:idx_users_lens_profile_id,where:lens_profile_id is not null"`
 	Bio         string    `json:"bio"`
 	Avatar      string    `json:"avatar"`
+	// EncryptionPublicKey is the base64 X25519 key private submissions are
+	// encrypted to. It is set through its own endpoint.
+	EncryptionPublicKey *string `json:"encryption_public_key,omitempty"`
 	Reputation  Reputation `json:"reputation" gorm:"foreignKey:UserID;references:ID"`
 	CreatedBounties []Bounty `json:"created_bounties" gorm:"foreignKey:CreatorID"`
 	HuntedBounties  []Bounty `json:"hunted_bounties" gorm:"foreignKey:HunterID"`
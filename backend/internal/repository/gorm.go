@@ -0,0 +1,931 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormStore implements Store on top of a GORM connection. The connection must
+// be opened with TranslateError so duplicate keys are reported as such.
+type GormStore struct {
+	db *gorm.DB
+}
+
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{
+		db: db,
+	}
+}
+
+func (s *GormStore) Bounties() BountyRepository {
+	return &gormBountyRepository{db: s.db}
+}
+
+func (s *GormStore) Submissions() SubmissionRepository {
+	return &gormSubmissionRepository{db: s.db}
+}
+
+func (s *GormStore) Comments() CommentRepository {
+	return &gormCommentRepository{db: s.db}
+}
+
+func (s *GormStore) Taxonomy() TaxonomyRepository {
+	return &gormTaxonomyRepository{db: s.db}
+}
+
+func (s *GormStore) Users() UserRepository {
+	return &gormUserRepository{db: s.db}
+}
+
+func (s *GormStore) Reputations() ReputationRepository {
+	return &gormReputationRepository{db: s.db}
+}
+
+func (s *GormStore) Notifications() NotificationRepository {
+	return &gormNotificationRepository{db: s.db}
+}
+
+func (s *GormStore) Sessions() SessionRepository {
+	return &gormSessionRepository{db: s.db}
+}
+
+func (s *GormStore) Roles() RoleRepository {
+	return &gormRoleRepository{db: s.db}
+}
+
+func (s *GormStore) Nonces() NonceRepository {
+	return &gormNonceRepository{db: s.db}
+}
+
+func (s *GormStore) Cursors() CursorRepository {
+	return &gormCursorRepository{db: s.db}
+}
+
+func (s *GormStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(NewGormStore(tx))
+	})
+}
+
+// paginateQuery runs query for one page. The sort field is validated by
+// ParseSortField, so it is safe to use as a column name.
+func paginateQuery[T any](query *gorm.DB, page PageRequest, key sortKey[T]) (*Page[T], error) {
+	var total int64
+	if err := query.Session(&gorm.Session{}).Model(new(T)).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	column := string(page.sortField())
+	direction, op := "asc", ">"
+	if page.Desc {
+		direction, op = "desc", "<"
+	}
+
+	if page.After != nil {
+		after, err := page.afterValue()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, op), after, page.After.ID)
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	var items []T
+	err := query.
+		Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+		Limit(page.limit() + 1).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return finishPage(items, total, page, key), nil
+}
+
+// translate maps GORM errors to the repository errors.
+func translate(err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
+
+type gormBountyRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormBountyRepository) Create(ctx context.Context, bounty *models.Bounty) error {
+	return translate(r.db.WithContext(ctx).Create(bounty).Error)
+}
+
+func (r *gormBountyRepository) Get(ctx context.Context, id uint) (*models.Bounty, error) {
+	var bounty models.Bounty
+	if err := preloadBountyRelations(r.db.WithContext(ctx)).First(&bounty, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &bounty, nil
+}
+
+func (r *gormBountyRepository) GetForUpdate(ctx context.Context, id uint) (*models.Bounty, error) {
+	db := r.db.WithContext(ctx)
+	var bounty models.Bounty
+	if err := db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&bounty, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	if err := loadBountyRelations(db, &bounty); err != nil {
+		return nil, err
+	}
+	return &bounty, nil
+}
+
+func (r *gormBountyRepository) IDByBlockchainID(ctx context.Context, blockchainID uint) (uint, error) {
+	var bounty models.Bounty
+	err := r.db.WithContext(ctx).Select("id").First(&bounty, "blockchain_id = ?", blockchainID).Error
+	if err != nil {
+		return 0, translate(err)
+	}
+	return bounty.ID, nil
+}
+
+func preloadBountyRelations(db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").Preload("Tags").Preload("Skills")
+}
+
+// loadBountyRelations fills in the category, tags and skills of bounties that
+// were loaded without them, such as locked rows and search results.
+func loadBountyRelations(db *gorm.DB, bounties ...*models.Bounty) error {
+	if len(bounties) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(bounties))
+	for i, b := range bounties {
+		ids[i] = b.ID
+	}
+
+	var loaded []models.Bounty
+	if err := preloadBountyRelations(db).Select("id", "category_id").Find(&loaded, ids).Error; err != nil {
+		return err
+	}
+	byID := make(map[uint]*models.Bounty, len(loaded))
+	for i := range loaded {
+		byID[loaded[i].ID] = &loaded[i]
+	}
+	for _, b := range bounties {
+		if l, ok := byID[b.ID]; ok {
+			b.Category, b.Tags, b.Skills = l.Category, l.Tags, l.Skills
+		}
+	}
+	return nil
+}
+
+func applyBountyFilter(query *gorm.DB, filter BountyFilter) *gorm.DB {
+	if filter.CreatorID != "" {
+		query = query.Where("bounties.creator_id = ?", filter.CreatorID)
+	}
+	if filter.HunterID != "" {
+		query = query.Where("bounties.hunter_id = ?", filter.HunterID)
+	}
+	if filter.Status != "" {
+		query = query.Where("bounties.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("bounties.category_id = (SELECT id FROM categories WHERE slug = ?)", filter.Category)
+	}
+	if filter.MinReward != nil {
+		query = query.Where("bounties.reward >= ?", *filter.MinReward)
+	}
+	if filter.MaxReward != nil {
+		query = query.Where("bounties.reward <= ?", *filter.MaxReward)
+	}
+	if filter.DeadlineAfter != nil {
+		query = query.Where("bounties.deadline >= ?", *filter.DeadlineAfter)
+	}
+	if filter.DeadlineBefore != nil {
+		query = query.Where("bounties.deadline < ?", *filter.DeadlineBefore)
+	}
+	if filter.CreatedSince != nil {
+		query = query.Where("bounties.created_at >= ?", *filter.CreatedSince)
+	}
+	if len(filter.Tags) > 0 {
+		tagged := "SELECT bounty_tags.bounty_id FROM bounty_tags JOIN tags ON tags.id = bounty_tags.tag_id WHERE tags.name IN ?"
+		if filter.MatchAllTags {
+			query = query.Where("bounties.id IN ("+tagged+" GROUP BY bounty_tags.bounty_id HAVING count(*) = ?)", filter.Tags, len(filter.Tags))
+		} else {
+			query = query.Where("bounties.id IN ("+tagged+")", filter.Tags)
+		}
+	}
+	return query
+}
+
+func (r *gormBountyRepository) List(ctx context.Context, filter BountyFilter, page PageRequest) (*Page[models.Bounty], error) {
+	db := r.db.WithContext(ctx)
+	result, err := paginateQuery(applyBountyFilter(db.Model(&models.Bounty{}), filter), page, bountySortKey)
+	if err != nil {
+		return nil, err
+	}
+	bounties := make([]*models.Bounty, len(result.Items))
+	for i := range result.Items {
+		bounties[i] = &result.Items[i]
+	}
+	if err := loadBountyRelations(db, bounties...); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (r *gormBountyRepository) ListIDsByStatus(ctx context.Context, status domain.BountyStatus) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.Bounty{}).
+		Where("status = ?", status).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *gormBountyRepository) ListPastDeadline(ctx context.Context, status domain.BountyStatus, before time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.Bounty{}).
+		Where("status = ? AND deadline > ? AND deadline < ?", status, time.Time{}, before).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *gormBountyRepository) ListStaleClaims(ctx context.Context, before time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.Bounty{}).
+		Where("status = ? AND deadline > ? AND deadline < ?", domain.StatusClaimed, time.Time{}, before).
+		Where("NOT EXISTS (SELECT 1 FROM bounty_submissions s WHERE s.bounty_id = bounties.id AND s.hunter_id = bounties.hunter_id)").
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *gormBountyRepository) Facets(ctx context.Context, filter BountyFilter) (*BountyFacets, error) {
+	db := r.db.WithContext(ctx)
+	facets := &BountyFacets{Status: []FacetCount{}, Tags: []FacetCount{}}
+
+	statusFilter := filter
+	statusFilter.Status = ""
+	err := applyBountyFilter(db.Model(&models.Bounty{}), statusFilter).
+		Select("bounties.status AS value, count(*) AS count").
+		Group("bounties.status").
+		Order("count desc, value asc").
+		Scan(&facets.Status).Error
+	if err != nil {
+		return nil, err
+	}
+
+	tagFilter := filter
+	tagFilter.Tags = nil
+	err = applyBountyFilter(db.Model(&models.Bounty{}), tagFilter).
+		Joins("JOIN bounty_tags ON bounty_tags.bounty_id = bounties.id").
+		Joins("JOIN tags ON tags.id = bounty_tags.tag_id").
+		Select("tags.name AS value, count(*) AS count").
+		Group("tags.name").
+		Order("count desc, value asc").
+		Limit(MaxTagFacets).
+		Scan(&facets.Tags).Error
+	if err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// Snippet options for ts_headline. Titles are short enough to show in full.
+const (
+	titleHeadlineOptions       = "HighlightAll=true, StartSel=<mark>, StopSel=</mark>"
+	descriptionHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" ... \""
+	searchRank                 = "ts_rank(bounties.search_vector, query)::real"
+)
+
+// escapeHTMLSQL escapes a text column so that the only markup in a headline
+// is the <mark> tags added by ts_headline.
+func escapeHTMLSQL(column string) string {
+	return fmt.Sprintf("replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')", column)
+}
+
+func (r *gormBountyRepository) Search(ctx context.Context, q string, filter BountyFilter, page PageRequest) (*Page[BountySearchResult], error) {
+	query := r.db.WithContext(ctx).
+		Table("bounties, websearch_to_tsquery('english', ?) AS query", q).
+		Where("bounties.search_vector @@ query")
+	query = applyBountyFilter(query, filter)
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	if page.After != nil {
+		after, err := page.afterValue()
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s, bounties.id) < (?::real, ?)", searchRank), after, page.After.ID)
+	} else if page.Offset > 0 {
+		query = query.Offset(page.Offset)
+	}
+
+	var results []BountySearchResult
+	err := query.
+		Select(fmt.Sprintf(
+			"bounties.*, %s AS rank, ts_headline('english', %s, query, ?) AS title_highlight, ts_headline('english', %s, query, ?) AS description_highlight",
+			searchRank, escapeHTMLSQL("bounties.title"), escapeHTMLSQL("bounties.description"),
+		), titleHeadlineOptions, descriptionHeadlineOptions).
+		Order("rank desc, bounties.id desc").
+		Limit(page.limit() + 1).
+		Scan(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bounties := make([]*models.Bounty, len(results))
+	for i := range results {
+		bounties[i] = &results[i].Bounty
+	}
+	if err := loadBountyRelations(r.db.WithContext(ctx), bounties...); err != nil {
+		return nil, err
+	}
+	return finishPage(results, total, page, searchSortKey), nil
+}
+
+func (r *gormBountyRepository) Transition(ctx context.Context, bounty *models.Bounty, action domain.BountyAction, actorID string) error {
+	to, err := domain.Transition(bounty.Status, action)
+	if err != nil {
+		return err
+	}
+	return r.Mirror(ctx, bounty, to, action, actorID)
+}
+
+func (r *gormBountyRepository) Mirror(ctx context.Context, bounty *models.Bounty, to domain.BountyStatus, action domain.BountyAction, actorID string) error {
+	from := bounty.Status
+	db := r.db.WithContext(ctx)
+	bounty.Status = to
+	if err := db.Omit(clause.Associations).Save(bounty).Error; err != nil {
+		return translate(err)
+	}
+	if from == to {
+		return nil
+	}
+	return r.AddHistory(ctx, &models.BountyStatusHistory{
+		BountyID:   bounty.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Action:     action,
+		ActorID:    actorID,
+	})
+}
+
+func (r *gormBountyRepository) AddHistory(ctx context.Context, entry *models.BountyStatusHistory) error {
+	return translate(r.db.WithContext(ctx).Create(entry).Error)
+}
+
+func (r *gormBountyRepository) History(ctx context.Context, bountyID uint) ([]models.BountyStatusHistory, error) {
+	var history []models.BountyStatusHistory
+	err := r.db.WithContext(ctx).
+		Where("bounty_id = ?", bountyID).
+		Order("created_at asc, id asc").
+		Find(&history).Error
+	return history, err
+}
+
+func (r *gormBountyRepository) SetCommentPolicy(ctx context.Context, id uint, policy domain.CommentPolicy) error {
+	result := r.db.WithContext(ctx).Model(&models.Bounty{}).Where("id = ?", id).Update("comment_policy", policy)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type gormSubmissionRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSubmissionRepository) Create(ctx context.Context, submission *models.BountySubmission) error {
+	return translate(r.db.WithContext(ctx).Create(submission).Error)
+}
+
+func (r *gormSubmissionRepository) Get(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error) {
+	var submission models.BountySubmission
+	err := r.db.WithContext(ctx).First(&submission, "id = ? AND bounty_id = ?", id, bountyID).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &submission, nil
+}
+
+func (r *gormSubmissionRepository) GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error) {
+	var submission models.BountySubmission
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&submission, "id = ? AND bounty_id = ?", id, bountyID).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &submission, nil
+}
+
+func (r *gormSubmissionRepository) ListByBounty(ctx context.Context, bountyID uint, page PageRequest) (*Page[models.BountySubmission], error) {
+	query := r.db.WithContext(ctx).Model(&models.BountySubmission{}).Where("bounty_id = ?", bountyID)
+	return paginateQuery(query, page, submissionSortKey)
+}
+
+func (r *gormSubmissionRepository) CountByHunter(ctx context.Context, bountyID uint, hunterID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.BountySubmission{}).
+		Where("bounty_id = ? AND hunter_id = ?", bountyID, hunterID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *gormSubmissionRepository) Save(ctx context.Context, submission *models.BountySubmission) error {
+	return translate(r.db.WithContext(ctx).Save(submission).Error)
+}
+
+func (r *gormSubmissionRepository) ObsoletePending(ctx context.Context, bountyID uint) error {
+	return r.db.WithContext(ctx).Model(&models.BountySubmission{}).
+		Where("bounty_id = ? AND status = ?", bountyID, domain.SubmissionPending).
+		Update("status", domain.SubmissionObsolete).Error
+}
+
+func (r *gormSubmissionRepository) AddEnvelope(ctx context.Context, envelope *models.SubmissionKeyEnvelope) error {
+	return translate(r.db.WithContext(ctx).Create(envelope).Error)
+}
+
+func (r *gormSubmissionRepository) GetEnvelope(ctx context.Context, submissionID uint, recipientID string) (*models.SubmissionKeyEnvelope, error) {
+	var envelope models.SubmissionKeyEnvelope
+	err := r.db.WithContext(ctx).
+		First(&envelope, "submission_id = ? AND recipient_id = ?", submissionID, recipientID).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &envelope, nil
+}
+
+type gormCommentRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormCommentRepository) Create(ctx context.Context, comment *models.BountyComment) error {
+	return translate(r.db.WithContext(ctx).Create(comment).Error)
+}
+
+func (r *gormCommentRepository) Get(ctx context.Context, bountyID, id uint) (*models.BountyComment, error) {
+	var comment models.BountyComment
+	err := r.db.WithContext(ctx).First(&comment, "id = ? AND bounty_id = ?", id, bountyID).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &comment, nil
+}
+
+func (r *gormCommentRepository) GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountyComment, error) {
+	var comment models.BountyComment
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&comment, "id = ? AND bounty_id = ?", id, bountyID).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &comment, nil
+}
+
+func (r *gormCommentRepository) Save(ctx context.Context, comment *models.BountyComment) error {
+	return translate(r.db.WithContext(ctx).Save(comment).Error)
+}
+
+func (r *gormCommentRepository) ListByBounty(ctx context.Context, bountyID uint, filter CommentFilter, page PageRequest) (*Page[models.BountyComment], error) {
+	query := r.db.WithContext(ctx).Model(&models.BountyComment{}).Where("bounty_id = ?", bountyID)
+	if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	}
+	if filter.TopLevel {
+		query = query.Where("parent_id IS NULL")
+	}
+	return paginateQuery(query, page, commentSortKey)
+}
+
+func (r *gormCommentRepository) AddRevision(ctx context.Context, revision *models.BountyCommentRevision) error {
+	return translate(r.db.WithContext(ctx).Create(revision).Error)
+}
+
+func (r *gormCommentRepository) ListRevisions(ctx context.Context, commentID uint) ([]models.BountyCommentRevision, error) {
+	var revisions []models.BountyCommentRevision
+	err := r.db.WithContext(ctx).
+		Where("comment_id = ?", commentID).
+		Order("created_at asc, id asc").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+type gormTaxonomyRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormTaxonomyRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Order("name asc").Find(&categories).Error
+	return categories, err
+}
+
+func (r *gormTaxonomyRepository) GetCategory(ctx context.Context, slug string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).First(&category, "slug = ?", slug).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &category, nil
+}
+
+func (r *gormTaxonomyRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	return translate(r.db.WithContext(ctx).Create(category).Error)
+}
+
+func (r *gormTaxonomyRepository) EnsureTags(ctx context.Context, names []string) ([]models.Tag, error) {
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{Name: name}
+	}
+	err := ensureNamed(r.db.WithContext(ctx), names, &tags)
+	return tags, err
+}
+
+func (r *gormTaxonomyRepository) EnsureSkills(ctx context.Context, names []string) ([]models.Skill, error) {
+	skills := make([]models.Skill, len(names))
+	for i, name := range names {
+		skills[i] = models.Skill{Name: name}
+	}
+	err := ensureNamed(r.db.WithContext(ctx), names, &skills)
+	return skills, err
+}
+
+// ensureNamed inserts the rows in dest that do not exist yet, then reloads
+// dest with every row matching names.
+func ensureNamed(db *gorm.DB, names []string, dest interface{}) error {
+	if len(names) == 0 {
+		return nil
+	}
+	err := db.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(dest).Error
+	if err != nil {
+		return err
+	}
+	return db.Where("name IN ?", names).Order("name asc").Find(dest).Error
+}
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return translate(r.db.WithContext(ctx).Omit(clause.Associations).Create(user).Error)
+}
+
+func (r *gormUserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByAddress(ctx context.Context, address string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "address = ?", address).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByLensProfile(ctx context.Context, profileID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "lens_profile_id = ?", profileID).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, "LOWER(username) = LOWER(?)", username).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetProfile(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Preload("Reputation").
+		Preload("Reputation.Badges").
+		Preload("CreatedBounties").
+		Preload("HuntedBounties").
+		First(&user, "id = ?", id).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) Save(ctx context.Context, user *models.User) error {
+	return translate(r.db.WithContext(ctx).Omit(clause.Associations).Save(user).Error)
+}
+
+type gormReputationRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormReputationRepository) Create(ctx context.Context, reputation *models.Reputation) error {
+	return translate(r.db.WithContext(ctx).Create(reputation).Error)
+}
+
+func (r *gormReputationRepository) GetByUserID(ctx context.Context, userID string) (*models.Reputation, error) {
+	var reputation models.Reputation
+	err := r.db.WithContext(ctx).Preload("Badges").Where("user_id = ?", userID).First(&reputation).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &reputation, nil
+}
+
+func (r *gormReputationRepository) GetForUpdate(ctx context.Context, userID string) (*models.Reputation, error) {
+	var reputation models.Reputation
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&reputation).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &reputation, nil
+}
+
+func (r *gormReputationRepository) Save(ctx context.Context, reputation *models.Reputation) error {
+	return translate(r.db.WithContext(ctx).Omit(clause.Associations).Save(reputation).Error)
+}
+
+func (r *gormReputationRepository) AppendEvent(ctx context.Context, event *models.ReputationEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *gormReputationRepository) ListEvents(ctx context.Context, userID string, page PageRequest) (*Page[models.ReputationEvent], error) {
+	query := r.db.WithContext(ctx).Model(&models.ReputationEvent{}).Where("user_id = ?", userID)
+	return paginateQuery(query, page, reputationEventSortKey)
+}
+
+func (r *gormReputationRepository) SumEvents(ctx context.Context, userID string) (int, error) {
+	var total int
+	err := r.db.WithContext(ctx).Model(&models.ReputationEvent{}).
+		Where("user_id = ?", userID).
+		Select("coalesce(sum(delta), 0)").
+		Scan(&total).Error
+	return total, err
+}
+
+func (r *gormReputationRepository) CountEvents(ctx context.Context, userID string, reasons ...models.ReputationReason) (int, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ReputationEvent{}).
+		Where("user_id = ? AND reason IN ?", userID, reasons).
+		Count(&count).Error
+	return int(count), err
+}
+
+func (r *gormReputationRepository) ListUserIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Raw("SELECT user_id FROM reputations UNION SELECT user_id FROM reputation_events ORDER BY user_id").
+		Scan(&ids).Error
+	return ids, err
+}
+
+func (r *gormReputationRepository) HasBadge(ctx context.Context, userID, name string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Badge{}).
+		Where("user_id = ? AND name = ?", userID, name).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormReputationRepository) GetBadge(ctx context.Context, id uint) (*models.Badge, error) {
+	var badge models.Badge
+	if err := r.db.WithContext(ctx).First(&badge, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &badge, nil
+}
+
+func (r *gormReputationRepository) CreateBadge(ctx context.Context, badge *models.Badge) error {
+	return translate(r.db.WithContext(ctx).Create(badge).Error)
+}
+
+func (r *gormReputationRepository) SaveBadge(ctx context.Context, badge *models.Badge) error {
+	return translate(r.db.WithContext(ctx).Save(badge).Error)
+}
+
+func (r *gormReputationRepository) CreateMintJob(ctx context.Context, job *models.BadgeMintJob) error {
+	return translate(r.db.WithContext(ctx).Create(job).Error)
+}
+
+func (r *gormReputationRepository) GetMintJob(ctx context.Context, id uint) (*models.BadgeMintJob, error) {
+	var job models.BadgeMintJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &job, nil
+}
+
+var dueMintStatuses = []models.BadgeMintStatus{models.MintPending, models.MintSubmitted}
+
+func (r *gormReputationRepository) DueMintJobs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.BadgeMintJob{}).
+		Where("status IN ? AND next_attempt_at <= ?", dueMintStatuses, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *gormReputationRepository) LockDueMintJob(ctx context.Context, id uint, now time.Time) (*models.BadgeMintJob, error) {
+	var job models.BadgeMintJob
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status IN ? AND next_attempt_at <= ?", dueMintStatuses, now).
+		First(&job, id).Error
+	if err != nil {
+		return nil, translate(err)
+	}
+	return &job, nil
+}
+
+func (r *gormReputationRepository) SaveMintJob(ctx context.Context, job *models.BadgeMintJob) error {
+	return translate(r.db.WithContext(ctx).Save(job).Error)
+}
+
+type gormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormNotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	return translate(r.db.WithContext(ctx).Create(notification).Error)
+}
+
+func (r *gormNotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var notifications []models.Notification
+	err := query.Order("created_at desc, id desc").Limit(limit).Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *gormNotificationRepository) MarkRead(ctx context.Context, userID string, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ? AND read_at IS NULL", id, userID).
+		Update("read_at", now).Error
+}
+
+type gormSessionRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormSessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return translate(r.db.WithContext(ctx).Create(session).Error)
+}
+
+func (r *gormSessionRepository) Get(ctx context.Context, id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).First(&session, id).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) GetByRefreshHash(ctx context.Context, hash string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.WithContext(ctx).First(&session, "refresh_token_hash = ?", hash).Error; err != nil {
+		return nil, translate(err)
+	}
+	return &session, nil
+}
+
+func (r *gormSessionRepository) Rotate(ctx context.Context, session *models.Session, newHash string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND refresh_token_hash = ?", session.ID, session.RefreshTokenHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": newHash,
+			"last_used_at":       now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return false, nil
+	}
+	session.RefreshTokenHash = newHash
+	session.LastUsedAt = &now
+	return true, nil
+}
+
+func (r *gormSessionRepository) Revoke(ctx context.Context, userID string, id uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now).Error
+}
+
+func (r *gormSessionRepository) RevokeAll(ctx context.Context, userID string, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", now)
+	return result.RowsAffected, result.Error
+}
+
+type gormRoleRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormRoleRepository) List(ctx context.Context, userID string) ([]models.Role, error) {
+	var roles []models.Role
+	err := r.db.WithContext(ctx).Model(&models.UserRole{}).
+		Where("user_id = ?", userID).
+		Order("role").
+		Pluck("role", &roles).Error
+	return roles, err
+}
+
+func (r *gormRoleRepository) HasAny(ctx context.Context, userID string, roles []models.Role) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.UserRole{}).
+		Where("user_id = ? AND role IN ?", userID, roles).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *gormRoleRepository) Grant(ctx context.Context, role *models.UserRole) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(role).Error
+}
+
+func (r *gormRoleRepository) HoldersForUpdate(ctx context.Context, role models.Role) ([]models.UserRole, error) {
+	var holders []models.UserRole
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ?", role).
+		Find(&holders).Error
+	return holders, err
+}
+
+func (r *gormRoleRepository) Revoke(ctx context.Context, userID string, role models.Role) error {
+	result := r.db.WithContext(ctx).Where("user_id = ? AND role = ?", userID, role).Delete(&models.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+type gormNonceRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormNonceRepository) Create(ctx context.Context, nonce *models.AuthNonce) error {
+	return translate(r.db.WithContext(ctx).Create(nonce).Error)
+}
+
+func (r *gormNonceRepository) Consume(ctx context.Context, nonce string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.AuthNonce{}).
+		Where("nonce = ? AND used_at IS NULL AND expires_at > ?", nonce, now).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
+
+type gormCursorRepository struct {
+	db *gorm.DB
+}
+
+func (r *gormCursorRepository) Get(ctx context.Context, name string) (uint64, error) {
+	var cursor models.IndexerCursor
+	if err := r.db.WithContext(ctx).First(&cursor, "name = ?", name).Error; err != nil {
+		return 0, translate(err)
+	}
+	return cursor.NextBlock, nil
+}
+
+func (r *gormCursorRepository) Save(ctx context.Context, name string, next uint64) error {
+	return r.db.WithContext(ctx).Save(&models.IndexerCursor{Name: name, NextBlock: next}).Error
+}
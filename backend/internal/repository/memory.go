@@ -0,0 +1,1337 @@
+package repository
+
+import (
+	"context"
+	"html"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+)
+
+// MemoryStore is an in-memory Store for tests. All access is serialised and a
+// transaction holds the store exclusively, restoring the previous state if it
+// fails, so GetForUpdate needs no extra locking.
+type MemoryStore struct {
+	mu   *sync.Mutex
+	data *memoryData
+	inTx bool
+}
+
+type memoryData struct {
+	nextID        map[string]uint
+	bounties      map[uint]models.Bounty
+	history       []models.BountyStatusHistory
+	submissions   map[uint]models.BountySubmission
+	envelopes     []models.SubmissionKeyEnvelope
+	comments      map[uint]models.BountyComment
+	revisions     []models.BountyCommentRevision
+	categories    map[uint]models.Category
+	tags          map[string]models.Tag   // keyed by name
+	skills        map[string]models.Skill // keyed by name
+	users         map[string]models.User
+	reputations   map[string]models.Reputation // keyed by user ID
+	events        []models.ReputationEvent
+	badges        []models.Badge
+	mintJobs      []models.BadgeMintJob
+	notifications []models.Notification
+	sessions      map[uint]models.Session
+	roles         []models.UserRole
+	nonces        map[string]models.AuthNonce
+	cursors       map[string]uint64
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mu: &sync.Mutex{},
+		data: &memoryData{
+			nextID:      make(map[string]uint),
+			bounties:    make(map[uint]models.Bounty),
+			submissions: make(map[uint]models.BountySubmission),
+			comments:    make(map[uint]models.BountyComment),
+			categories:  make(map[uint]models.Category),
+			tags:        make(map[string]models.Tag),
+			skills:      make(map[string]models.Skill),
+			users:       make(map[string]models.User),
+			reputations: make(map[string]models.Reputation),
+			sessions:    make(map[uint]models.Session),
+			nonces:      make(map[string]models.AuthNonce),
+			cursors:     make(map[string]uint64),
+		},
+	}
+}
+
+func (d *memoryData) clone() *memoryData {
+	c := &memoryData{
+		nextID:        make(map[string]uint, len(d.nextID)),
+		bounties:      make(map[uint]models.Bounty, len(d.bounties)),
+		history:       append([]models.BountyStatusHistory(nil), d.history...),
+		submissions:   make(map[uint]models.BountySubmission, len(d.submissions)),
+		envelopes:     append([]models.SubmissionKeyEnvelope(nil), d.envelopes...),
+		comments:      make(map[uint]models.BountyComment, len(d.comments)),
+		revisions:     append([]models.BountyCommentRevision(nil), d.revisions...),
+		categories:    make(map[uint]models.Category, len(d.categories)),
+		tags:          make(map[string]models.Tag, len(d.tags)),
+		skills:        make(map[string]models.Skill, len(d.skills)),
+		users:         make(map[string]models.User, len(d.users)),
+		reputations:   make(map[string]models.Reputation, len(d.reputations)),
+		events:        append([]models.ReputationEvent(nil), d.events...),
+		badges:        append([]models.Badge(nil), d.badges...),
+		mintJobs:      append([]models.BadgeMintJob(nil), d.mintJobs...),
+		notifications: append([]models.Notification(nil), d.notifications...),
+		sessions:      make(map[uint]models.Session, len(d.sessions)),
+		roles:         append([]models.UserRole(nil), d.roles...),
+		nonces:        make(map[string]models.AuthNonce, len(d.nonces)),
+		cursors:       make(map[string]uint64, len(d.cursors)),
+	}
+	for k, v := range d.nextID {
+		c.nextID[k] = v
+	}
+	for k, v := range d.bounties {
+		c.bounties[k] = v
+	}
+	for k, v := range d.submissions {
+		c.submissions[k] = v
+	}
+	for k, v := range d.comments {
+		c.comments[k] = v
+	}
+	for k, v := range d.categories {
+		c.categories[k] = v
+	}
+	for k, v := range d.tags {
+		c.tags[k] = v
+	}
+	for k, v := range d.skills {
+		c.skills[k] = v
+	}
+	for k, v := range d.users {
+		c.users[k] = v
+	}
+	for k, v := range d.reputations {
+		c.reputations[k] = v
+	}
+	for k, v := range d.sessions {
+		c.sessions[k] = v
+	}
+	for k, v := range d.nonces {
+		c.nonces[k] = v
+	}
+	for k, v := range d.cursors {
+		c.cursors[k] = v
+	}
+	return c
+}
+
+func (d *memoryData) id(table string) uint {
+	d.nextID[table]++
+	return d.nextID[table]
+}
+
+// lock serialises access outside transactions. Inside a transaction the store
+// is already held.
+func (s *MemoryStore) lock() func() {
+	if s.inTx {
+		return func() {}
+	}
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+func (s *MemoryStore) Bounties() BountyRepository {
+	return &memoryBountyRepository{s}
+}
+
+func (s *MemoryStore) Submissions() SubmissionRepository {
+	return &memorySubmissionRepository{s}
+}
+
+func (s *MemoryStore) Comments() CommentRepository {
+	return &memoryCommentRepository{s}
+}
+
+func (s *MemoryStore) Taxonomy() TaxonomyRepository {
+	return &memoryTaxonomyRepository{s}
+}
+
+func (s *MemoryStore) Users() UserRepository {
+	return &memoryUserRepository{s}
+}
+
+func (s *MemoryStore) Reputations() ReputationRepository {
+	return &memoryReputationRepository{s}
+}
+
+func (s *MemoryStore) Notifications() NotificationRepository {
+	return &memoryNotificationRepository{s}
+}
+
+func (s *MemoryStore) Sessions() SessionRepository {
+	return &memorySessionRepository{s}
+}
+
+func (s *MemoryStore) Roles() RoleRepository {
+	return &memoryRoleRepository{s}
+}
+
+func (s *MemoryStore) Nonces() NonceRepository {
+	return &memoryNonceRepository{s}
+}
+
+func (s *MemoryStore) Cursors() CursorRepository {
+	return &memoryCursorRepository{s}
+}
+
+func (s *MemoryStore) Transaction(ctx context.Context, fn func(tx Store) error) error {
+	if s.inTx {
+		return fn(s)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot := s.data.clone()
+	if err := fn(&MemoryStore{mu: s.mu, data: s.data, inTx: true}); err != nil {
+		*s.data = *snapshot
+		return err
+	}
+	return nil
+}
+
+func stamp(createdAt, updatedAt *time.Time) {
+	now := time.Now()
+	if createdAt != nil && createdAt.IsZero() {
+		*createdAt = now
+	}
+	if updatedAt != nil {
+		*updatedAt = now
+	}
+}
+
+type memoryBountyRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryBountyRepository) Create(ctx context.Context, bounty *models.Bounty) error {
+	defer r.s.lock()()
+	for _, b := range r.s.data.bounties {
+		if b.BlockchainID == bounty.BlockchainID {
+			return ErrDuplicate
+		}
+	}
+	bounty.ID = r.s.data.id("bounties")
+	stamp(&bounty.CreatedAt, &bounty.UpdatedAt)
+	r.s.data.bounties[bounty.ID] = *bounty
+	return nil
+}
+
+func (r *memoryBountyRepository) Get(ctx context.Context, id uint) (*models.Bounty, error) {
+	defer r.s.lock()()
+	bounty, ok := r.s.data.bounties[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &bounty, nil
+}
+
+func (r *memoryBountyRepository) GetForUpdate(ctx context.Context, id uint) (*models.Bounty, error) {
+	return r.Get(ctx, id)
+}
+
+func (r *memoryBountyRepository) IDByBlockchainID(ctx context.Context, blockchainID uint) (uint, error) {
+	defer r.s.lock()()
+	for id, b := range r.s.data.bounties {
+		if b.BlockchainID == blockchainID {
+			return id, nil
+		}
+	}
+	return 0, ErrNotFound
+}
+
+func (r *memoryBountyRepository) List(ctx context.Context, filter BountyFilter, page PageRequest) (*Page[models.Bounty], error) {
+	defer r.s.lock()()
+	return paginateSlice(r.filter(filter), page, bountySortKey)
+}
+
+func (r *memoryBountyRepository) ListIDsByStatus(ctx context.Context, status domain.BountyStatus) ([]uint, error) {
+	defer r.s.lock()()
+	ids := []uint{}
+	for _, b := range r.s.data.bounties {
+		if b.Status == status {
+			ids = append(ids, b.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *memoryBountyRepository) ListPastDeadline(ctx context.Context, status domain.BountyStatus, before time.Time) ([]uint, error) {
+	defer r.s.lock()()
+	ids := []uint{}
+	for _, b := range r.s.data.bounties {
+		if b.Status == status && pastDeadline(b, before) {
+			ids = append(ids, b.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (r *memoryBountyRepository) ListStaleClaims(ctx context.Context, before time.Time) ([]uint, error) {
+	defer r.s.lock()()
+	ids := []uint{}
+	for _, b := range r.s.data.bounties {
+		if b.Status != domain.StatusClaimed || b.HunterID == nil || !pastDeadline(b, before) {
+			continue
+		}
+		submitted := false
+		for _, s := range r.s.data.submissions {
+			if s.BountyID == b.ID && s.HunterID == *b.HunterID {
+				submitted = true
+				break
+			}
+		}
+		if !submitted {
+			ids = append(ids, b.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func pastDeadline(b models.Bounty, before time.Time) bool {
+	return !b.Deadline.IsZero() && b.Deadline.Before(before)
+}
+
+func (r *memoryBountyRepository) filter(filter BountyFilter) []models.Bounty {
+	bounties := []models.Bounty{}
+	for _, b := range r.s.data.bounties {
+		if filter.CreatorID != "" && b.CreatorID != filter.CreatorID {
+			continue
+		}
+		if filter.HunterID != "" && (b.HunterID == nil || *b.HunterID != filter.HunterID) {
+			continue
+		}
+		if filter.Status != "" && b.Status != filter.Status {
+			continue
+		}
+		if filter.Category != "" && (b.Category == nil || b.Category.Slug != filter.Category) {
+			continue
+		}
+		if filter.MinReward != nil && b.Reward.LessThan(*filter.MinReward) {
+			continue
+		}
+		if filter.MaxReward != nil && b.Reward.GreaterThan(*filter.MaxReward) {
+			continue
+		}
+		if filter.DeadlineAfter != nil && b.Deadline.Before(*filter.DeadlineAfter) {
+			continue
+		}
+		if filter.DeadlineBefore != nil && !b.Deadline.Before(*filter.DeadlineBefore) {
+			continue
+		}
+		if filter.CreatedSince != nil && b.CreatedAt.Before(*filter.CreatedSince) {
+			continue
+		}
+		if len(filter.Tags) > 0 && !matchTags(b.Tags, filter.Tags, filter.MatchAllTags) {
+			continue
+		}
+		bounties = append(bounties, b)
+	}
+	return bounties
+}
+
+func matchTags(tags []models.Tag, want []string, all bool) bool {
+	matched := 0
+	for _, name := range want {
+		for _, t := range tags {
+			if t.Name == name {
+				matched++
+				break
+			}
+		}
+	}
+	if all {
+		return matched == len(want)
+	}
+	return matched > 0
+}
+
+func (r *memoryBountyRepository) Facets(ctx context.Context, filter BountyFilter) (*BountyFacets, error) {
+	defer r.s.lock()()
+
+	statusFilter := filter
+	statusFilter.Status = ""
+	statuses := make(map[string]int64)
+	for _, b := range r.filter(statusFilter) {
+		statuses[string(b.Status)]++
+	}
+
+	tagFilter := filter
+	tagFilter.Tags = nil
+	tags := make(map[string]int64)
+	for _, b := range r.filter(tagFilter) {
+		for _, t := range b.Tags {
+			tags[t.Name]++
+		}
+	}
+
+	facets := &BountyFacets{Status: facetCounts(statuses), Tags: facetCounts(tags)}
+	if len(facets.Tags) > MaxTagFacets {
+		facets.Tags = facets.Tags[:MaxTagFacets]
+	}
+	return facets, nil
+}
+
+// facetCounts sorts counts most common first, then by value.
+func facetCounts(counts map[string]int64) []FacetCount {
+	facets := make([]FacetCount, 0, len(counts))
+	for value, count := range counts {
+		facets = append(facets, FacetCount{Value: value, Count: count})
+	}
+	sort.Slice(facets, func(i, j int) bool {
+		if facets[i].Count != facets[j].Count {
+			return facets[i].Count > facets[j].Count
+		}
+		return facets[i].Value < facets[j].Value
+	})
+	return facets
+}
+
+// Search approximates Postgres full-text search: every query word must
+// appear in the title or description, and title matches count double.
+func (r *memoryBountyRepository) Search(ctx context.Context, q string, filter BountyFilter, page PageRequest) (*Page[BountySearchResult], error) {
+	defer r.s.lock()()
+
+	terms := strings.Fields(strings.ToLower(q))
+	results := []BountySearchResult{}
+	for _, b := range r.filter(filter) {
+		title, description := strings.ToLower(b.Title), strings.ToLower(b.Description)
+		var rank float32
+		for _, term := range terms {
+			hits := 2*strings.Count(title, term) + strings.Count(description, term)
+			if hits == 0 {
+				rank = 0
+				break
+			}
+			rank += float32(hits)
+		}
+		if rank == 0 {
+			continue
+		}
+		results = append(results, BountySearchResult{
+			Bounty:               b,
+			Rank:                 rank / 10,
+			TitleHighlight:       highlight(b.Title, terms),
+			DescriptionHighlight: highlight(b.Description, terms),
+		})
+	}
+
+	page.Sort, page.Desc = SortRelevance, true
+	return paginateSlice(results, page, searchSortKey)
+}
+
+// highlight HTML-escapes text and wraps each occurrence of terms in <mark>.
+func highlight(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		// Case folding changed byte offsets; skip highlighting
+		return html.EscapeString(text)
+	}
+	marked := make([]bool, len(text))
+	for _, term := range terms {
+		for i := 0; ; {
+			j := strings.Index(lower[i:], term)
+			if j < 0 {
+				break
+			}
+			for k := i + j; k < i+j+len(term); k++ {
+				marked[k] = true
+			}
+			i += j + len(term)
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			b.WriteString("<mark>" + html.EscapeString(text[i:j]) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(text[i:j]))
+		}
+		i = j
+	}
+	return b.String()
+}
+
+func (r *memoryBountyRepository) Transition(ctx context.Context, bounty *models.Bounty, action domain.BountyAction, actorID string) error {
+	to, err := domain.Transition(bounty.Status, action)
+	if err != nil {
+		return err
+	}
+	return r.Mirror(ctx, bounty, to, action, actorID)
+}
+
+func (r *memoryBountyRepository) Mirror(ctx context.Context, bounty *models.Bounty, to domain.BountyStatus, action domain.BountyAction, actorID string) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.bounties[bounty.ID]; !ok {
+		return ErrNotFound
+	}
+
+	from := bounty.Status
+	bounty.Status = to
+	stamp(nil, &bounty.UpdatedAt)
+	r.s.data.bounties[bounty.ID] = *bounty
+	if from == to {
+		return nil
+	}
+	r.addHistory(&models.BountyStatusHistory{
+		BountyID:   bounty.ID,
+		FromStatus: from,
+		ToStatus:   to,
+		Action:     action,
+		ActorID:    actorID,
+	})
+	return nil
+}
+
+func (r *memoryBountyRepository) AddHistory(ctx context.Context, entry *models.BountyStatusHistory) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.bounties[entry.BountyID]; !ok {
+		return ErrNotFound
+	}
+	r.addHistory(entry)
+	return nil
+}
+
+func (r *memoryBountyRepository) addHistory(entry *models.BountyStatusHistory) {
+	entry.ID = r.s.data.id("bounty_status_history")
+	stamp(&entry.CreatedAt, nil)
+	r.s.data.history = append(r.s.data.history, *entry)
+}
+
+func (r *memoryBountyRepository) History(ctx context.Context, bountyID uint) ([]models.BountyStatusHistory, error) {
+	defer r.s.lock()()
+	history := []models.BountyStatusHistory{}
+	for _, h := range r.s.data.history {
+		if h.BountyID == bountyID {
+			history = append(history, h)
+		}
+	}
+	return history, nil
+}
+
+func (r *memoryBountyRepository) SetCommentPolicy(ctx context.Context, id uint, policy domain.CommentPolicy) error {
+	defer r.s.lock()()
+	bounty, ok := r.s.data.bounties[id]
+	if !ok {
+		return ErrNotFound
+	}
+	bounty.CommentPolicy = policy
+	stamp(nil, &bounty.UpdatedAt)
+	r.s.data.bounties[id] = bounty
+	return nil
+}
+
+type memorySubmissionRepository struct {
+	s *MemoryStore
+}
+
+func (r *memorySubmissionRepository) Create(ctx context.Context, submission *models.BountySubmission) error {
+	defer r.s.lock()()
+	submission.ID = r.s.data.id("bounty_submissions")
+	stamp(&submission.CreatedAt, &submission.UpdatedAt)
+	r.s.data.submissions[submission.ID] = *submission
+	return nil
+}
+
+func (r *memorySubmissionRepository) Get(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error) {
+	return r.GetForUpdate(ctx, bountyID, id)
+}
+
+func (r *memorySubmissionRepository) GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error) {
+	defer r.s.lock()()
+	submission, ok := r.s.data.submissions[id]
+	if !ok || submission.BountyID != bountyID {
+		return nil, ErrNotFound
+	}
+	return &submission, nil
+}
+
+func (r *memorySubmissionRepository) ListByBounty(ctx context.Context, bountyID uint, page PageRequest) (*Page[models.BountySubmission], error) {
+	defer r.s.lock()()
+	submissions := []models.BountySubmission{}
+	for _, s := range r.s.data.submissions {
+		if s.BountyID == bountyID {
+			submissions = append(submissions, s)
+		}
+	}
+	return paginateSlice(submissions, page, submissionSortKey)
+}
+
+func (r *memorySubmissionRepository) CountByHunter(ctx context.Context, bountyID uint, hunterID string) (int64, error) {
+	defer r.s.lock()()
+	var count int64
+	for _, s := range r.s.data.submissions {
+		if s.BountyID == bountyID && s.HunterID == hunterID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memorySubmissionRepository) Save(ctx context.Context, submission *models.BountySubmission) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.submissions[submission.ID]; !ok {
+		return ErrNotFound
+	}
+	stamp(nil, &submission.UpdatedAt)
+	r.s.data.submissions[submission.ID] = *submission
+	return nil
+}
+
+func (r *memorySubmissionRepository) ObsoletePending(ctx context.Context, bountyID uint) error {
+	defer r.s.lock()()
+	for id, s := range r.s.data.submissions {
+		if s.BountyID == bountyID && s.Status == domain.SubmissionPending {
+			s.Status = domain.SubmissionObsolete
+			stamp(nil, &s.UpdatedAt)
+			r.s.data.submissions[id] = s
+		}
+	}
+	return nil
+}
+
+func (r *memorySubmissionRepository) AddEnvelope(ctx context.Context, envelope *models.SubmissionKeyEnvelope) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.submissions[envelope.SubmissionID]; !ok {
+		return ErrNotFound
+	}
+	for _, e := range r.s.data.envelopes {
+		if e.SubmissionID == envelope.SubmissionID && e.RecipientID == envelope.RecipientID {
+			return ErrDuplicate
+		}
+	}
+	envelope.ID = r.s.data.id("submission_key_envelopes")
+	stamp(&envelope.CreatedAt, nil)
+	r.s.data.envelopes = append(r.s.data.envelopes, *envelope)
+	return nil
+}
+
+func (r *memorySubmissionRepository) GetEnvelope(ctx context.Context, submissionID uint, recipientID string) (*models.SubmissionKeyEnvelope, error) {
+	defer r.s.lock()()
+	for _, e := range r.s.data.envelopes {
+		if e.SubmissionID == submissionID && e.RecipientID == recipientID {
+			return &e, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+type memoryCommentRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryCommentRepository) Create(ctx context.Context, comment *models.BountyComment) error {
+	defer r.s.lock()()
+	comment.ID = r.s.data.id("bounty_comments")
+	stamp(&comment.CreatedAt, &comment.UpdatedAt)
+	r.s.data.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *memoryCommentRepository) Get(ctx context.Context, bountyID, id uint) (*models.BountyComment, error) {
+	defer r.s.lock()()
+	comment, ok := r.s.data.comments[id]
+	if !ok || comment.BountyID != bountyID {
+		return nil, ErrNotFound
+	}
+	return &comment, nil
+}
+
+func (r *memoryCommentRepository) GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountyComment, error) {
+	return r.Get(ctx, bountyID, id)
+}
+
+func (r *memoryCommentRepository) Save(ctx context.Context, comment *models.BountyComment) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.comments[comment.ID]; !ok {
+		return ErrNotFound
+	}
+	stamp(nil, &comment.UpdatedAt)
+	r.s.data.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *memoryCommentRepository) ListByBounty(ctx context.Context, bountyID uint, filter CommentFilter, page PageRequest) (*Page[models.BountyComment], error) {
+	defer r.s.lock()()
+	comments := []models.BountyComment{}
+	for _, c := range r.s.data.comments {
+		if c.BountyID != bountyID {
+			continue
+		}
+		if filter.ParentID != nil && (c.ParentID == nil || *c.ParentID != *filter.ParentID) {
+			continue
+		}
+		if filter.TopLevel && c.ParentID != nil {
+			continue
+		}
+		comments = append(comments, c)
+	}
+	return paginateSlice(comments, page, commentSortKey)
+}
+
+func (r *memoryCommentRepository) AddRevision(ctx context.Context, revision *models.BountyCommentRevision) error {
+	defer r.s.lock()()
+	revision.ID = r.s.data.id("bounty_comment_revisions")
+	stamp(&revision.CreatedAt, nil)
+	r.s.data.revisions = append(r.s.data.revisions, *revision)
+	return nil
+}
+
+func (r *memoryCommentRepository) ListRevisions(ctx context.Context, commentID uint) ([]models.BountyCommentRevision, error) {
+	defer r.s.lock()()
+	revisions := []models.BountyCommentRevision{}
+	for _, rev := range r.s.data.revisions {
+		if rev.CommentID == commentID {
+			revisions = append(revisions, rev)
+		}
+	}
+	return revisions, nil
+}
+
+type memoryTaxonomyRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryTaxonomyRepository) ListCategories(ctx context.Context) ([]models.Category, error) {
+	defer r.s.lock()()
+	categories := make([]models.Category, 0, len(r.s.data.categories))
+	for _, c := range r.s.data.categories {
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool { return categories[i].Name < categories[j].Name })
+	return categories, nil
+}
+
+func (r *memoryTaxonomyRepository) GetCategory(ctx context.Context, slug string) (*models.Category, error) {
+	defer r.s.lock()()
+	for _, c := range r.s.data.categories {
+		if c.Slug == slug {
+			return &c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryTaxonomyRepository) CreateCategory(ctx context.Context, category *models.Category) error {
+	defer r.s.lock()()
+	for _, c := range r.s.data.categories {
+		if c.Slug == category.Slug {
+			return ErrDuplicate
+		}
+	}
+	category.ID = r.s.data.id("categories")
+	stamp(&category.CreatedAt, nil)
+	r.s.data.categories[category.ID] = *category
+	return nil
+}
+
+func (r *memoryTaxonomyRepository) EnsureTags(ctx context.Context, names []string) ([]models.Tag, error) {
+	defer r.s.lock()()
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tag, ok := r.s.data.tags[name]
+		if !ok {
+			tag = models.Tag{ID: r.s.data.id("tags"), Name: name, CreatedAt: time.Now()}
+			r.s.data.tags[name] = tag
+		}
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, nil
+}
+
+func (r *memoryTaxonomyRepository) EnsureSkills(ctx context.Context, names []string) ([]models.Skill, error) {
+	defer r.s.lock()()
+	skills := make([]models.Skill, 0, len(names))
+	for _, name := range names {
+		skill, ok := r.s.data.skills[name]
+		if !ok {
+			skill = models.Skill{ID: r.s.data.id("skills"), Name: name, CreatedAt: time.Now()}
+			r.s.data.skills[name] = skill
+		}
+		skills = append(skills, skill)
+	}
+	sort.Slice(skills, func(i, j int) bool { return skills[i].Name < skills[j].Name })
+	return skills, nil
+}
+
+type memoryUserRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.users[user.ID]; ok {
+		return ErrDuplicate
+	}
+	if r.conflicts(user) {
+		return ErrDuplicate
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+	r.s.data.users[user.ID] = stripUser(*user)
+	return nil
+}
+
+// conflicts reports whether another user holds user's address or username.
+func (r *memoryUserRepository) conflicts(user *models.User) bool {
+	for _, u := range r.s.data.users {
+		if u.ID == user.ID {
+			continue
+		}
+		if u.Address == user.Address {
+			return true
+		}
+		if u.Username != nil && user.Username != nil && *u.Username == *user.Username {
+			return true
+		}
+		if u.LensProfileID != nil && user.LensProfileID != nil && *u.LensProfileID == *user.LensProfileID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *memoryUserRepository) Get(ctx context.Context, id string) (*models.User, error) {
+	defer r.s.lock()()
+	user, ok := r.s.data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) GetByAddress(ctx context.Context, address string) (*models.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.Address == address {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) GetByLensProfile(ctx context.Context, profileID string) (*models.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.LensProfileID != nil && *u.LensProfileID == profileID {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	defer r.s.lock()()
+	for _, u := range r.s.data.users {
+		if u.Username != nil && strings.EqualFold(*u.Username, username) {
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) GetProfile(ctx context.Context, id string) (*models.User, error) {
+	defer r.s.lock()()
+	user, ok := r.s.data.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	if reputation, ok := r.s.data.reputations[id]; ok {
+		reputation.Badges = badgesOf(r.s.data, id)
+		user.Reputation = reputation
+	}
+	user.CreatedBounties = []models.Bounty{}
+	user.HuntedBounties = []models.Bounty{}
+	for _, b := range r.s.data.bounties {
+		if b.CreatorID == id {
+			user.CreatedBounties = append(user.CreatedBounties, b)
+		}
+		if b.HunterID != nil && *b.HunterID == id {
+			user.HuntedBounties = append(user.HuntedBounties, b)
+		}
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) Save(ctx context.Context, user *models.User) error {
+	defer r.s.lock()()
+	if r.conflicts(user) {
+		return ErrDuplicate
+	}
+	stamp(&user.CreatedAt, &user.UpdatedAt)
+	r.s.data.users[user.ID] = stripUser(*user)
+	return nil
+}
+
+// stripUser drops associations, which are stored in their own tables.
+func stripUser(user models.User) models.User {
+	user.Reputation = models.Reputation{}
+	user.CreatedBounties = nil
+	user.HuntedBounties = nil
+	return user
+}
+
+func badgesOf(d *memoryData, userID string) []models.Badge {
+	badges := []models.Badge{}
+	for _, b := range d.badges {
+		if b.UserID == userID {
+			badges = append(badges, b)
+		}
+	}
+	return badges
+}
+
+type memoryReputationRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryReputationRepository) Create(ctx context.Context, reputation *models.Reputation) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.reputations[reputation.UserID]; ok {
+		return ErrDuplicate
+	}
+	reputation.ID = r.s.data.id("reputations")
+	stamp(&reputation.CreatedAt, &reputation.UpdatedAt)
+	stored := *reputation
+	stored.Badges = nil
+	r.s.data.reputations[reputation.UserID] = stored
+	return nil
+}
+
+func (r *memoryReputationRepository) GetByUserID(ctx context.Context, userID string) (*models.Reputation, error) {
+	defer r.s.lock()()
+	reputation, ok := r.s.data.reputations[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	reputation.Badges = badgesOf(r.s.data, userID)
+	return &reputation, nil
+}
+
+func (r *memoryReputationRepository) GetForUpdate(ctx context.Context, userID string) (*models.Reputation, error) {
+	defer r.s.lock()()
+	reputation, ok := r.s.data.reputations[userID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &reputation, nil
+}
+
+func (r *memoryReputationRepository) Save(ctx context.Context, reputation *models.Reputation) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.reputations[reputation.UserID]; !ok {
+		return ErrNotFound
+	}
+	stamp(nil, &reputation.UpdatedAt)
+	stored := *reputation
+	stored.Badges = nil
+	r.s.data.reputations[reputation.UserID] = stored
+	return nil
+}
+
+func (r *memoryReputationRepository) AppendEvent(ctx context.Context, event *models.ReputationEvent) error {
+	defer r.s.lock()()
+	event.ID = r.s.data.id("reputation_events")
+	stamp(&event.CreatedAt, nil)
+	r.s.data.events = append(r.s.data.events, *event)
+	return nil
+}
+
+func (r *memoryReputationRepository) ListEvents(ctx context.Context, userID string, page PageRequest) (*Page[models.ReputationEvent], error) {
+	defer r.s.lock()()
+	events := []models.ReputationEvent{}
+	for _, e := range r.s.data.events {
+		if e.UserID == userID {
+			events = append(events, e)
+		}
+	}
+	return paginateSlice(events, page, reputationEventSortKey)
+}
+
+func (r *memoryReputationRepository) SumEvents(ctx context.Context, userID string) (int, error) {
+	defer r.s.lock()()
+	total := 0
+	for _, e := range r.s.data.events {
+		if e.UserID == userID {
+			total += e.Delta
+		}
+	}
+	return total, nil
+}
+
+func (r *memoryReputationRepository) CountEvents(ctx context.Context, userID string, reasons ...models.ReputationReason) (int, error) {
+	defer r.s.lock()()
+	count := 0
+	for _, e := range r.s.data.events {
+		if e.UserID != userID {
+			continue
+		}
+		for _, reason := range reasons {
+			if e.Reason == reason {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func (r *memoryReputationRepository) ListUserIDs(ctx context.Context) ([]string, error) {
+	defer r.s.lock()()
+	seen := make(map[string]bool)
+	for id := range r.s.data.reputations {
+		seen[id] = true
+	}
+	for _, e := range r.s.data.events {
+		seen[e.UserID] = true
+	}
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (r *memoryReputationRepository) HasBadge(ctx context.Context, userID, name string) (bool, error) {
+	defer r.s.lock()()
+	for _, b := range r.s.data.badges {
+		if b.UserID == userID && b.Name == name {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryReputationRepository) GetBadge(ctx context.Context, id uint) (*models.Badge, error) {
+	defer r.s.lock()()
+	for _, b := range r.s.data.badges {
+		if b.ID == id {
+			return &b, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryReputationRepository) SaveBadge(ctx context.Context, badge *models.Badge) error {
+	defer r.s.lock()()
+	for i, b := range r.s.data.badges {
+		if b.ID == badge.ID {
+			r.s.data.badges[i] = *badge
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (r *memoryReputationRepository) CreateBadge(ctx context.Context, badge *models.Badge) error {
+	defer r.s.lock()()
+	badge.ID = r.s.data.id("badges")
+	stamp(&badge.CreatedAt, nil)
+	r.s.data.badges = append(r.s.data.badges, *badge)
+	return nil
+}
+
+func (r *memoryReputationRepository) CreateMintJob(ctx context.Context, job *models.BadgeMintJob) error {
+	defer r.s.lock()()
+	for _, j := range r.s.data.mintJobs {
+		if j.BadgeID == job.BadgeID {
+			return ErrDuplicate
+		}
+	}
+	job.ID = r.s.data.id("badge_mint_jobs")
+	stamp(&job.CreatedAt, &job.UpdatedAt)
+	r.s.data.mintJobs = append(r.s.data.mintJobs, *job)
+	return nil
+}
+
+func (r *memoryReputationRepository) GetMintJob(ctx context.Context, id uint) (*models.BadgeMintJob, error) {
+	defer r.s.lock()()
+	for _, j := range r.s.data.mintJobs {
+		if j.ID == id {
+			return &j, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func mintJobDue(job models.BadgeMintJob, now time.Time) bool {
+	return (job.Status == models.MintPending || job.Status == models.MintSubmitted) && !job.NextAttemptAt.After(now)
+}
+
+func (r *memoryReputationRepository) DueMintJobs(ctx context.Context, now time.Time, limit int) ([]uint, error) {
+	defer r.s.lock()()
+	due := []models.BadgeMintJob{}
+	for _, j := range r.s.data.mintJobs {
+		if mintJobDue(j, now) {
+			due = append(due, j)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].NextAttemptAt.Before(due[j].NextAttemptAt) })
+	ids := []uint{}
+	for i := 0; i < len(due) && i < limit; i++ {
+		ids = append(ids, due[i].ID)
+	}
+	return ids, nil
+}
+
+func (r *memoryReputationRepository) LockDueMintJob(ctx context.Context, id uint, now time.Time) (*models.BadgeMintJob, error) {
+	job, err := r.GetMintJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !mintJobDue(*job, now) {
+		return nil, ErrNotFound
+	}
+	return job, nil
+}
+
+func (r *memoryReputationRepository) SaveMintJob(ctx context.Context, job *models.BadgeMintJob) error {
+	defer r.s.lock()()
+	for i, j := range r.s.data.mintJobs {
+		if j.ID == job.ID {
+			stamp(nil, &job.UpdatedAt)
+			r.s.data.mintJobs[i] = *job
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type memoryNotificationRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryNotificationRepository) Create(ctx context.Context, notification *models.Notification) error {
+	defer r.s.lock()()
+	notification.ID = r.s.data.id("notifications")
+	stamp(&notification.CreatedAt, nil)
+	r.s.data.notifications = append(r.s.data.notifications, *notification)
+	return nil
+}
+
+func (r *memoryNotificationRepository) List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error) {
+	defer r.s.lock()()
+	notifications := []models.Notification{}
+	for i := len(r.s.data.notifications) - 1; i >= 0 && len(notifications) < limit; i-- {
+		n := r.s.data.notifications[i]
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, nil
+}
+
+func (r *memoryNotificationRepository) MarkRead(ctx context.Context, userID string, id uint, now time.Time) error {
+	defer r.s.lock()()
+	for i, n := range r.s.data.notifications {
+		if n.ID == id && n.UserID == userID && n.ReadAt == nil {
+			r.s.data.notifications[i].ReadAt = &now
+		}
+	}
+	return nil
+}
+
+type memorySessionRepository struct {
+	s *MemoryStore
+}
+
+func (r *memorySessionRepository) Create(ctx context.Context, session *models.Session) error {
+	defer r.s.lock()()
+	for _, existing := range r.s.data.sessions {
+		if existing.RefreshTokenHash == session.RefreshTokenHash {
+			return ErrDuplicate
+		}
+	}
+	session.ID = r.s.data.id("sessions")
+	stamp(&session.CreatedAt, &session.UpdatedAt)
+	r.s.data.sessions[session.ID] = *session
+	return nil
+}
+
+func (r *memorySessionRepository) Get(ctx context.Context, id uint) (*models.Session, error) {
+	defer r.s.lock()()
+	session, ok := r.s.data.sessions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &session, nil
+}
+
+func (r *memorySessionRepository) GetByRefreshHash(ctx context.Context, hash string) (*models.Session, error) {
+	defer r.s.lock()()
+	for _, session := range r.s.data.sessions {
+		if session.RefreshTokenHash == hash {
+			return &session, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memorySessionRepository) Rotate(ctx context.Context, session *models.Session, newHash string, now time.Time) (bool, error) {
+	defer r.s.lock()()
+	stored, ok := r.s.data.sessions[session.ID]
+	if !ok || stored.RefreshTokenHash != session.RefreshTokenHash {
+		return false, nil
+	}
+	stored.RefreshTokenHash = newHash
+	stored.LastUsedAt = &now
+	stamp(nil, &stored.UpdatedAt)
+	r.s.data.sessions[session.ID] = stored
+	*session = stored
+	return true, nil
+}
+
+func (r *memorySessionRepository) Revoke(ctx context.Context, userID string, id uint, now time.Time) error {
+	defer r.s.lock()()
+	session, ok := r.s.data.sessions[id]
+	if ok && session.UserID == userID && session.RevokedAt == nil {
+		session.RevokedAt = &now
+		r.s.data.sessions[id] = session
+	}
+	return nil
+}
+
+func (r *memorySessionRepository) RevokeAll(ctx context.Context, userID string, now time.Time) (int64, error) {
+	defer r.s.lock()()
+	var revoked int64
+	for id, session := range r.s.data.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &now
+			r.s.data.sessions[id] = session
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+type memoryRoleRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryRoleRepository) List(ctx context.Context, userID string) ([]models.Role, error) {
+	defer r.s.lock()()
+	roles := []models.Role{}
+	for _, grant := range r.s.data.roles {
+		if grant.UserID == userID {
+			roles = append(roles, grant.Role)
+		}
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i] < roles[j] })
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) HasAny(ctx context.Context, userID string, roles []models.Role) (bool, error) {
+	defer r.s.lock()()
+	for _, grant := range r.s.data.roles {
+		if grant.UserID != userID {
+			continue
+		}
+		for _, role := range roles {
+			if grant.Role == role {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRoleRepository) Grant(ctx context.Context, role *models.UserRole) error {
+	defer r.s.lock()()
+	for _, grant := range r.s.data.roles {
+		if grant.UserID == role.UserID && grant.Role == role.Role {
+			return nil
+		}
+	}
+	role.ID = r.s.data.id("user_roles")
+	stamp(&role.CreatedAt, nil)
+	r.s.data.roles = append(r.s.data.roles, *role)
+	return nil
+}
+
+func (r *memoryRoleRepository) HoldersForUpdate(ctx context.Context, role models.Role) ([]models.UserRole, error) {
+	defer r.s.lock()()
+	holders := []models.UserRole{}
+	for _, grant := range r.s.data.roles {
+		if grant.Role == role {
+			holders = append(holders, grant)
+		}
+	}
+	return holders, nil
+}
+
+func (r *memoryRoleRepository) Revoke(ctx context.Context, userID string, role models.Role) error {
+	defer r.s.lock()()
+	for i, grant := range r.s.data.roles {
+		if grant.UserID == userID && grant.Role == role {
+			r.s.data.roles = append(r.s.data.roles[:i:i], r.s.data.roles[i+1:]...)
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+type memoryNonceRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryNonceRepository) Create(ctx context.Context, nonce *models.AuthNonce) error {
+	defer r.s.lock()()
+	if _, ok := r.s.data.nonces[nonce.Nonce]; ok {
+		return ErrDuplicate
+	}
+	nonce.ID = r.s.data.id("auth_nonces")
+	stamp(&nonce.CreatedAt, nil)
+	r.s.data.nonces[nonce.Nonce] = *nonce
+	return nil
+}
+
+func (r *memoryNonceRepository) Consume(ctx context.Context, nonce string, now time.Time) (bool, error) {
+	defer r.s.lock()()
+	stored, ok := r.s.data.nonces[nonce]
+	if !ok || stored.UsedAt != nil || !stored.ExpiresAt.After(now) {
+		return false, nil
+	}
+	stored.UsedAt = &now
+	r.s.data.nonces[nonce] = stored
+	return true, nil
+}
+
+type memoryCursorRepository struct {
+	s *MemoryStore
+}
+
+func (r *memoryCursorRepository) Get(ctx context.Context, name string) (uint64, error) {
+	defer r.s.lock()()
+	next, ok := r.s.data.cursors[name]
+	if !ok {
+		return 0, ErrNotFound
+	}
+	return next, nil
+}
+
+func (r *memoryCursorRepository) Save(ctx context.Context, name string, next uint64) error {
+	defer r.s.lock()()
+	r.s.data.cursors[name] = next
+	return nil
+}
@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort field")
+)
+
+// SortField is a column list results can be ordered by.
+type SortField string
+
+const (
+	SortCreatedAt SortField = "created_at"
+	SortReward    SortField = "reward"
+	SortDeadline  SortField = "deadline"
+	SortRelevance SortField = "relevance" // search results only
+)
+
+// ParseSortField validates a sort field received from a client.
+func ParseSortField(s string) (SortField, error) {
+	switch f := SortField(s); f {
+	case SortCreatedAt, SortReward, SortDeadline, SortRelevance:
+		return f, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidSort, s)
+}
+
+// Cursor marks the last item of a page: its sort value and ID, which breaks
+// ties between equal sort values. It also records the order it was issued
+// for, since its value means nothing in any other.
+type Cursor struct {
+	Sort  SortField `json:"s"`
+	Desc  bool      `json:"d,omitempty"`
+	Value string    `json:"v"`
+	ID    uint      `json:"id"`
+}
+
+// Encode returns the opaque form of c handed to clients.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a cursor produced by Encode.
+func DecodeCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.ID == 0 {
+		return nil, ErrInvalidCursor
+	}
+	if _, err := ParseSortField(string(c.Sort)); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+// Matches reports whether c continues a list in the order of page.
+func (c Cursor) Matches(page PageRequest) bool {
+	return c.Sort == page.sortField() && c.Desc == page.Desc
+}
+
+// PageRequest selects one page of a sorted list. After, if set, continues
+// from a cursor; otherwise Offset rows are skipped.
+type PageRequest struct {
+	Limit  int
+	Offset int
+	After  *Cursor
+	Sort   SortField
+	Desc   bool
+}
+
+// Page is one page of results. NextCursor is nil on the last page.
+type Page[T any] struct {
+	Items      []T     `json:"items"`
+	NextCursor *string `json:"next_cursor"`
+	Total      int64   `json:"total"`
+}
+
+func (p PageRequest) limit() int {
+	if p.Limit <= 0 {
+		return DefaultPageLimit
+	}
+	if p.Limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return p.Limit
+}
+
+func (p PageRequest) sortField() SortField {
+	if p.Sort == "" {
+		return SortCreatedAt
+	}
+	return p.Sort
+}
+
+// afterValue returns the cursor's sort value converted to the field's type.
+// A cursor issued for another order is rejected.
+func (p PageRequest) afterValue() (interface{}, error) {
+	if !p.After.Matches(p) {
+		return nil, ErrInvalidCursor
+	}
+	switch p.sortField() {
+	case SortReward:
+		v, err := decimal.NewFromString(p.After.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return v, nil
+	case SortRelevance:
+		v, err := strconv.ParseFloat(p.After.Value, 32)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return float32(v), nil
+	default:
+		v, err := time.Parse(time.RFC3339Nano, p.After.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return v, nil
+	}
+}
+
+// formatSortValue is the inverse of PageRequest.afterValue.
+func formatSortValue(v interface{}) string {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case decimal.Decimal:
+		return v.String()
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	}
+	return fmt.Sprint(v)
+}
+
+func compareSortValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case decimal.Decimal:
+		return a.Cmp(b.(decimal.Decimal))
+	case float32:
+		b := b.(float32)
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// sortKey extracts the sort value and ID of an item.
+type sortKey[T any] func(item *T, field SortField) (interface{}, uint)
+
+func bountySortKey(b *models.Bounty, field SortField) (interface{}, uint) {
+	switch field {
+	case SortReward:
+		return b.Reward, b.ID
+	case SortDeadline:
+		return b.Deadline, b.ID
+	}
+	return b.CreatedAt, b.ID
+}
+
+func searchSortKey(r *BountySearchResult, _ SortField) (interface{}, uint) {
+	return r.Rank, r.ID
+}
+
+// Submissions, comments and reputation events are only sorted by creation
+// time.
+func submissionSortKey(s *models.BountySubmission, _ SortField) (interface{}, uint) {
+	return s.CreatedAt, s.ID
+}
+
+func commentSortKey(c *models.BountyComment, _ SortField) (interface{}, uint) {
+	return c.CreatedAt, c.ID
+}
+
+func reputationEventSortKey(e *models.ReputationEvent, _ SortField) (interface{}, uint) {
+	return e.CreatedAt, e.ID
+}
+
+// finishPage trims a result fetched with one extra row to the page size and
+// sets the next cursor if that extra row existed.
+func finishPage[T any](items []T, total int64, page PageRequest, key sortKey[T]) *Page[T] {
+	result := &Page[T]{Items: items, Total: total}
+	if len(items) > page.limit() {
+		result.Items = items[:page.limit()]
+		value, id := key(&result.Items[len(result.Items)-1], page.sortField())
+		next := Cursor{Sort: page.sortField(), Desc: page.Desc, Value: formatSortValue(value), ID: id}.Encode()
+		result.NextCursor = &next
+	}
+	if result.Items == nil {
+		result.Items = []T{}
+	}
+	return result
+}
+
+// paginateSlice applies page to items in memory.
+func paginateSlice[T any](items []T, page PageRequest, key sortKey[T]) (*Page[T], error) {
+	field := page.sortField()
+	less := func(a, b *T) bool {
+		va, ida := key(a, field)
+		vb, idb := key(b, field)
+		c := compareSortValues(va, vb)
+		if c == 0 {
+			return ida < idb
+		}
+		return c < 0
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if page.Desc {
+			return less(&items[j], &items[i])
+		}
+		return less(&items[i], &items[j])
+	})
+
+	total := int64(len(items))
+	if page.After != nil {
+		after, err := page.afterValue()
+		if err != nil {
+			return nil, err
+		}
+		start := len(items)
+		for i := range items {
+			v, id := key(&items[i], field)
+			c := compareSortValues(v, after)
+			if c == 0 {
+				c = compareIDs(id, page.After.ID)
+			}
+			if (!page.Desc && c > 0) || (page.Desc && c < 0) {
+				start = i
+				break
+			}
+		}
+		items = items[start:]
+	} else if page.Offset > 0 {
+		if page.Offset >= len(items) {
+			items = nil
+		} else {
+			items = items[page.Offset:]
+		}
+	}
+
+	if len(items) > page.limit()+1 {
+		items = items[:page.limit()+1]
+	}
+	return finishPage(append([]T(nil), items...), total, page, key), nil
+}
+
+func compareIDs(a, b uint) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
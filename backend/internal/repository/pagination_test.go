@@ -0,0 +1,189 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/database/dbtest"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+const pageCreator = "0x00000000000000000000000000000000000000c0"
+
+// testPaginate runs fn against the memory store and against gorm on SQLite,
+// each seeded with bounties whose creation times and rewards are given.
+func testPaginate(t *testing.T, createdAt []time.Time, rewards []int64, fn func(t *testing.T, store Store, ids []uint)) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(*testing.T) Store { return NewMemoryStore() },
+		"gorm":   func(t *testing.T) Store { return NewGormStore(dbtest.Open(t)) },
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			store := open(t)
+			if err := store.Users().Create(ctx, &models.User{ID: pageCreator, Address: pageCreator}); err != nil {
+				t.Fatal(err)
+			}
+			ids := make([]uint, len(createdAt))
+			for i := range createdAt {
+				bounty := models.Bounty{
+					BlockchainID: uint(i + 1),
+					Title:        "Bounty",
+					CreatorID:    pageCreator,
+					Reward:       decimal.NewFromInt(rewards[i]),
+					Status:       domain.StatusOpen,
+					CreatedAt:    createdAt[i],
+				}
+				if err := store.Bounties().Create(ctx, &bounty); err != nil {
+					t.Fatal(err)
+				}
+				ids[i] = bounty.ID
+			}
+			fn(t, store, ids)
+		})
+	}
+}
+
+// walk follows next cursors from the first page and returns the IDs seen on
+// each page.
+func walk(t *testing.T, store Store, page PageRequest) [][]uint {
+	t.Helper()
+	var pages [][]uint
+	for {
+		result, err := store.Bounties().List(context.Background(), BountyFilter{}, page)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]uint, len(result.Items))
+		for i, b := range result.Items {
+			ids[i] = b.ID
+		}
+		pages = append(pages, ids)
+		if result.NextCursor == nil {
+			return pages
+		}
+		if len(pages) > 10 {
+			t.Fatal("pagination does not terminate")
+		}
+		if page.After, err = DecodeCursor(*result.NextCursor); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func equalPages(a, b [][]uint) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if len(a[i]) != len(b[i]) {
+			return false
+		}
+		for j := range a[i] {
+			if a[i][j] != b[i][j] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestCursorPageBoundaries(t *testing.T) {
+	base := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{base, base.Add(time.Hour), base.Add(2 * time.Hour), base.Add(3 * time.Hour)}
+	testPaginate(t, createdAt, []int64{1, 2, 3, 4}, func(t *testing.T, store Store, ids []uint) {
+		// An exact multiple of the limit ends without an empty page
+		if got, want := walk(t, store, PageRequest{Limit: 2}), [][]uint{{ids[0], ids[1]}, {ids[2], ids[3]}}; !equalPages(got, want) {
+			t.Errorf("asc pages = %v, want %v", got, want)
+		}
+		if got, want := walk(t, store, PageRequest{Limit: 3, Desc: true}), [][]uint{{ids[3], ids[2], ids[1]}, {ids[0]}}; !equalPages(got, want) {
+			t.Errorf("desc pages = %v, want %v", got, want)
+		}
+		if got, want := walk(t, store, PageRequest{Limit: 4}), [][]uint{{ids[0], ids[1], ids[2], ids[3]}}; !equalPages(got, want) {
+			t.Errorf("single page = %v, want %v", got, want)
+		}
+
+		result, err := store.Bounties().List(context.Background(), BountyFilter{}, PageRequest{Limit: 2, Offset: 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Items) != 0 || result.NextCursor != nil || result.Total != 4 {
+			t.Errorf("page past the end = %d items, cursor %v, total %d", len(result.Items), result.NextCursor, result.Total)
+		}
+	})
+}
+
+func TestCursorBreaksTiesByID(t *testing.T) {
+	same := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := []time.Time{same, same, same, same, same}
+	testPaginate(t, createdAt, []int64{5, 5, 5, 5, 5}, func(t *testing.T, store Store, ids []uint) {
+		for _, sort := range []SortField{SortCreatedAt, SortReward} {
+			want := [][]uint{{ids[0], ids[1]}, {ids[2], ids[3]}, {ids[4]}}
+			if got := walk(t, store, PageRequest{Limit: 2, Sort: sort}); !equalPages(got, want) {
+				t.Errorf("%s asc pages = %v, want %v", sort, got, want)
+			}
+			want = [][]uint{{ids[4], ids[3]}, {ids[2], ids[1]}, {ids[0]}}
+			if got := walk(t, store, PageRequest{Limit: 2, Sort: sort, Desc: true}); !equalPages(got, want) {
+				t.Errorf("%s desc pages = %v, want %v", sort, got, want)
+			}
+		}
+	})
+}
+
+func TestCursorRejectsOtherOrders(t *testing.T) {
+	base := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	testPaginate(t, []time.Time{base, base.Add(time.Hour), base.Add(2 * time.Hour)}, []int64{1, 2, 3}, func(t *testing.T, store Store, _ []uint) {
+		ctx := context.Background()
+		first, err := store.Bounties().List(ctx, BountyFilter{}, PageRequest{Limit: 1, Sort: SortReward})
+		if err != nil {
+			t.Fatal(err)
+		}
+		after, err := DecodeCursor(*first.NextCursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, page := range []PageRequest{
+			{Limit: 1, Sort: SortCreatedAt, After: after},
+			{Limit: 1, After: after},
+			{Limit: 1, Sort: SortReward, Desc: true, After: after},
+		} {
+			if _, err := store.Bounties().List(ctx, BountyFilter{}, page); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("List(sort %q, desc %v) with a reward cursor: err = %v, want ErrInvalidCursor", page.Sort, page.Desc, err)
+			}
+		}
+	})
+}
+
+func TestDecodeCursor(t *testing.T) {
+	valid := Cursor{Sort: SortReward, Value: "1.5", ID: 3}
+	got, err := DecodeCursor(valid.Encode())
+	if err != nil || *got != valid {
+		t.Fatalf("DecodeCursor(Encode(%+v)) = %+v, %v", valid, got, err)
+	}
+
+	raw := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+	for name, cursor := range map[string]string{
+		"not base64":   "%%%",
+		"not json":     raw("not json"),
+		"no id":        raw(`{"s":"reward","v":"1.5"}`),
+		"no sort":      raw(`{"v":"1.5","id":3}`),
+		"unknown sort": raw(`{"s":"title","v":"a","id":3}`),
+	} {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("%s: err = %v, want ErrInvalidCursor", name, err)
+		}
+	}
+
+	// A cursor whose value was edited for its sort field fails when used
+	edited := Cursor{Sort: SortCreatedAt, Value: "yesterday", ID: 3}
+	page := PageRequest{Sort: SortCreatedAt, After: &edited}
+	if _, err := page.afterValue(); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("malformed created_at value: err = %v, want ErrInvalidCursor", err)
+	}
+}
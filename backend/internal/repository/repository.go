@@ -0,0 +1,290 @@
+// Package repository defines the persistence interfaces used by the HTTP
+// handlers and services, with a GORM implementation for production and an
+// in-memory implementation for tests.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+)
+
+// BountyFilter narrows BountyRepository.List. Zero values match everything.
+type BountyFilter struct {
+	CreatorID string
+	HunterID  string
+	Status    domain.BountyStatus
+	Category  string // category slug
+	// Tags matches bounties with any of the tags, or all of them if
+	// MatchAllTags is set.
+	Tags         []string
+	MatchAllTags bool
+	// Reward bounds are inclusive.
+	MinReward *decimal.Decimal
+	MaxReward *decimal.Decimal
+	// DeadlineAfter is inclusive and DeadlineBefore exclusive, so adjacent
+	// windows do not overlap.
+	DeadlineAfter  *time.Time
+	DeadlineBefore *time.Time
+	CreatedSince   *time.Time
+}
+
+// FacetCount is the number of bounties sharing one value of a field.
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// BountyFacets counts the bounties matching a filter by status and by tag,
+// most common first. Each facet ignores the filter on its own field, so
+// clients can show the counts for the alternatives to the current selection.
+type BountyFacets struct {
+	Status []FacetCount `json:"status"`
+	Tags   []FacetCount `json:"tags"`
+}
+
+// MaxTagFacets caps the number of tags returned in BountyFacets.
+const MaxTagFacets = 50
+
+// BountySearchResult is a bounty matching a full-text search with its rank
+// and highlighted snippets. Snippets are HTML-escaped with matches wrapped in
+// <mark> tags.
+type BountySearchResult struct {
+	models.Bounty
+	Rank                 float32 `json:"rank"`
+	TitleHighlight       string  `json:"title_highlight"`
+	DescriptionHighlight string  `json:"description_highlight"`
+}
+
+type BountyRepository interface {
+	// Create stores a new bounty. It returns ErrDuplicate if a bounty with the
+	// same blockchain ID exists.
+	Create(ctx context.Context, bounty *models.Bounty) error
+	Get(ctx context.Context, id uint) (*models.Bounty, error)
+	// GetForUpdate loads a bounty and locks it until the surrounding
+	// transaction ends.
+	GetForUpdate(ctx context.Context, id uint) (*models.Bounty, error)
+	// IDByBlockchainID returns the ID of the bounty with the given on-chain
+	// ID, or ErrNotFound if it is not stored.
+	IDByBlockchainID(ctx context.Context, blockchainID uint) (uint, error)
+	List(ctx context.Context, filter BountyFilter, page PageRequest) (*Page[models.Bounty], error)
+	// ListIDsByStatus returns the IDs of every bounty in status, in ID order.
+	ListIDsByStatus(ctx context.Context, status domain.BountyStatus) ([]uint, error)
+	// ListPastDeadline returns the IDs of bounties in status whose deadline
+	// is set and earlier than before, in ID order.
+	ListPastDeadline(ctx context.Context, status domain.BountyStatus, before time.Time) ([]uint, error)
+	// ListStaleClaims returns the IDs of claimed bounties whose deadline is
+	// set and earlier than before and whose current hunter has submitted
+	// nothing, in ID order.
+	ListStaleClaims(ctx context.Context, before time.Time) ([]uint, error)
+	Facets(ctx context.Context, filter BountyFilter) (*BountyFacets, error)
+	// Search returns bounties matching a web-search style query, best match
+	// first. Only relevance ordering is supported.
+	Search(ctx context.Context, query string, filter BountyFilter, page PageRequest) (*Page[BountySearchResult], error)
+	// Transition applies action according to the domain transition table,
+	// saves the bounty with any other pending changes and records the status
+	// change in the history. Actions that keep the status are not recorded.
+	Transition(ctx context.Context, bounty *models.Bounty, action domain.BountyAction, actorID string) error
+	// Mirror is Transition without the transition table: it moves the bounty
+	// to a status reported by the chain, which is authoritative.
+	Mirror(ctx context.Context, bounty *models.Bounty, to domain.BountyStatus, action domain.BountyAction, actorID string) error
+	// AddHistory records a status change made outside Transition, such as
+	// one mirrored from the chain.
+	AddHistory(ctx context.Context, entry *models.BountyStatusHistory) error
+	History(ctx context.Context, bountyID uint) ([]models.BountyStatusHistory, error)
+	SetCommentPolicy(ctx context.Context, id uint, policy domain.CommentPolicy) error
+}
+
+type SubmissionRepository interface {
+	Create(ctx context.Context, submission *models.BountySubmission) error
+	// Get loads a submission of the given bounty.
+	Get(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error)
+	// GetForUpdate loads a submission of the given bounty and locks it until
+	// the surrounding transaction ends.
+	GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountySubmission, error)
+	// ListByBounty returns a page of a bounty's submissions sorted by
+	// creation time.
+	ListByBounty(ctx context.Context, bountyID uint, page PageRequest) (*Page[models.BountySubmission], error)
+	// CountByHunter counts the submissions hunterID made to a bounty.
+	CountByHunter(ctx context.Context, bountyID uint, hunterID string) (int64, error)
+	Save(ctx context.Context, submission *models.BountySubmission) error
+	// ObsoletePending marks every pending submission of a bounty obsolete.
+	ObsoletePending(ctx context.Context, bountyID uint) error
+	// AddEnvelope stores a key envelope of an encrypted submission. It
+	// returns ErrDuplicate if the recipient already has one.
+	AddEnvelope(ctx context.Context, envelope *models.SubmissionKeyEnvelope) error
+	// GetEnvelope loads the envelope of a submission held by recipientID.
+	GetEnvelope(ctx context.Context, submissionID uint, recipientID string) (*models.SubmissionKeyEnvelope, error)
+}
+
+// CommentFilter narrows CommentRepository.ListByBounty. The zero value
+// matches every comment of the bounty.
+type CommentFilter struct {
+	ParentID *uint // only direct replies to this comment
+	TopLevel bool  // only comments that are not replies
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *models.BountyComment) error
+	// Get loads a comment of the given bounty, including deleted ones.
+	Get(ctx context.Context, bountyID, id uint) (*models.BountyComment, error)
+	// GetForUpdate loads a comment like Get and locks it until the
+	// surrounding transaction ends.
+	GetForUpdate(ctx context.Context, bountyID, id uint) (*models.BountyComment, error)
+	Save(ctx context.Context, comment *models.BountyComment) error
+	// ListByBounty returns a page of a bounty's comments sorted by creation
+	// time.
+	ListByBounty(ctx context.Context, bountyID uint, filter CommentFilter, page PageRequest) (*Page[models.BountyComment], error)
+	AddRevision(ctx context.Context, revision *models.BountyCommentRevision) error
+	// ListRevisions returns a comment's earlier versions, oldest first.
+	ListRevisions(ctx context.Context, commentID uint) ([]models.BountyCommentRevision, error)
+}
+
+// TaxonomyRepository manages the categories, tags and skills used to
+// classify bounties.
+type TaxonomyRepository interface {
+	ListCategories(ctx context.Context) ([]models.Category, error)
+	GetCategory(ctx context.Context, slug string) (*models.Category, error)
+	// CreateCategory returns ErrDuplicate if the slug is taken.
+	CreateCategory(ctx context.Context, category *models.Category) error
+	// EnsureTags returns the tags with the given normalised names, creating
+	// any that do not exist yet.
+	EnsureTags(ctx context.Context, names []string) ([]models.Tag, error)
+	// EnsureSkills is EnsureTags for skills.
+	EnsureSkills(ctx context.Context, names []string) ([]models.Skill, error)
+}
+
+type UserRepository interface {
+	// Create stores a new user. It returns ErrDuplicate if the ID, address or
+	// username is taken.
+	Create(ctx context.Context, user *models.User) error
+	Get(ctx context.Context, id string) (*models.User, error)
+	// GetByAddress finds a user by lowercase wallet address.
+	GetByAddress(ctx context.Context, address string) (*models.User, error)
+	// GetByUsername finds a user by username, ignoring case.
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	// GetByLensProfile finds the user a Lens profile is linked to.
+	GetByLensProfile(ctx context.Context, profileID string) (*models.User, error)
+	// GetProfile loads a user with their reputation, badges and bounties.
+	GetProfile(ctx context.Context, id string) (*models.User, error)
+	Save(ctx context.Context, user *models.User) error
+}
+
+type ReputationRepository interface {
+	Create(ctx context.Context, reputation *models.Reputation) error
+	// GetByUserID loads a user's reputation with their badges.
+	GetByUserID(ctx context.Context, userID string) (*models.Reputation, error)
+	// GetForUpdate loads a user's reputation without badges and locks it
+	// until the surrounding transaction ends.
+	GetForUpdate(ctx context.Context, userID string) (*models.Reputation, error)
+	Save(ctx context.Context, reputation *models.Reputation) error
+	// AppendEvent adds an event to the ledger. Events are never changed.
+	AppendEvent(ctx context.Context, event *models.ReputationEvent) error
+	// ListEvents returns a page of a user's events sorted by creation time.
+	ListEvents(ctx context.Context, userID string, page PageRequest) (*Page[models.ReputationEvent], error)
+	// SumEvents returns the total of a user's event deltas.
+	SumEvents(ctx context.Context, userID string) (int, error)
+	// CountEvents returns how many of a user's events have one of reasons.
+	CountEvents(ctx context.Context, userID string, reasons ...models.ReputationReason) (int, error)
+	// ListUserIDs returns every user with a reputation or ledger events.
+	ListUserIDs(ctx context.Context) ([]string, error)
+	HasBadge(ctx context.Context, userID, name string) (bool, error)
+	GetBadge(ctx context.Context, id uint) (*models.Badge, error)
+	CreateBadge(ctx context.Context, badge *models.Badge) error
+	SaveBadge(ctx context.Context, badge *models.Badge) error
+	// CreateMintJob queues a badge to be minted on-chain.
+	CreateMintJob(ctx context.Context, job *models.BadgeMintJob) error
+	GetMintJob(ctx context.Context, id uint) (*models.BadgeMintJob, error)
+	// DueMintJobs returns the IDs of up to limit pending or submitted mint
+	// jobs whose next attempt is due at now, earliest first.
+	DueMintJobs(ctx context.Context, now time.Time, limit int) ([]uint, error)
+	// LockDueMintJob loads a mint job that is still due at now and locks it
+	// until the surrounding transaction ends. It returns ErrNotFound if the
+	// job is no longer due or another transaction holds it.
+	LockDueMintJob(ctx context.Context, id uint, now time.Time) (*models.BadgeMintJob, error)
+	SaveMintJob(ctx context.Context, job *models.BadgeMintJob) error
+}
+
+type NotificationRepository interface {
+	Create(ctx context.Context, notification *models.Notification) error
+	// List returns up to limit of a user's notifications, newest first.
+	List(ctx context.Context, userID string, unreadOnly bool, limit int) ([]models.Notification, error)
+	// MarkRead marks one of the user's unread notifications as read.
+	MarkRead(ctx context.Context, userID string, id uint, now time.Time) error
+}
+
+// SessionRepository stores login sessions. Refresh tokens are only known by
+// their hash.
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	Get(ctx context.Context, id uint) (*models.Session, error)
+	GetByRefreshHash(ctx context.Context, hash string) (*models.Session, error)
+	// Rotate replaces the session's refresh token hash if it is still
+	// session.RefreshTokenHash, and reports whether it did. A false result
+	// means a concurrent request rotated the token first.
+	Rotate(ctx context.Context, session *models.Session, newHash string, now time.Time) (bool, error)
+	// Revoke ends one active session of userID.
+	Revoke(ctx context.Context, userID string, id uint, now time.Time) error
+	// RevokeAll ends every active session of userID and returns how many
+	// were revoked.
+	RevokeAll(ctx context.Context, userID string, now time.Time) (int64, error)
+}
+
+// RoleRepository stores elevated roles. RoleUser is implicit and never
+// stored.
+type RoleRepository interface {
+	// List returns the roles granted to a user, sorted by name.
+	List(ctx context.Context, userID string) ([]models.Role, error)
+	HasAny(ctx context.Context, userID string, roles []models.Role) (bool, error)
+	// Grant stores a role. Granting a role the user holds is a no-op.
+	Grant(ctx context.Context, role *models.UserRole) error
+	// HoldersForUpdate returns the grants of a role and locks them until the
+	// surrounding transaction ends.
+	HoldersForUpdate(ctx context.Context, role models.Role) ([]models.UserRole, error)
+	// Revoke deletes a grant. It returns ErrNotFound if there is none.
+	Revoke(ctx context.Context, userID string, role models.Role) error
+}
+
+// NonceRepository stores Sign-In with Ethereum nonces.
+type NonceRepository interface {
+	Create(ctx context.Context, nonce *models.AuthNonce) error
+	// Consume marks a nonce as used if it is unused and unexpired at now, and
+	// reports whether it was.
+	Consume(ctx context.Context, nonce string, now time.Time) (bool, error)
+}
+
+// CursorRepository stores how far background readers such as the chain
+// indexer have progressed.
+type CursorRepository interface {
+	// Get returns the next position stored under name, or ErrNotFound.
+	Get(ctx context.Context, name string) (uint64, error)
+	Save(ctx context.Context, name string, next uint64) error
+}
+
+// Store gives access to every repository and runs units of work atomically.
+type Store interface {
+	Bounties() BountyRepository
+	Submissions() SubmissionRepository
+	Comments() CommentRepository
+	Taxonomy() TaxonomyRepository
+	Users() UserRepository
+	Reputations() ReputationRepository
+	Notifications() NotificationRepository
+	Sessions() SessionRepository
+	Roles() RoleRepository
+	Nonces() NonceRepository
+	Cursors() CursorRepository
+
+	// Transaction runs fn with a Store whose repositories all share one
+	// transaction. It commits if fn returns nil and rolls back otherwise.
+	Transaction(ctx context.Context, fn func(tx Store) error) error
+}
@@ -0,0 +1,315 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/bountyBoard/internal/storage"
+	"github.com/bountyBoard/pkg/sealed"
+)
+
+var (
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+	ErrAttachmentType     = errors.New("attachment type is not allowed")
+	ErrTooManyAttachments = errors.New("too many attachments")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+	ErrInvalidManifest    = errors.New("invalid attachment manifest")
+)
+
+// AttachmentLimits restrict what hunters may attach to a submission.
+type AttachmentLimits struct {
+	MaxFileSize  int64    // bytes per file
+	MaxTotalSize int64    // bytes per submission, across all files
+	MaxFiles     int      // files per submission
+	AllowedTypes []string // MIME types detected from file content
+}
+
+func DefaultAttachmentLimits() AttachmentLimits {
+	return AttachmentLimits{
+		MaxFileSize:  25 << 20,
+		MaxTotalSize: 100 << 20,
+		MaxFiles:     10,
+		AllowedTypes: []string{
+			"text/plain",
+			"application/pdf",
+			"application/zip",
+			"application/x-gzip",
+			"image/png",
+			"image/jpeg",
+			"image/gif",
+			"image/webp",
+		},
+	}
+}
+
+// AttachmentLimitsFromEnv reads SUBMISSION_MAX_FILE_SIZE,
+// SUBMISSION_MAX_UPLOAD_SIZE (both in bytes), SUBMISSION_MAX_FILES and
+// SUBMISSION_ALLOWED_TYPES (comma-separated), falling back to the defaults.
+func AttachmentLimitsFromEnv() (AttachmentLimits, error) {
+	limits := DefaultAttachmentLimits()
+
+	sizes := []struct {
+		name string
+		dest *int64
+	}{
+		{"SUBMISSION_MAX_FILE_SIZE", &limits.MaxFileSize},
+		{"SUBMISSION_MAX_UPLOAD_SIZE", &limits.MaxTotalSize},
+	}
+	for _, setting := range sizes {
+		v := os.Getenv(setting.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n <= 0 {
+			return limits, fmt.Errorf("invalid %s: %q", setting.name, v)
+		}
+		*setting.dest = n
+	}
+
+	if v := os.Getenv("SUBMISSION_MAX_FILES"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			return limits, fmt.Errorf("invalid SUBMISSION_MAX_FILES: %q", v)
+		}
+		limits.MaxFiles = n
+	}
+
+	if v := os.Getenv("SUBMISSION_ALLOWED_TYPES"); v != "" {
+		limits.AllowedTypes = nil
+		for _, t := range strings.Split(v, ",") {
+			mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(t))
+			if err != nil {
+				return limits, fmt.Errorf("invalid SUBMISSION_ALLOWED_TYPES entry %q", t)
+			}
+			limits.AllowedTypes = append(limits.AllowedTypes, mediaType)
+		}
+	}
+	return limits, nil
+}
+
+// attachmentLimits are the limits used by new AttachmentServices.
+var attachmentLimits = DefaultAttachmentLimits()
+
+// SetAttachmentLimits replaces the limits used by AttachmentServices created
+// afterwards. It is meant to be called once at startup.
+func SetAttachmentLimits(limits AttachmentLimits) {
+	attachmentLimits = limits
+}
+
+// manifestVersion is the version of the manifest format written.
+const manifestVersion = 1
+
+// AttachmentManifest lists the files of a submission. It is stored in the
+// content store and its CID is recorded on the submission.
+type AttachmentManifest struct {
+	Version int          `json:"version"`
+	Files   []Attachment `json:"files"`
+}
+
+type Attachment struct {
+	Name     string `json:"name"`
+	CID      string `json:"cid"`
+	Size     int64  `json:"size"`
+	MimeType string `json:"mime_type"`
+}
+
+// File returns the attachment with the given CID.
+func (m *AttachmentManifest) File(cid string) (*Attachment, bool) {
+	for i := range m.Files {
+		if m.Files[i].CID == cid {
+			return &m.Files[i], true
+		}
+	}
+	return nil, false
+}
+
+// AttachmentService stores submission attachments in the content store.
+// Uploads are never unpinned, even if the submission fails, because the
+// same content may be referenced by other submissions under the same CID.
+type AttachmentService struct {
+	content storage.ContentStore
+	limits  AttachmentLimits
+}
+
+func NewAttachmentService(content storage.ContentStore) *AttachmentService {
+	return &AttachmentService{
+		content: content,
+		limits:  attachmentLimits,
+	}
+}
+
+func (s *AttachmentService) Limits() AttachmentLimits {
+	return s.limits
+}
+
+// Upload streams one file to the content store. Its type is detected from
+// the first bytes rather than trusted from the client, and the upload fails
+// with ErrAttachmentTooLarge once it exceeds maxSize.
+func (s *AttachmentService) Upload(ctx context.Context, filename string, r io.Reader, maxSize int64) (*Attachment, error) {
+	if maxSize > s.limits.MaxFileSize {
+		maxSize = s.limits.MaxFileSize
+	}
+
+	buffered := bufio.NewReaderSize(r, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.allowed(mimeType) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentType, mimeType)
+	}
+
+	counted := &sizeLimitReader{r: buffered, remaining: maxSize}
+	c, err := s.content.Put(ctx, counted)
+	if counted.exceeded {
+		return nil, ErrAttachmentTooLarge
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Attachment{
+		Name:     sanitizeFilename(filename),
+		CID:      c.String(),
+		Size:     maxSize - counted.remaining,
+		MimeType: mimeType,
+	}, nil
+}
+
+// UploadCiphertext streams the ciphertext of an encrypted submission to the
+// content store. It counts against the same size limit as attachments. Only
+// the header is checked, the server cannot decrypt the rest.
+func (s *AttachmentService) UploadCiphertext(ctx context.Context, r io.Reader, maxSize int64) (string, error) {
+	r, err := sealed.ReadHeader(r)
+	if err != nil {
+		return "", err
+	}
+	counted := &sizeLimitReader{r: r, remaining: maxSize}
+	c, err := s.content.Put(ctx, counted)
+	if counted.exceeded {
+		return "", ErrAttachmentTooLarge
+	}
+	if err != nil {
+		return "", err
+	}
+	if maxSize-counted.remaining < sealed.Overhead {
+		return "", fmt.Errorf("%w: too short", sealed.ErrInvalidCiphertext)
+	}
+	return c.String(), nil
+}
+
+func (s *AttachmentService) allowed(mimeType string) bool {
+	for _, t := range s.limits.AllowedTypes {
+		if t == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveManifest stores a manifest listing files and returns its CID.
+func (s *AttachmentService) SaveManifest(ctx context.Context, files []Attachment) (string, error) {
+	data, err := json.Marshal(AttachmentManifest{Version: manifestVersion, Files: files})
+	if err != nil {
+		return "", err
+	}
+	c, err := s.content.Put(ctx, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// Manifest loads the manifest stored under cid.
+func (s *AttachmentService) Manifest(ctx context.Context, cid string) (*AttachmentManifest, error) {
+	content, err := s.open(ctx, cid)
+	if err != nil {
+		return nil, err
+	}
+	defer content.Close()
+
+	// Read to the end so the content is checked against its CID
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	var manifest AttachmentManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidManifest, err)
+	}
+	if manifest.Version != manifestVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidManifest, manifest.Version)
+	}
+	return &manifest, nil
+}
+
+// Open opens an attachment's content. Reading it fails if the content does
+// not match its CID.
+func (s *AttachmentService) Open(ctx context.Context, attachment *Attachment) (io.ReadCloser, error) {
+	return s.open(ctx, attachment.CID)
+}
+
+func (s *AttachmentService) open(ctx context.Context, cid string) (io.ReadCloser, error) {
+	c, err := storage.ParseCID(cid)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.content.Get(ctx, c)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, ErrAttachmentNotFound
+	}
+	return content, err
+}
+
+// sizeLimitReader fails once more than remaining bytes are read.
+type sizeLimitReader struct {
+	r         io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	if int64(n) > l.remaining {
+		l.exceeded = true
+		return 0, ErrAttachmentTooLarge
+	}
+	l.remaining -= int64(n)
+	return n, err
+}
+
+// sanitizeFilename keeps the base name of a client-supplied file name and
+// drops control characters, so it is safe to echo in a Content-Disposition
+// header.
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	if len([]rune(name)) > 255 {
+		name = string([]rune(name)[:255])
+	}
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
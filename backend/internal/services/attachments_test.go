@@ -0,0 +1,113 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bountyBoard/internal/storage"
+)
+
+func newTestAttachmentService(limits AttachmentLimits) (*AttachmentService, *storage.MemoryStore) {
+	content := storage.NewMemoryStore()
+	return &AttachmentService{content: content, limits: limits}, content
+}
+
+// pngHeader is the signature http.DetectContentType recognises as image/png.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestUploadSizeLimits(t *testing.T) {
+	limits := DefaultAttachmentLimits()
+	limits.MaxFileSize = 1024
+	s, _ := newTestAttachmentService(limits)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		size    int
+		maxSize int64
+		wantErr error
+	}{
+		{"empty", 0, 4096, nil},
+		{"under the file limit", 1000, 4096, nil},
+		{"at the file limit", 1024, 4096, nil},
+		{"over the file limit", 1025, 4096, ErrAttachmentTooLarge},
+		{"at what remains of the upload", 512, 512, nil},
+		{"over what remains of the upload", 513, 512, ErrAttachmentTooLarge},
+		{"far over the limit", 1 << 20, 4096, ErrAttachmentTooLarge},
+	}
+	for _, tt := range tests {
+		file, err := s.Upload(ctx, "notes.txt", strings.NewReader(strings.Repeat("a", tt.size)), tt.maxSize)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil && file.Size != int64(tt.size) {
+			t.Errorf("%s: size = %d, want %d", tt.name, file.Size, tt.size)
+		}
+	}
+}
+
+func TestUploadDetectsType(t *testing.T) {
+	limits := DefaultAttachmentLimits()
+	limits.AllowedTypes = []string{"text/plain", "image/png"}
+	s, content := newTestAttachmentService(limits)
+	ctx := context.Background()
+
+	png := append(append([]byte{}, pngHeader...), make([]byte, 64)...)
+	tests := []struct {
+		name     string
+		filename string
+		data     []byte
+		wantType string
+		wantErr  error
+	}{
+		{"text", "notes.txt", []byte("Steps to reproduce"), "text/plain", nil},
+		{"png", "shot.png", png, "image/png", nil},
+		// The file name and client headers are never trusted
+		{"text named as an image", "shot.png", []byte("just text"), "text/plain", nil},
+		{"html named as text", "notes.txt", []byte("<html><script>alert(1)</script></html>"), "", ErrAttachmentType},
+		{"pdf not allowed here", "paper.pdf", []byte("%PDF-1.7\n"), "", ErrAttachmentType},
+		{"binary", "tool.txt", []byte{0x7f, 'E', 'L', 'F', 2, 1, 1, 0}, "", ErrAttachmentType},
+	}
+	for _, tt := range tests {
+		file, err := s.Upload(ctx, tt.filename, bytes.NewReader(tt.data), 1<<20)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			// Rejected files are never stored
+			c, cerr := storage.ComputeCID(bytes.NewReader(tt.data))
+			if cerr != nil {
+				t.Fatal(cerr)
+			}
+			if _, serr := content.Stat(ctx, c); !errors.Is(serr, storage.ErrNotFound) {
+				t.Errorf("%s: rejected file was stored (stat err = %v)", tt.name, serr)
+			}
+			continue
+		}
+		if file.MimeType != tt.wantType || file.Name != tt.filename {
+			t.Errorf("%s: file = %+v, want %s named %s", tt.name, file, tt.wantType, tt.filename)
+		}
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":            "report.pdf",
+		"../../etc/passwd":      "passwd",
+		`C:\Users\me\notes.txt`: "notes.txt",
+		"bad\"name\r\n.txt":     "badname.txt",
+		"":                      "attachment",
+		"/":                     "attachment",
+		"..":                    "attachment",
+	}
+	for name, want := range tests {
+		if got := sanitizeFilename(name); got != want {
+			t.Errorf("sanitizeFilename(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+// Actor IDs recorded in the status history for transitions made by the
+// backend itself rather than a user.
+const (
+	ActorVerifier  = "system:verifier"
+	ActorIndexer   = "system:indexer"
+	ActorScheduler = "system:scheduler"
+)
+
+var ErrBountyNotFound = errors.New("bounty not found")
+
+// WithLockedBounty loads the bounty with a row lock inside a transaction and
+// runs fn with it. Concurrent callers for the same bounty are serialised, so
+// the loser of a race sees the winner's status and fails its transition check
+// instead of overwriting it. It returns the bounty as fn left it.
+func WithLockedBounty(ctx context.Context, store repository.Store, id uint, fn func(tx repository.Store, bounty *models.Bounty) error) (*models.Bounty, error) {
+	var bounty *models.Bounty
+	err := store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		bounty, err = tx.Bounties().GetForUpdate(ctx, id)
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBountyNotFound
+		}
+		if err != nil {
+			return err
+		}
+		return fn(tx, bounty)
+	})
+	return bounty, err
+}
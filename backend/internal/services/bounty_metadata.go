@@ -0,0 +1,258 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/shopspring/decimal"
+)
+
+// BountyMetadataVersion is the version of the metadata schema written.
+const BountyMetadataVersion = 1
+
+//go:embed bounty_metadata.v1.schema.json
+var bountyMetadataSchema []byte
+
+// BountyMetadataSchema returns the JSON Schema of metadata documents.
+func BountyMetadataSchema() []byte {
+	return bountyMetadataSchema
+}
+
+var (
+	ErrInvalidBountyMetadata  = errors.New("invalid bounty metadata")
+	ErrBountyMetadataNotFound = errors.New("bounty metadata not found")
+)
+
+// maxBountyMetadataSize caps documents read back from the content store.
+const maxBountyMetadataSize = 256 << 10
+
+var (
+	rewardPattern  = regexp.MustCompile(`^(0|[1-9][0-9]*)(\.[0-9]{1,18})?$`)
+	addressPattern = regexp.MustCompile(`^0x[0-9a-f]{40}$`)
+)
+
+// BountyMetadata is the canonical description of a bounty, defined by
+// bounty_metadata.v1.schema.json. It is pinned to the content store and its
+// CID is both the bounty's IPFSHash and, as ipfs://<cid>, the metadata string
+// passed to the contract.
+type BountyMetadata struct {
+	Version     int       `json:"version"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Reward      string    `json:"reward"` // whole tokens
+	Deadline    time.Time `json:"deadline"`
+	Creator     string    `json:"creator"`
+	Category    string    `json:"category,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Skills      []string  `json:"skills,omitempty"`
+}
+
+// ParseBountyMetadata decodes and validates a metadata document. Unknown
+// fields are rejected, as the schema does not allow them.
+func ParseBountyMetadata(data []byte) (*BountyMetadata, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var metadata BountyMetadata
+	if err := decoder.Decode(&metadata); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBountyMetadata, err)
+	}
+	if err := metadata.Validate(); err != nil {
+		return nil, err
+	}
+	return &metadata, nil
+}
+
+// Validate checks the document against the schema.
+func (m *BountyMetadata) Validate() error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidBountyMetadata, fmt.Sprintf(format, args...))
+	}
+
+	if m.Version != BountyMetadataVersion {
+		return invalid("unsupported version %d", m.Version)
+	}
+	if n := utf8.RuneCountInString(m.Title); strings.TrimSpace(m.Title) == "" || n > 200 {
+		return invalid("title must be 1 to 200 characters")
+	}
+	if n := utf8.RuneCountInString(m.Description); strings.TrimSpace(m.Description) == "" || n > 50000 {
+		return invalid("description must be 1 to 50000 characters")
+	}
+	if !rewardPattern.MatchString(m.Reward) || m.RewardAmount().Sign() <= 0 {
+		return invalid("reward must be a positive decimal with at most 18 decimals")
+	}
+	if m.Deadline.IsZero() {
+		return invalid("deadline is required")
+	}
+	if !addressPattern.MatchString(m.Creator) {
+		return invalid("creator must be a lowercase wallet address")
+	}
+	if m.Category != "" {
+		if label, err := domain.NormalizeLabel(m.Category); err != nil || label != m.Category {
+			return invalid("category %q is not a category slug", m.Category)
+		}
+	}
+	if err := validateLabels("tags", m.Tags, domain.MaxTagsPerBounty); err != nil {
+		return invalid("%v", err)
+	}
+	if err := validateLabels("skills", m.Skills, domain.MaxSkillsPerBounty); err != nil {
+		return invalid("%v", err)
+	}
+	return nil
+}
+
+// validateLabels requires labels to be normalised and distinct already, so
+// the document is stored exactly as the bounty uses it.
+func validateLabels(field string, labels []string, max int) error {
+	normalized, err := domain.NormalizeLabels(labels, max)
+	if err != nil {
+		return fmt.Errorf("%s: %v", field, err)
+	}
+	if len(normalized) != len(labels) {
+		return fmt.Errorf("%s must be distinct", field)
+	}
+	for i := range labels {
+		if labels[i] != normalized[i] {
+			return fmt.Errorf("%s: %q is not normalised", field, labels[i])
+		}
+	}
+	return nil
+}
+
+// RewardAmount returns the reward as a decimal, or zero if it is malformed.
+func (m *BountyMetadata) RewardAmount() decimal.Decimal {
+	reward, err := decimal.NewFromString(m.Reward)
+	if err != nil {
+		return decimal.Zero
+	}
+	return reward
+}
+
+// Mismatch returns the name of the first field that differs between m and
+// other, or "" if they describe the same bounty. Rewards and deadlines are
+// compared by value and labels regardless of order.
+func (m *BountyMetadata) Mismatch(other *BountyMetadata) string {
+	switch {
+	case m.Title != other.Title:
+		return "title"
+	case m.Description != other.Description:
+		return "description"
+	case !m.RewardAmount().Equal(other.RewardAmount()):
+		return "reward"
+	case !m.Deadline.Equal(other.Deadline):
+		return "deadline"
+	case m.Creator != other.Creator:
+		return "creator"
+	case m.Category != other.Category:
+		return "category"
+	case !sameLabels(m.Tags, other.Tags):
+		return "tags"
+	case !sameLabels(m.Skills, other.Skills):
+		return "skills"
+	}
+	return ""
+}
+
+func sameLabels(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[string]bool, len(a))
+	for _, label := range a {
+		set[label] = true
+	}
+	for _, label := range b {
+		if !set[label] {
+			return false
+		}
+	}
+	return true
+}
+
+// MetadataURI is the metadata string to pass to the contract for a document
+// stored under cid.
+func MetadataURI(cid string) string {
+	return "ipfs://" + cid
+}
+
+// ParseMetadataURI returns the CID in an ipfs://<cid> metadata string. Older
+// bounties store inline JSON on-chain instead, for which ok is false.
+func ParseMetadataURI(uri string) (cid string, ok bool) {
+	rest, found := strings.CutPrefix(uri, "ipfs://")
+	if !found {
+		return "", false
+	}
+	c, err := storage.ParseCID(rest)
+	if err != nil {
+		return "", false
+	}
+	return c.String(), true
+}
+
+// BountyMetadataService pins and loads bounty metadata documents.
+type BountyMetadataService struct {
+	content storage.ContentStore
+}
+
+func NewBountyMetadataService(content storage.ContentStore) *BountyMetadataService {
+	return &BountyMetadataService{content: content}
+}
+
+// Pin validates and stores a document and returns its CID. Storing the same
+// document again returns the same CID.
+func (s *BountyMetadataService) Pin(ctx context.Context, metadata *BountyMetadata) (string, error) {
+	if err := metadata.Validate(); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return "", err
+	}
+	c, err := s.content.Put(ctx, bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return c.String(), nil
+}
+
+// Load reads and validates the document stored under cid. It also returns
+// the document's bytes, which are what the CID addresses.
+func (s *BountyMetadataService) Load(ctx context.Context, cid string) (*BountyMetadata, []byte, error) {
+	c, err := storage.ParseCID(cid)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.content.Get(ctx, c)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, ErrBountyMetadataNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer content.Close()
+
+	// Read to the end so the content is checked against its CID
+	data, err := io.ReadAll(io.LimitReader(content, maxBountyMetadataSize+1))
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(data) > maxBountyMetadataSize {
+		return nil, nil, fmt.Errorf("%w: document exceeds %d bytes", ErrInvalidBountyMetadata, maxBountyMetadataSize)
+	}
+	metadata, err := ParseBountyMetadata(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return metadata, data, nil
+}
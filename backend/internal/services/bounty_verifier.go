@@ -0,0 +1,232 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	errTxNotMined     = errors.New("transaction not mined yet")
+	errTxNotFinal     = errors.New("transaction does not have enough confirmations yet")
+	ErrTxReverted     = errors.New("transaction reverted")
+	ErrTxMismatch     = errors.New("transaction does not match bounty")
+	ErrTxNotConfirmed = errors.New("transaction was not mined in time")
+)
+
+// BountyVerifier confirms that bounties created through the API correspond to
+// a successful BountyCreated event on-chain. Bounties wait in
+// pending_confirmation until verified and are rejected if the transaction
+// reverted, never appeared, or does not match what the client claimed. A
+// transaction is only trusted once it is Confirmations blocks deep, so a
+// bounty is not opened on the strength of a block that is later reorged out.
+type BountyVerifier struct {
+	store         repository.Store
+	backend       chain.Backend
+	board         *chain.BountyBoard
+	confirmations uint64
+	jobs          chan uint
+	interval      time.Duration
+	timeout       time.Duration
+}
+
+func NewBountyVerifier(store repository.Store, backend chain.Backend, board *chain.BountyBoard, confirmations uint64) *BountyVerifier {
+	return &BountyVerifier{
+		store:         store,
+		backend:       backend,
+		board:         board,
+		confirmations: confirmations,
+		jobs:          make(chan uint, 256),
+		interval:      30 * time.Second,
+		timeout:       15 * time.Minute,
+	}
+}
+
+// Enqueue schedules a pending bounty for verification. If the queue is full
+// the bounty is picked up by the next periodic sweep instead.
+func (v *BountyVerifier) Enqueue(bountyID uint) {
+	select {
+	case v.jobs <- bountyID:
+	default:
+	}
+}
+
+// Run processes queued bounties and periodically sweeps every bounty still
+// pending confirmation, so nothing is lost across restarts.
+func (v *BountyVerifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.interval)
+	defer ticker.Stop()
+
+	v.sweep(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case id := <-v.jobs:
+			v.process(ctx, id)
+		case <-ticker.C:
+			v.sweep(ctx)
+		}
+	}
+}
+
+func (v *BountyVerifier) sweep(ctx context.Context) {
+	ids, err := v.store.Bounties().ListIDsByStatus(ctx, domain.StatusPendingConfirmation)
+	if err != nil {
+		log.Printf("Failed to load bounties pending confirmation: %v", err)
+		return
+	}
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		v.process(ctx, id)
+	}
+}
+
+func (v *BountyVerifier) process(ctx context.Context, id uint) {
+	bounty, err := v.store.Bounties().Get(ctx, id)
+	if err != nil {
+		log.Printf("Failed to load bounty %d for verification: %v", id, err)
+		return
+	}
+	if bounty.Status != domain.StatusPendingConfirmation {
+		return
+	}
+
+	err = v.Verify(ctx, bounty)
+	switch {
+	case err == nil:
+		now := time.Now()
+		v.transition(ctx, bounty.ID, domain.ActionConfirm, func(b *models.Bounty) {
+			b.VerifiedAt = &now
+		})
+
+	case errors.Is(err, errTxNotMined):
+		if time.Since(bounty.CreatedAt) > v.timeout {
+			v.reject(ctx, bounty, ErrTxNotConfirmed)
+		}
+
+	case errors.Is(err, errTxNotFinal):
+		// Mined but still shallow enough to be reorged; checked again later
+
+	case errors.Is(err, ErrTxReverted), errors.Is(err, ErrTxMismatch):
+		v.reject(ctx, bounty, err)
+
+	default:
+		// RPC failures are retried on the next sweep
+		log.Printf("Failed to verify bounty %d: %v", bounty.ID, err)
+	}
+}
+
+func (v *BountyVerifier) reject(ctx context.Context, bounty *models.Bounty, reason error) {
+	log.Printf("Rejecting bounty %d (tx %s): %v", bounty.ID, bounty.TxHash, reason)
+	message := reason.Error()
+	v.transition(ctx, bounty.ID, domain.ActionReject, func(b *models.Bounty) {
+		b.VerificationError = &message
+	})
+}
+
+// transition applies action and update only if the bounty is still pending,
+// so a concurrent indexer update is never overwritten.
+func (v *BountyVerifier) transition(ctx context.Context, id uint, action domain.BountyAction, update func(*models.Bounty)) {
+	_, err := WithLockedBounty(ctx, v.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if bounty.Status != domain.StatusPendingConfirmation {
+			return nil
+		}
+		update(bounty)
+		return tx.Bounties().Transition(ctx, bounty, action, ActorVerifier)
+	})
+	if err != nil {
+		log.Printf("Failed to %s bounty %d: %v", action, id, err)
+	}
+}
+
+// Verify checks the bounty's transaction receipt for a BountyCreated event
+// whose ID, creator, reward and metadata match the bounty. The receipt is
+// only inspected once its block has enough confirmations.
+func (v *BountyVerifier) Verify(ctx context.Context, bounty *models.Bounty) error {
+	if !isTxHash(bounty.TxHash) {
+		return fmt.Errorf("%w: malformed transaction hash", ErrTxMismatch)
+	}
+
+	receipt, err := v.backend.TransactionReceipt(ctx, common.HexToHash(bounty.TxHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return errTxNotMined
+	}
+	if err != nil {
+		return err
+	}
+	head, err := v.backend.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < receipt.BlockNumber.Uint64()+v.confirmations {
+		return errTxNotFinal
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return ErrTxReverted
+	}
+
+	reward := bounty.Reward.Shift(18)
+	if !reward.IsInteger() {
+		return fmt.Errorf("%w: reward has more than 18 decimals", ErrTxMismatch)
+	}
+
+	for _, l := range receipt.Logs {
+		event, err := v.board.ParseLog(*l)
+		if err != nil {
+			continue
+		}
+		created, ok := event.(*chain.BountyCreated)
+		if !ok || created.BountyID.Uint64() != uint64(bounty.BlockchainID) {
+			continue
+		}
+
+		if !strings.EqualFold(created.Creator.Hex(), bounty.CreatorID) {
+			return fmt.Errorf("%w: creator is %s", ErrTxMismatch, strings.ToLower(created.Creator.Hex()))
+		}
+		if created.Reward.Cmp(reward.BigInt()) != 0 {
+			return fmt.Errorf("%w: on-chain reward is %s wei", ErrTxMismatch, created.Reward)
+		}
+		return v.verifyMetadata(ctx, bounty, created.BountyID, receipt.BlockNumber)
+	}
+
+	return fmt.Errorf("%w: no BountyCreated event for bounty %d", ErrTxMismatch, bounty.BlockchainID)
+}
+
+// verifyMetadata checks that a bounty created with an ipfs:// metadata string
+// references the same document as the bounty's IPFSHash. Bounties created
+// with inline JSON metadata by older clients are accepted as they are.
+func (v *BountyVerifier) verifyMetadata(ctx context.Context, bounty *models.Bounty, id, blockNumber *big.Int) error {
+	if bounty.IPFSHash == "" {
+		return nil
+	}
+	onChain, err := v.board.GetBounty(ctx, v.backend, id, blockNumber)
+	if err != nil {
+		return err
+	}
+	cid, ok := ParseMetadataURI(onChain.Metadata)
+	if ok && cid != bounty.IPFSHash {
+		return fmt.Errorf("%w: on-chain metadata is %s", ErrTxMismatch, onChain.Metadata)
+	}
+	return nil
+}
+
+func isTxHash(s string) bool {
+	b, err := hexutil.Decode(s)
+	return err == nil && len(b) == common.HashLength
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/chain/chaintest"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+var verifierBoard = common.HexToAddress("0x00000000000000000000000000000000000b0a2d")
+
+func TestBountyVerifierWaitsForConfirmations(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	sim := chaintest.New(t, map[common.Address][]byte{
+		verifierBoard: chaintest.EmitterCode(chaintest.Selector("getBounty(uint256)"), nil),
+	})
+	board, err := chain.NewBountyBoard(verifierBoard)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reward := common.BigToHash(new(big.Int).Mul(big.NewInt(2), big.NewInt(1e18)))
+	tx := sim.Emit(t, verifierBoard, [3]common.Hash{
+		chaintest.EventID("BountyCreated(uint256,address,uint256)"), chaintest.IntTopic(1), chaintest.AddressTopic(sim.From),
+	}, reward.Bytes())
+	sim.Commit()
+
+	creator := strings.ToLower(sim.From.Hex())
+	if err := store.Users().Create(ctx, &models.User{ID: creator, Address: creator}); err != nil {
+		t.Fatal(err)
+	}
+	bounty := models.Bounty{
+		BlockchainID: 1,
+		Title:        "Pending bounty",
+		Reward:       decimal.NewFromInt(2),
+		CreatorID:    creator,
+		Status:       domain.StatusPendingConfirmation,
+		TxHash:       tx.Hash().Hex(),
+	}
+	if err := store.Bounties().Create(ctx, &bounty); err != nil {
+		t.Fatal(err)
+	}
+
+	status := func() domain.BountyStatus {
+		t.Helper()
+		got, err := store.Bounties().Get(ctx, bounty.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got.Status
+	}
+
+	v := NewBountyVerifier(store, sim.SimulatedBackend, board, 3)
+
+	// Mined in the head block, so it has no confirmations yet
+	v.process(ctx, bounty.ID)
+	if got := status(); got != domain.StatusPendingConfirmation {
+		t.Fatalf("status before confirmations = %s, want %s", got, domain.StatusPendingConfirmation)
+	}
+
+	sim.Mine(2)
+	v.process(ctx, bounty.ID)
+	if got := status(); got != domain.StatusPendingConfirmation {
+		t.Fatalf("status after 2 confirmations = %s, want %s", got, domain.StatusPendingConfirmation)
+	}
+
+	sim.Mine(1)
+	v.process(ctx, bounty.ID)
+	if got := status(); got != domain.StatusOpen {
+		t.Fatalf("status after 3 confirmations = %s, want %s", got, domain.StatusOpen)
+	}
+}
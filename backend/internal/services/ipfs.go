@@ -2,25 +2,22 @@ package services
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"net/http"
-	"os"
+
+	"github.com/bountyBoard/internal/storage"
 )
 
+// IPFSService uploads and reads content through the configured content
+// store.
 type IPFSService struct {
-	projectId  string
-	projectKey string
-	endpoint   string
+	store storage.ContentStore
 }
 
-func NewIPFSService() *IPFSService {
-	return &IPFSService{
-		projectId:  os.Getenv("IPFS_PROJECT_ID"),
-		projectKey: os.Getenv("IPFS_PROJECT_SECRET"),
-		endpoint:   "https://ipfs.infura.io:5001/api/v0",
-	}
+func NewIPFSService(store storage.ContentStore) *IPFSService {
+	return &IPFSService{store: store}
 }
 
 func (s *IPFSService) UploadJSON(data interface{}) (string, error) {
@@ -33,57 +30,23 @@ func (s *IPFSService) UploadJSON(data interface{}) (string, error) {
 }
 
 func (s *IPFSService) UploadFile(file io.Reader) (string, error) {
-	url := fmt.Sprintf("%s/add", s.endpoint)
-	
-	req, err := http.NewRequest("POST", url, file)
-	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
-	}
-
-	req.SetBasicAuth(s.projectId, s.projectKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	c, err := s.store.Put(context.Background(), file)
 	if err != nil {
 		return "", fmt.Errorf("failed to upload to IPFS: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("IPFS upload failed with status: %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Hash string `json:"Hash"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode IPFS response: %w", err)
-	}
-
-	return result.Hash, nil
+	return c.String(), nil
 }
 
 func (s *IPFSService) GetFile(hash string) ([]byte, error) {
-	url := fmt.Sprintf("%s/cat?arg=%s", s.endpoint, hash)
-	
-	req, err := http.NewRequest("POST", url, nil)
+	c, err := storage.ParseCID(hash)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
-
-	req.SetBasicAuth(s.projectId, s.projectKey)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	content, err := s.store.Get(context.Background(), c)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get file from IPFS: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("IPFS get failed with status: %d", resp.StatusCode)
-	}
+	defer content.Close()
 
-	return io.ReadAll(resp.Body)
+	return io.ReadAll(content)
 }
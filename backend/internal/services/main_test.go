@@ -0,0 +1,14 @@
+package services
+
+import (
+	"os"
+	"testing"
+
+	"github.com/bountyBoard/internal/auth"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_SECRET", "test-secret-that-is-at-least-32-characters")
+	auth.InitTokens()
+	os.Exit(m.Run())
+}
@@ -0,0 +1,37 @@
+package services
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// markdown renders GitHub-flavoured markdown. Raw HTML in the source is
+// dropped rather than passed through.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// markdownPolicy is the allowlist applied to rendered markdown: the usual
+// user-content elements, links marked nofollow and opened in a new tab, and
+// the disabled checkboxes of task lists and the language of code blocks.
+var markdownPolicy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	return p
+}()
+
+// RenderMarkdown converts user-written markdown to HTML that is safe to embed
+// in a page.
+func RenderMarkdown(source string) (string, error) {
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(source), &buf); err != nil {
+		return "", err
+	}
+	return markdownPolicy.Sanitize(buf.String()), nil
+}
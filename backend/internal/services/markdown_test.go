@@ -0,0 +1,46 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderMarkdownSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		want    []string
+		notWant []string
+	}{
+		{"script tag", "hi <script>alert(1)</script>",
+			[]string{"hi"}, []string{"<script", "</script"}},
+		{"javascript link", "[click](javascript:alert(1))",
+			[]string{"click"}, []string{"javascript:", "href"}},
+		{"raw html", "<div onclick=\"steal()\"><img src=x onerror=\"steal()\"></div>\n\n<iframe src=\"https://evil.example\"></iframe>",
+			nil, []string{"<div", "<img", "onclick", "onerror", "<iframe"}},
+		{"inline html", "a <b onmouseover=\"steal()\">bold</b> word",
+			[]string{"a", "word"}, []string{"<b", "onmouseover"}},
+		{"links", "[docs](https://example.com/docs)",
+			[]string{`href="https://example.com/docs"`, `rel="nofollow noopener"`, `target="_blank"`}, nil},
+		{"markdown", "# Title\n\n**bold** `code`\n\n- [x] done",
+			[]string{"<h1>Title</h1>", "<strong>bold</strong>", "<code>code</code>", `type="checkbox"`}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderMarkdown(tt.source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("output %q does not contain %q", got, want)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("output %q contains %q", got, notWant)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+type NonceService struct {
+	nonces repository.NonceRepository
+}
+
+func NewNonceService(store repository.Store) *NonceService {
+	return &NonceService{
+		nonces: store.Nonces(),
+	}
+}
+
+// Issue creates a Sign-In with Ethereum nonce valid for ttl.
+func (s *NonceService) Issue(ctx context.Context, ttl time.Duration) (*models.AuthNonce, error) {
+	nonce, err := auth.GenerateNonce()
+	if err != nil {
+		return nil, err
+	}
+
+	record := models.AuthNonce{
+		Nonce:     nonce,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	if err := s.nonces.Create(ctx, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Consume marks nonce as used and reports whether it was still valid, so a
+// signed message cannot be replayed.
+func (s *NonceService) Consume(ctx context.Context, nonce string, now time.Time) (bool, error) {
+	return s.nonces.Consume(ctx, nonce, now)
+}
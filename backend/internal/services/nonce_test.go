@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/repository"
+)
+
+func TestNonceConsume(t *testing.T) {
+	ctx := context.Background()
+	nonces := NewNonceService(repository.NewMemoryStore())
+
+	record, err := nonces.Issue(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, err := nonces.Consume(ctx, record.Nonce, time.Now()); err != nil || !ok {
+		t.Fatalf("first Consume = %v, %v, want true", ok, err)
+	}
+	if ok, _ := nonces.Consume(ctx, record.Nonce, time.Now()); ok {
+		t.Fatal("nonce consumed twice")
+	}
+
+	expired, err := nonces.Issue(ctx, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := nonces.Consume(ctx, expired.Nonce, time.Now().Add(2*time.Minute)); ok {
+		t.Fatal("expired nonce consumed")
+	}
+	if ok, _ := nonces.Consume(ctx, "unknown", time.Now()); ok {
+		t.Fatal("unknown nonce consumed")
+	}
+}
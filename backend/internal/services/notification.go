@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+// Notification types
+const (
+	NotificationBountyExpired   = "bounty_expired"
+	NotificationBountyUnclaimed = "bounty_unclaimed"
+	NotificationCommentMention  = "comment_mention"
+)
+
+type NotificationService struct {
+	notifications repository.NotificationRepository
+}
+
+func NewNotificationService(store repository.Store) *NotificationService {
+	return &NotificationService{
+		notifications: store.Notifications(),
+	}
+}
+
+// Notify stores a notification for userID using tx, so it is only persisted
+// if the surrounding change commits.
+func Notify(ctx context.Context, tx repository.Store, userID, kind string, bountyID *uint, message string) error {
+	return tx.Notifications().Create(ctx, &models.Notification{
+		UserID:   userID,
+		Type:     kind,
+		BountyID: bountyID,
+		Message:  message,
+	})
+}
+
+// List returns a user's notifications, newest first.
+func (s *NotificationService) List(ctx context.Context, userID string, unreadOnly bool) ([]models.Notification, error) {
+	return s.notifications.List(ctx, userID, unreadOnly, 100)
+}
+
+// MarkRead marks one of the user's notifications as read.
+func (s *NotificationService) MarkRead(ctx context.Context, userID string, id uint) error {
+	return s.notifications.MarkRead(ctx, userID, id, time.Now())
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+func TestNotificationListAndMarkRead(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	notifications := NewNotificationService(store)
+
+	for _, n := range []models.Notification{
+		{UserID: "0xa1", Type: NotificationCommentMention, Message: "first"},
+		{UserID: "0xa1", Type: NotificationCommentMention, Message: "second"},
+		{UserID: "0xb0", Type: NotificationCommentMention, Message: "other user"},
+	} {
+		n := n
+		if err := store.Notifications().Create(ctx, &n); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := notifications.List(ctx, "0xa1", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Message != "second" {
+		t.Fatalf("List = %+v, want two notifications newest first", list)
+	}
+
+	// Marking another user's notification is ignored
+	other, _ := notifications.List(ctx, "0xb0", false)
+	if err := notifications.MarkRead(ctx, "0xa1", other[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if unread, _ := notifications.List(ctx, "0xb0", true); len(unread) != 1 {
+		t.Fatal("another user's notification was marked read")
+	}
+
+	if err := notifications.MarkRead(ctx, "0xa1", list[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	unread, err := notifications.List(ctx, "0xa1", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unread) != 1 || unread[0].Message != "first" {
+		t.Fatalf("unread = %+v, want only the first notification", unread)
+	}
+}
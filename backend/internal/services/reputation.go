@@ -1,102 +1,223 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"time"
 
-	"github.com/bountyBoard/internal/database"
 	"github.com/bountyBoard/internal/models"
-	"gorm.io/gorm"
+	"github.com/bountyBoard/internal/repository"
 )
 
+// ReputationService keeps reputations as a projection of the append-only
+// reputation ledger: every score change is recorded as an event first.
 type ReputationService struct {
-	db *gorm.DB
+	store       repository.Store
+	reputations repository.ReputationRepository
+	rules       ReputationRules
+	config      *ReputationConfig
 }
 
-func NewReputationService() *ReputationService {
+func NewReputationService(store repository.Store) *ReputationService {
 	return &ReputationService{
-		db: database.DB,
+		store:       store,
+		reputations: store.Reputations(),
+		rules:       reputationRules,
+		config:      reputationConfig,
 	}
 }
 
-func (s *ReputationService) GetUserReputation(userID string) (*models.Reputation, error) {
-	var reputation models.Reputation
-	result := s.db.Preload("Badges").Where("user_id = ?", userID).First(&reputation)
-	if result.Error != nil {
-		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+func (s *ReputationService) GetUserReputation(ctx context.Context, userID string) (*models.Reputation, error) {
+	reputation, err := s.reputations.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
 			// Create new reputation record if not found
-			reputation = models.Reputation{
+			reputation = &models.Reputation{
 				UserID: userID,
 				Score:  0,
 				Level:  1,
 			}
-			if err := s.db.Create(&reputation).Error; err != nil {
+			if err := s.reputations.Create(ctx, reputation); err != nil {
 				return nil, err
 			}
 		} else {
-			return nil, result.Error
+			return nil, err
 		}
 	}
-	return &reputation, nil
+	return reputation, nil
 }
 
-func (s *ReputationService) UpdateScore(userID string, points int) (*models.Reputation, error) {
-	reputation, err := s.GetUserReputation(userID)
-	if err != nil {
-		return nil, err
+// Record appends event to the ledger and applies it to the user's score in
+// one transaction. The reputation row is locked first, so concurrent events
+// for the same user are applied one after another.
+func (s *ReputationService) Record(ctx context.Context, event *models.ReputationEvent) (*models.Reputation, error) {
+	reputation, err := s.record(ctx, event)
+	if errors.Is(err, repository.ErrDuplicate) {
+		// Another transaction created the user's reputation first
+		reputation, err = s.record(ctx, event)
 	}
+	return reputation, err
+}
 
-	reputation.Score += points
-	reputation.CalculateLevel()
+func (s *ReputationService) record(ctx context.Context, event *models.ReputationEvent) (*models.Reputation, error) {
+	var reputation *models.Reputation
+	err := s.store.Transaction(ctx, func(tx repository.Store) error {
+		var err error
+		reputation, err = lockReputation(ctx, tx.Reputations(), event.UserID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Reputations().AppendEvent(ctx, event); err != nil {
+			return err
+		}
 
-	if err := s.db.Save(reputation).Error; err != nil {
+		reputation.Score += event.Delta
+		reputation.CalculateLevel(s.config.LevelThresholds)
+		return tx.Reputations().Save(ctx, reputation)
+	})
+	if err != nil {
 		return nil, err
 	}
-
 	return reputation, nil
 }
 
-func (s *ReputationService) AwardBadge(userID string, name, description, tokenURI, txHash string) error {
-	badge := models.Badge{
-		UserID:      userID,
-		Name:        name,
-		Description: description,
-		TokenURI:    tokenURI,
-		TxHash:      txHash,
+// lockReputation locks a user's reputation, creating it if it does not exist.
+func lockReputation(ctx context.Context, reputations repository.ReputationRepository, userID string) (*models.Reputation, error) {
+	reputation, err := reputations.GetForUpdate(ctx, userID)
+	if !errors.Is(err, repository.ErrNotFound) {
+		return reputation, err
 	}
+	reputation = &models.Reputation{
+		UserID: userID,
+		Score:  0,
+		Level:  1,
+	}
+	if err := reputations.Create(ctx, reputation); err != nil {
+		return nil, err
+	}
+	return reputation, nil
+}
 
-	return s.db.Create(&badge).Error
+// History returns a page of a user's ledger events.
+func (s *ReputationService) History(ctx context.Context, userID string, page repository.PageRequest) (*repository.Page[models.ReputationEvent], error) {
+	return s.reputations.ListEvents(ctx, userID, page)
 }
 
-// Milestone thresholds for badge awards
-var milestones = map[string]int{
-	"Novice Hunter":    100,
-	"Skilled Hunter":   500,
-	"Expert Hunter":    1000,
-	"Legendary Hunter": 5000,
+// RebuildResult summarises a Rebuild.
+type RebuildResult struct {
+	Users         int `json:"users"`
+	Changed       int `json:"changed"` // reputations whose score or level changed
+	BadgesAwarded int `json:"badges_awarded"`
 }
 
-func (s *ReputationService) CheckAndAwardMilestoneBadges(userID string, reputation *models.Reputation) error {
-	for badgeName, threshold := range milestones {
-		if reputation.Score >= threshold {
-			// Check if badge already exists
-			var badge models.Badge
-			result := s.db.Where("user_id = ? AND name = ?", userID, badgeName).First(&badge)
-			if result.Error != nil {
-				if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-					// Award new badge
-					err := s.AwardBadge(userID, badgeName,
-						"Awarded for reaching "+string(threshold)+" reputation points",
-						"ipfs://...", // TODO: Generate badge metadata and upload to IPFS
-						"",           // TODO: Mint NFT badge and get transaction hash
-					)
-					if err != nil {
-						return err
-					}
-				} else {
-					return result.Error
+// Rebuild recomputes every user's score and level from the ledger and awards
+// any badges they now qualify for, e.g. after the level curve or badge
+// definitions change. Badges are never revoked. Each user is rebuilt in its
+// own transaction under the same lock Record takes, so it is safe to run
+// while events are being recorded.
+func (s *ReputationService) Rebuild(ctx context.Context) (*RebuildResult, error) {
+	userIDs, err := s.reputations.ListUserIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RebuildResult{Users: len(userIDs)}
+	for _, userID := range userIDs {
+		updated, awarded := false, 0
+		err := s.store.Transaction(ctx, func(tx repository.Store) error {
+			reputation, err := lockReputation(ctx, tx.Reputations(), userID)
+			if err != nil {
+				return err
+			}
+			total, err := tx.Reputations().SumEvents(ctx, userID)
+			if err != nil {
+				return err
+			}
+
+			score, level := reputation.Score, reputation.Level
+			reputation.Score = total
+			reputation.CalculateLevel(s.config.LevelThresholds)
+			if reputation.Score != score || reputation.Level != level {
+				updated = true
+				if err := tx.Reputations().Save(ctx, reputation); err != nil {
+					return err
 				}
 			}
+
+			awarded, err = s.withStore(tx).CheckAndAwardMilestoneBadges(ctx, userID, reputation)
+			return err
+		})
+		if err != nil {
+			return result, fmt.Errorf("rebuild reputation of %s: %w", userID, err)
+		}
+		if updated {
+			result.Changed++
+		}
+		result.BadgesAwarded += awarded
+	}
+	return result, nil
+}
+
+// withStore returns a copy of s that works on store, typically a transaction.
+func (s *ReputationService) withStore(store repository.Store) *ReputationService {
+	c := *s
+	c.store, c.reputations = store, store.Reputations()
+	return &c
+}
+
+// AwardBadge gives a user a badge and queues it to be minted as a Reputation
+// NFT. The token URI and transaction hash are filled in once the mint
+// worker confirms the mint.
+func (s *ReputationService) AwardBadge(ctx context.Context, userID string, name, description string) error {
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		badge := models.Badge{
+			UserID:      userID,
+			Name:        name,
+			Description: description,
+		}
+		if err := tx.Reputations().CreateBadge(ctx, &badge); err != nil {
+			return err
+		}
+		return tx.Reputations().CreateMintJob(ctx, &models.BadgeMintJob{
+			BadgeID:       badge.ID,
+			Status:        models.MintPending,
+			NextAttemptAt: time.Now(),
+		})
+	})
+}
+
+// CheckAndAwardMilestoneBadges awards the configured badges whose criteria
+// the user meets and does not hold yet. It returns the number awarded.
+func (s *ReputationService) CheckAndAwardMilestoneBadges(ctx context.Context, userID string, reputation *models.Reputation) (int, error) {
+	completed := 0
+	if s.config.needsCompletedBounties() {
+		var err error
+		completed, err = s.reputations.CountEvents(ctx, userID, models.ReasonBountyCompleted, models.ReasonDisputeWon)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	awarded := 0
+	for _, badge := range s.config.Badges {
+		if reputation.Score < badge.Criteria.MinScore || completed < badge.Criteria.MinCompletedBounties {
+			continue
+		}
+
+		// Check if badge already exists
+		exists, err := s.reputations.HasBadge(ctx, userID, badge.Name)
+		if err != nil {
+			return awarded, err
+		}
+		if exists {
+			continue
+		}
+
+		if err := s.AwardBadge(ctx, userID, badge.Name, badge.Description); err != nil {
+			return awarded, err
 		}
+		awarded++
 	}
-	return nil
+	return awarded, nil
 }
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ipfs/go-cid"
+)
+
+//go:embed reputation_config.json
+var defaultReputationConfig []byte
+
+// ReputationConfig holds the level curve and the milestone badges.
+type ReputationConfig struct {
+	// LevelThresholds[i] is the score needed to reach level i+1. The first
+	// threshold must be 0 and thresholds must increase.
+	LevelThresholds []int             `json:"level_thresholds"`
+	Badges          []BadgeDefinition `json:"badges"`
+}
+
+// BadgeDefinition describes a milestone badge. A user earns it once every
+// criterion is met.
+type BadgeDefinition struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Criteria    BadgeCriteria `json:"criteria"`
+	ImageCID    string        `json:"image_cid,omitempty"` // IPFS CID of the badge artwork
+}
+
+// BadgeCriteria are the requirements for a badge. Zero values are ignored,
+// but at least one must be set.
+type BadgeCriteria struct {
+	MinScore             int `json:"min_score,omitempty"`
+	MinCompletedBounties int `json:"min_completed_bounties,omitempty"`
+}
+
+// ParseReputationConfig decodes and validates a configuration. Unknown fields
+// are rejected so typos do not silently disable a rule.
+func ParseReputationConfig(data []byte) (*ReputationConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	var config ReputationConfig
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("invalid reputation config: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid reputation config: %w", err)
+	}
+	return &config, nil
+}
+
+// ReputationConfigFromEnv loads the file named by REPUTATION_CONFIG, or the
+// built-in configuration if it is unset.
+func ReputationConfigFromEnv() (*ReputationConfig, error) {
+	path := os.Getenv("REPUTATION_CONFIG")
+	if path == "" {
+		return ParseReputationConfig(defaultReputationConfig)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reputation config: %w", err)
+	}
+	return ParseReputationConfig(data)
+}
+
+func (c *ReputationConfig) Validate() error {
+	if len(c.LevelThresholds) == 0 || c.LevelThresholds[0] != 0 {
+		return errors.New("level_thresholds must start at 0")
+	}
+	for i := 1; i < len(c.LevelThresholds); i++ {
+		if c.LevelThresholds[i] <= c.LevelThresholds[i-1] {
+			return fmt.Errorf("level_thresholds must increase, but level %d needs %d and level %d needs %d",
+				i, c.LevelThresholds[i-1], i+1, c.LevelThresholds[i])
+		}
+	}
+
+	names := make(map[string]bool, len(c.Badges))
+	for _, badge := range c.Badges {
+		if badge.Name == "" {
+			return errors.New("badge name is required")
+		}
+		if names[badge.Name] {
+			return fmt.Errorf("duplicate badge %q", badge.Name)
+		}
+		names[badge.Name] = true
+
+		if badge.Description == "" {
+			return fmt.Errorf("badge %q: description is required", badge.Name)
+		}
+		criteria := badge.Criteria
+		if criteria.MinScore < 0 || criteria.MinCompletedBounties < 0 {
+			return fmt.Errorf("badge %q: criteria must not be negative", badge.Name)
+		}
+		if criteria == (BadgeCriteria{}) {
+			return fmt.Errorf("badge %q: at least one criterion is required", badge.Name)
+		}
+		if badge.ImageCID != "" {
+			if _, err := cid.Decode(badge.ImageCID); err != nil {
+				return fmt.Errorf("badge %q: invalid image_cid: %w", badge.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Badge returns the definition of the named badge.
+func (c *ReputationConfig) Badge(name string) (BadgeDefinition, bool) {
+	for _, badge := range c.Badges {
+		if badge.Name == name {
+			return badge, true
+		}
+	}
+	return BadgeDefinition{}, false
+}
+
+// needsCompletedBounties reports whether any badge depends on the number of
+// completed bounties, which costs a query to find out.
+func (c *ReputationConfig) needsCompletedBounties() bool {
+	for _, badge := range c.Badges {
+		if badge.Criteria.MinCompletedBounties > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// reputationConfig is the configuration used by new ReputationServices.
+var reputationConfig = mustParseReputationConfig(defaultReputationConfig)
+
+func mustParseReputationConfig(data []byte) *ReputationConfig {
+	config, err := ParseReputationConfig(data)
+	if err != nil {
+		panic(err)
+	}
+	return config
+}
+
+// SetReputationConfig replaces the configuration used by ReputationServices
+// created afterwards. It is meant to be called once at startup.
+func SetReputationConfig(config *ReputationConfig) {
+	reputationConfig = config
+}
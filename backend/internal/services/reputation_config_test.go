@@ -0,0 +1,169 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReputationConfigValidate(t *testing.T) {
+	badge := func(name string, criteria BadgeCriteria) BadgeDefinition {
+		return BadgeDefinition{Name: name, Description: "A badge", Criteria: criteria}
+	}
+	tests := []struct {
+		name    string
+		config  ReputationConfig
+		wantErr string
+	}{
+		{
+			name:   "valid",
+			config: ReputationConfig{LevelThresholds: []int{0, 10, 20}, Badges: []BadgeDefinition{badge("Ten", BadgeCriteria{MinScore: 10})}},
+		},
+		{
+			name: "valid image",
+			config: ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{{
+				Name: "Art", Description: "A badge", Criteria: BadgeCriteria{MinCompletedBounties: 1},
+				ImageCID: "bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi",
+			}}},
+		},
+		{name: "no thresholds", config: ReputationConfig{}, wantErr: "must start at 0"},
+		{name: "first threshold not zero", config: ReputationConfig{LevelThresholds: []int{10, 20}}, wantErr: "must start at 0"},
+		{name: "negative first threshold", config: ReputationConfig{LevelThresholds: []int{-1, 20}}, wantErr: "must start at 0"},
+		{name: "repeated threshold", config: ReputationConfig{LevelThresholds: []int{0, 10, 10}}, wantErr: "must increase"},
+		{name: "decreasing threshold", config: ReputationConfig{LevelThresholds: []int{0, 20, 10, 30}}, wantErr: "level 2 needs 20 and level 3 needs 10"},
+		{
+			name:    "badge without a name",
+			config:  ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{badge("", BadgeCriteria{MinScore: 1})}},
+			wantErr: "name is required",
+		},
+		{
+			name:    "duplicate badge",
+			config:  ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{badge("Ten", BadgeCriteria{MinScore: 10}), badge("Ten", BadgeCriteria{MinScore: 20})}},
+			wantErr: `duplicate badge "Ten"`,
+		},
+		{
+			name:    "badge without a description",
+			config:  ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{{Name: "Ten", Criteria: BadgeCriteria{MinScore: 10}}}},
+			wantErr: "description is required",
+		},
+		{
+			name:    "negative criterion",
+			config:  ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{badge("Ten", BadgeCriteria{MinScore: 10, MinCompletedBounties: -1})}},
+			wantErr: "must not be negative",
+		},
+		{
+			name:    "no criteria",
+			config:  ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{badge("Free", BadgeCriteria{})}},
+			wantErr: "at least one criterion",
+		},
+		{
+			name: "malformed image",
+			config: ReputationConfig{LevelThresholds: []int{0}, Badges: []BadgeDefinition{{
+				Name: "Art", Description: "A badge", Criteria: BadgeCriteria{MinScore: 1}, ImageCID: "not-a-cid",
+			}}},
+			wantErr: "invalid image_cid",
+		},
+	}
+	for _, tt := range tests {
+		err := tt.config.Validate()
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: err = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestParseReputationConfig(t *testing.T) {
+	valid := `{"level_thresholds": [0, 100], "badges": [{"name": "Hundred", "description": "100 points", "criteria": {"min_score": 100}}]}`
+	config, err := ParseReputationConfig([]byte(valid))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ReputationConfig{
+		LevelThresholds: []int{0, 100},
+		Badges:          []BadgeDefinition{{Name: "Hundred", Description: "100 points", Criteria: BadgeCriteria{MinScore: 100}}},
+	}
+	if !reflect.DeepEqual(config, want) {
+		t.Errorf("config = %+v, want %+v", config, want)
+	}
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{"unknown field", `{"level_thresholds": [0], "reasons": {"bounty_completed": 10}}`, `unknown field "reasons"`},
+		{"unknown badge field", `{"level_thresholds": [0], "badges": [{"name": "A", "description": "B", "points": 1, "criteria": {"min_score": 1}}]}`, `unknown field "points"`},
+		{"misspelled criterion", `{"level_thresholds": [0], "badges": [{"name": "A", "description": "B", "criteria": {"min_scor": 1}}]}`, `unknown field "min_scor"`},
+		{"malformed json", `{"level_thresholds": [0,`, "invalid reputation config"},
+		{"threshold not a number", `{"level_thresholds": ["0"]}`, "cannot unmarshal"},
+		{"invalid config", `{"level_thresholds": [0, 100, 50]}`, "must increase"},
+		{"empty", ``, "invalid reputation config"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseReputationConfig([]byte(tt.data)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want one containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDefaultReputationConfig(t *testing.T) {
+	config, err := ParseReputationConfig(defaultReputationConfig)
+	if err != nil {
+		t.Fatalf("embedded default does not load: %v", err)
+	}
+	if len(config.LevelThresholds) < 2 || config.LevelThresholds[0] != 0 {
+		t.Errorf("level_thresholds = %v, want a curve starting at 0", config.LevelThresholds)
+	}
+	// AwardCompletion relies on the default first-completion badge
+	if badge, ok := config.Badge("First Bounty"); !ok || badge.Criteria.MinCompletedBounties != 1 {
+		t.Errorf("First Bounty = %+v, %v, want a badge for one completed bounty", badge, ok)
+	}
+	if !config.needsCompletedBounties() {
+		t.Error("default config does not count completed bounties")
+	}
+
+	t.Setenv("REPUTATION_CONFIG", "")
+	fromEnv, err := ReputationConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromEnv, config) {
+		t.Errorf("ReputationConfigFromEnv without a file = %+v, want the embedded default", fromEnv)
+	}
+}
+
+func TestReputationConfigFromEnvFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Setenv("REPUTATION_CONFIG", write("valid.json", `{"level_thresholds": [0, 5]}`))
+	config, err := ReputationConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(config.LevelThresholds, []int{0, 5}) || len(config.Badges) != 0 {
+		t.Errorf("config = %+v, want the file's thresholds and no badges", config)
+	}
+
+	for name, path := range map[string]string{
+		"missing file":  filepath.Join(dir, "missing.json"),
+		"unknown field": write("unknown.json", `{"level_thresholds": [0], "levels": 3}`),
+		"invalid":       write("invalid.json", `{"level_thresholds": [5]}`),
+	} {
+		t.Setenv("REPUTATION_CONFIG", path)
+		if _, err := ReputationConfigFromEnv(); err == nil {
+			t.Errorf("%s: loaded without an error", name)
+		}
+	}
+}
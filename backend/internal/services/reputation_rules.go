@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/shopspring/decimal"
+)
+
+// ReputationRules decides how many points bounty outcomes are worth.
+type ReputationRules struct {
+	CompletionBase     int             // points a hunter earns for any completed bounty
+	PointsPerReward    decimal.Decimal // extra hunter points per whole unit of reward
+	MaxRewardPoints    int             // cap on the reward-scaled points
+	CreatorCompletion  int             // points a creator earns when their bounty completes
+	DisputeLossPenalty int             // points deducted from the losing side of a dispute
+}
+
+// DefaultReputationRules returns the rules used when no overrides are set.
+func DefaultReputationRules() ReputationRules {
+	return ReputationRules{
+		CompletionBase:     10,
+		PointsPerReward:    decimal.NewFromInt(10),
+		MaxRewardPoints:    500,
+		CreatorCompletion:  2,
+		DisputeLossPenalty: 25,
+	}
+}
+
+// ReputationRulesFromEnv reads REPUTATION_COMPLETION_BASE,
+// REPUTATION_POINTS_PER_REWARD, REPUTATION_MAX_REWARD_POINTS,
+// REPUTATION_CREATOR_COMPLETION and REPUTATION_DISPUTE_LOSS_PENALTY, falling
+// back to the defaults.
+func ReputationRulesFromEnv() (ReputationRules, error) {
+	rules := DefaultReputationRules()
+
+	ints := []struct {
+		name string
+		dest *int
+	}{
+		{"REPUTATION_COMPLETION_BASE", &rules.CompletionBase},
+		{"REPUTATION_MAX_REWARD_POINTS", &rules.MaxRewardPoints},
+		{"REPUTATION_CREATOR_COMPLETION", &rules.CreatorCompletion},
+		{"REPUTATION_DISPUTE_LOSS_PENALTY", &rules.DisputeLossPenalty},
+	}
+	for _, setting := range ints {
+		v := os.Getenv(setting.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return rules, fmt.Errorf("invalid %s: %q", setting.name, v)
+		}
+		*setting.dest = n
+	}
+
+	if v := os.Getenv("REPUTATION_POINTS_PER_REWARD"); v != "" {
+		perReward, err := decimal.NewFromString(v)
+		if err != nil || perReward.IsNegative() {
+			return rules, fmt.Errorf("invalid REPUTATION_POINTS_PER_REWARD: %q", v)
+		}
+		rules.PointsPerReward = perReward
+	}
+	return rules, nil
+}
+
+// reputationRules are the rules used by new ReputationServices.
+var reputationRules = DefaultReputationRules()
+
+// SetReputationRules replaces the rules used by ReputationServices created
+// afterwards. It is meant to be called once at startup.
+func SetReputationRules(rules ReputationRules) {
+	reputationRules = rules
+}
+
+// completionPoints returns the points a hunter earns for completing bounty.
+func (r ReputationRules) completionPoints(bounty *models.Bounty) int {
+	scaled := bounty.Reward.Mul(r.PointsPerReward).IntPart()
+	if scaled > int64(r.MaxRewardPoints) {
+		scaled = int64(r.MaxRewardPoints)
+	}
+	if scaled < 0 {
+		scaled = 0
+	}
+	return r.CompletionBase + int(scaled)
+}
+
+// AwardCompletion credits the hunter and creator of a bounty that was
+// completed without a dispute. It must run in the same transaction as the
+// transition to completed, which happens at most once per bounty.
+func (s *ReputationService) AwardCompletion(ctx context.Context, bounty *models.Bounty, actorID string) error {
+	if bounty.HunterID == nil {
+		return nil
+	}
+	if err := s.award(ctx, *bounty.HunterID, s.rules.completionPoints(bounty), models.ReasonBountyCompleted, bounty.ID, actorID); err != nil {
+		return err
+	}
+	return s.award(ctx, bounty.CreatorID, s.rules.CreatorCompletion, models.ReasonBountyFunded, bounty.ID, actorID)
+}
+
+// AwardDisputeResolution settles reputation for a resolved dispute. If the
+// hunter wins they earn the completion points; either way the loser is
+// penalised.
+func (s *ReputationService) AwardDisputeResolution(ctx context.Context, bounty *models.Bounty, actorID string) error {
+	if bounty.HunterID == nil || bounty.DisputeWinner == nil {
+		return nil
+	}
+
+	hunter, winner := *bounty.HunterID, *bounty.DisputeWinner
+	loser := hunter
+	if winner == hunter {
+		loser = bounty.CreatorID
+		if err := s.award(ctx, hunter, s.rules.completionPoints(bounty), models.ReasonDisputeWon, bounty.ID, actorID); err != nil {
+			return err
+		}
+	}
+	return s.award(ctx, loser, -s.rules.DisputeLossPenalty, models.ReasonDisputeLost, bounty.ID, actorID)
+}
+
+// award records a ledger event and awards any milestone badges it unlocks.
+// Zero-point awards are skipped.
+func (s *ReputationService) award(ctx context.Context, userID string, delta int, reason models.ReputationReason, bountyID uint, actorID string) error {
+	if delta == 0 {
+		return nil
+	}
+	reputation, err := s.Record(ctx, &models.ReputationEvent{
+		UserID:   userID,
+		Delta:    delta,
+		Reason:   reason,
+		BountyID: &bountyID,
+		ActorID:  actorID,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.CheckAndAwardMilestoneBadges(ctx, userID, reputation)
+	return err
+}
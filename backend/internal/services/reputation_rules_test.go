@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	awardCreator = "0x00000000000000000000000000000000000000c0"
+	awardHunter  = "0x00000000000000000000000000000000000000b0"
+)
+
+// awardedBounty returns a bounty with a reward of 2, which the default rules
+// value at 10 + 2*10 = 30 hunter points.
+func awardedBounty(hunter *string) *models.Bounty {
+	return &models.Bounty{
+		ID:        7,
+		CreatorID: awardCreator,
+		HunterID:  hunter,
+		Reward:    decimal.NewFromInt(2),
+		Status:    domain.StatusCompleted,
+	}
+}
+
+// ledger returns the events recorded for userID by reason.
+func ledger(t *testing.T, store repository.Store, userID string) map[models.ReputationReason]int {
+	t.Helper()
+	page, err := store.Reputations().ListEvents(context.Background(), userID, repository.PageRequest{Limit: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deltas := map[models.ReputationReason]int{}
+	for _, event := range page.Items {
+		if event.BountyID == nil || *event.BountyID != 7 || event.ActorID != "tester" {
+			t.Errorf("event %+v does not reference the bounty and actor", event)
+		}
+		deltas[event.Reason] += event.Delta
+	}
+	return deltas
+}
+
+func score(t *testing.T, store repository.Store, userID string) int {
+	t.Helper()
+	reputation, err := store.Reputations().GetByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reputation.Score
+}
+
+func newRuleTestService(store repository.Store) *ReputationService {
+	s := NewReputationService(store)
+	s.rules = DefaultReputationRules()
+	return s
+}
+
+func TestAwardCompletion(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	hunter := awardHunter
+
+	if err := newRuleTestService(store).AwardCompletion(ctx, awardedBounty(&hunter), "tester"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ledger(t, store, awardHunter); len(got) != 1 || got[models.ReasonBountyCompleted] != 30 {
+		t.Errorf("hunter ledger = %v, want 30 for the completion", got)
+	}
+	if got := ledger(t, store, awardCreator); len(got) != 1 || got[models.ReasonBountyFunded] != 2 {
+		t.Errorf("creator ledger = %v, want 2 for funding", got)
+	}
+	if got := score(t, store, awardHunter); got != 30 {
+		t.Errorf("hunter score = %d, want 30", got)
+	}
+
+	// The first completion unlocks the default "First Bounty" badge
+	has, err := store.Reputations().HasBadge(ctx, awardHunter, "First Bounty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !has {
+		t.Error("hunter did not get the First Bounty badge")
+	}
+}
+
+func TestAwardCompletionCapsRewardPoints(t *testing.T) {
+	store := repository.NewMemoryStore()
+	hunter := awardHunter
+	bounty := awardedBounty(&hunter)
+	bounty.Reward = decimal.NewFromInt(1000)
+
+	if err := newRuleTestService(store).AwardCompletion(context.Background(), bounty, "tester"); err != nil {
+		t.Fatal(err)
+	}
+	rules := DefaultReputationRules()
+	if got, want := score(t, store, awardHunter), rules.CompletionBase+rules.MaxRewardPoints; got != want {
+		t.Errorf("hunter score = %d, want %d", got, want)
+	}
+}
+
+func TestAwardCompletionWithoutHunter(t *testing.T) {
+	store := repository.NewMemoryStore()
+	if err := newRuleTestService(store).AwardCompletion(context.Background(), awardedBounty(nil), "tester"); err != nil {
+		t.Fatal(err)
+	}
+	users, err := store.Reputations().ListUserIDs(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 0 {
+		t.Errorf("reputations of %v were touched for a bounty without a hunter", users)
+	}
+}
+
+func TestAwardDisputeResolution(t *testing.T) {
+	hunter, creator := awardHunter, awardCreator
+	tests := []struct {
+		name    string
+		winner  string
+		hunter  map[models.ReputationReason]int
+		creator map[models.ReputationReason]int
+	}{
+		{
+			name:    "hunter wins",
+			winner:  hunter,
+			hunter:  map[models.ReputationReason]int{models.ReasonDisputeWon: 30},
+			creator: map[models.ReputationReason]int{models.ReasonDisputeLost: -25},
+		},
+		{
+			name:    "creator wins",
+			winner:  creator,
+			hunter:  map[models.ReputationReason]int{models.ReasonDisputeLost: -25},
+			creator: map[models.ReputationReason]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryStore()
+			bounty := awardedBounty(&hunter)
+			bounty.DisputeWinner = &tt.winner
+
+			if err := newRuleTestService(store).AwardDisputeResolution(context.Background(), bounty, "tester"); err != nil {
+				t.Fatal(err)
+			}
+			if got := ledger(t, store, hunter); !equalDeltas(got, tt.hunter) {
+				t.Errorf("hunter ledger = %v, want %v", got, tt.hunter)
+			}
+			if got := ledger(t, store, creator); !equalDeltas(got, tt.creator) {
+				t.Errorf("creator ledger = %v, want %v", got, tt.creator)
+			}
+		})
+	}
+}
+
+func TestAwardDisputeResolutionWithoutWinner(t *testing.T) {
+	store := repository.NewMemoryStore()
+	hunter := awardHunter
+	if err := newRuleTestService(store).AwardDisputeResolution(context.Background(), awardedBounty(&hunter), "tester"); err != nil {
+		t.Fatal(err)
+	}
+	if got := ledger(t, store, awardHunter); len(got) != 0 {
+		t.Errorf("hunter ledger = %v for an unresolved dispute", got)
+	}
+}
+
+func equalDeltas(a, b map[models.ReputationReason]int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for reason, delta := range a {
+		if b[reason] != delta {
+			return false
+		}
+	}
+	return true
+}
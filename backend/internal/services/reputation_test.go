@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+const (
+	ledgerUser  = "0x00000000000000000000000000000000000000a1"
+	ledgerOther = "0x00000000000000000000000000000000000000a2"
+)
+
+// testReputationConfig has levels at 0, 100 and 250 points and a badge at
+// 100 points.
+func testReputationConfig() *ReputationConfig {
+	return &ReputationConfig{
+		LevelThresholds: []int{0, 100, 250},
+		Badges: []BadgeDefinition{
+			{Name: "Centurion", Description: "Reached 100 points", Criteria: BadgeCriteria{MinScore: 100}},
+		},
+	}
+}
+
+func newLedgerTestService(store repository.Store) *ReputationService {
+	s := NewReputationService(store)
+	s.config = testReputationConfig()
+	return s
+}
+
+func reputationOf(t *testing.T, store repository.Store, userID string) *models.Reputation {
+	t.Helper()
+	reputation, err := store.Reputations().GetByUserID(context.Background(), userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reputation
+}
+
+func TestRecordUpdatesProjection(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	s := newLedgerTestService(store)
+
+	steps := []struct {
+		delta  int
+		reason models.ReputationReason
+		score  int
+		level  int
+	}{
+		{60, models.ReasonBountyCompleted, 60, 1},
+		{50, models.ReasonBountyCompleted, 110, 2},
+		{150, models.ReasonDisputeWon, 260, 3},
+		{-25, models.ReasonDisputeLost, 235, 2},
+	}
+	for i, step := range steps {
+		reputation, err := s.Record(ctx, &models.ReputationEvent{UserID: ledgerUser, Delta: step.delta, Reason: step.reason})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reputation.Score != step.score || reputation.Level != step.level {
+			t.Fatalf("after event %d: score %d level %d, want %d and %d", i+1, reputation.Score, reputation.Level, step.score, step.level)
+		}
+		if stored := reputationOf(t, store, ledgerUser); stored.Score != step.score || stored.Level != step.level {
+			t.Fatalf("after event %d: stored score %d level %d, want %d and %d", i+1, stored.Score, stored.Level, step.score, step.level)
+		}
+	}
+
+	events, err := s.History(ctx, ledgerUser, repository.PageRequest{Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if events.Total != int64(len(steps)) {
+		t.Fatalf("ledger has %d events, want %d", events.Total, len(steps))
+	}
+	if sum, err := store.Reputations().SumEvents(ctx, ledgerUser); err != nil || sum != 235 {
+		t.Fatalf("SumEvents = %d, %v, want 235", sum, err)
+	}
+}
+
+func TestRebuildReproducesProjectionFromLedger(t *testing.T) {
+	ctx := context.Background()
+	events := []models.ReputationEvent{
+		{UserID: ledgerUser, Delta: 200, Reason: models.ReasonOpeningBalance},
+		{UserID: ledgerUser, Delta: 80, Reason: models.ReasonBountyCompleted},
+		{UserID: ledgerUser, Delta: -25, Reason: models.ReasonDisputeLost},
+		{UserID: ledgerOther, Delta: 40, Reason: models.ReasonBountyFunded},
+	}
+
+	// The projection kept by Record
+	recorded := repository.NewMemoryStore()
+	for _, event := range events {
+		if _, err := newLedgerTestService(recorded).Record(ctx, &event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The same events on their own, next to a projection that disagrees
+	rebuilt := repository.NewMemoryStore()
+	for _, event := range events {
+		if err := rebuilt.Reputations().AppendEvent(ctx, &event); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := rebuilt.Reputations().Create(ctx, &models.Reputation{UserID: ledgerOther, Score: 999, Level: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newLedgerTestService(rebuilt)
+	result, err := s.Rebuild(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != 2 || result.Changed != 2 || result.BadgesAwarded != 1 {
+		t.Errorf("Rebuild = %+v, want 2 users changed and 1 badge", result)
+	}
+	for _, userID := range []string{ledgerUser, ledgerOther} {
+		want, got := reputationOf(t, recorded, userID), reputationOf(t, rebuilt, userID)
+		if got.Score != want.Score || got.Level != want.Level {
+			t.Errorf("%s: rebuilt score %d level %d, want %d and %d", userID, got.Score, got.Level, want.Score, want.Level)
+		}
+	}
+
+	// A second rebuild has nothing to do
+	result, err = s.Rebuild(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Changed != 0 || result.BadgesAwarded != 0 {
+		t.Errorf("second Rebuild = %+v, want no changes", result)
+	}
+}
+
+func TestMilestoneBadgesAreAwardedOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	s := newLedgerTestService(store)
+
+	reputation, err := s.Record(ctx, &models.ReputationEvent{UserID: ledgerUser, Delta: 150, Reason: models.ReasonBountyCompleted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range []int{1, 0} {
+		awarded, err := s.CheckAndAwardMilestoneBadges(ctx, ledgerUser, reputation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if awarded != want {
+			t.Errorf("check %d awarded %d badges, want %d", i+1, awarded, want)
+		}
+	}
+
+	// Neither more points nor a rebuild award it again
+	if _, err := s.Record(ctx, &models.ReputationEvent{UserID: ledgerUser, Delta: 50, Reason: models.ReasonBountyCompleted}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := s.Rebuild(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.BadgesAwarded != 0 {
+		t.Errorf("Rebuild awarded %d badges, want 0", result.BadgesAwarded)
+	}
+
+	badges := reputationOf(t, store, ledgerUser).Badges
+	if len(badges) != 1 || badges[0].Name != "Centurion" {
+		t.Fatalf("badges = %+v, want one Centurion badge", badges)
+	}
+	jobs, err := store.Reputations().DueMintJobs(ctx, badges[0].CreatedAt.AddDate(1, 0, 0), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 {
+		t.Errorf("%d mint jobs queued, want 1", len(jobs))
+	}
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+var (
+	ErrInvalidRole    = errors.New("invalid role")
+	ErrLastAdmin      = errors.New("cannot revoke the last admin")
+	ErrRoleNotGranted = errors.New("role not granted")
+)
+
+type RoleService struct {
+	store repository.Store
+}
+
+func NewRoleService(store repository.Store) *RoleService {
+	return &RoleService{
+		store: store,
+	}
+}
+
+// ListRoles returns the roles held by a user, always including RoleUser.
+func (s *RoleService) ListRoles(ctx context.Context, userID string) ([]models.Role, error) {
+	roles, err := s.store.Roles().List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]models.Role{models.RoleUser}, roles...), nil
+}
+
+// HasAnyRole reports whether the user holds at least one of the given roles.
+func (s *RoleService) HasAnyRole(ctx context.Context, userID string, roles ...models.Role) (bool, error) {
+	for _, role := range roles {
+		if role == models.RoleUser {
+			return true, nil
+		}
+	}
+
+	return s.store.Roles().HasAny(ctx, userID, roles)
+}
+
+// Grant gives a role to a user. Granting a role the user already holds is a no-op.
+func (s *RoleService) Grant(ctx context.Context, userID string, role models.Role, grantedBy string) error {
+	if !role.Valid() || role == models.RoleUser {
+		return ErrInvalidRole
+	}
+
+	return s.store.Roles().Grant(ctx, &models.UserRole{
+		UserID:    userID,
+		Role:      role,
+		GrantedBy: grantedBy,
+	})
+}
+
+// Revoke removes a role from a user. The last remaining admin cannot be revoked.
+func (s *RoleService) Revoke(ctx context.Context, userID string, role models.Role) error {
+	if !role.Valid() || role == models.RoleUser {
+		return ErrInvalidRole
+	}
+
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		if role == models.RoleAdmin {
+			admins, err := tx.Roles().HoldersForUpdate(ctx, models.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if len(admins) == 1 && admins[0].UserID == userID {
+				return ErrLastAdmin
+			}
+		}
+
+		if err := tx.Roles().Revoke(ctx, userID, role); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrRoleNotGranted
+			}
+			return err
+		}
+		return nil
+	})
+}
+
+// BootstrapFromEnv grants roles to the wallet addresses listed in
+// ADMIN_ADDRESSES and ARBITER_ADDRESSES, creating the users if needed.
+func (s *RoleService) BootstrapFromEnv(ctx context.Context) {
+	bootstrap := map[models.Role]string{
+		models.RoleAdmin:   os.Getenv("ADMIN_ADDRESSES"),
+		models.RoleArbiter: os.Getenv("ARBITER_ADDRESSES"),
+	}
+
+	users := NewUserService(s.store)
+	for role, addresses := range bootstrap {
+		for _, address := range strings.Split(addresses, ",") {
+			address = strings.ToLower(strings.TrimSpace(address))
+			if address == "" {
+				continue
+			}
+
+			user, err := users.FindOrCreateByAddress(ctx, address)
+			if err != nil {
+				log.Fatalf("Failed to bootstrap %s %s: %v", role, address, err)
+			}
+			if err := s.Grant(ctx, user.ID, role, "bootstrap"); err != nil {
+				log.Fatalf("Failed to bootstrap %s %s: %v", role, address, err)
+			}
+		}
+	}
+}
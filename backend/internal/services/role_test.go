@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+func TestRoleGrantAndRevoke(t *testing.T) {
+	ctx := context.Background()
+	roles := NewRoleService(repository.NewMemoryStore())
+	const alice, bob = "0xa1", "0xb0"
+
+	ok, err := roles.HasAnyRole(ctx, alice, models.RoleAdmin)
+	if err != nil || ok {
+		t.Fatalf("HasAnyRole before grant = %v, %v", ok, err)
+	}
+	// Every user holds RoleUser implicitly
+	if ok, _ := roles.HasAnyRole(ctx, alice, models.RoleUser); !ok {
+		t.Fatal("RoleUser not implied")
+	}
+
+	if err := roles.Grant(ctx, alice, models.RoleAdmin, "bootstrap"); err != nil {
+		t.Fatal(err)
+	}
+	// Granting twice is a no-op
+	if err := roles.Grant(ctx, alice, models.RoleAdmin, "bootstrap"); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.Grant(ctx, alice, models.RoleArbiter, bob); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := roles.ListRoles(ctx, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []models.Role{models.RoleUser, models.RoleAdmin, models.RoleArbiter}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListRoles = %v, want %v", got, want)
+	}
+
+	if err := roles.Revoke(ctx, alice, models.RoleAdmin); !errors.Is(err, ErrLastAdmin) {
+		t.Fatalf("revoking last admin: err = %v, want ErrLastAdmin", err)
+	}
+	if err := roles.Grant(ctx, bob, models.RoleAdmin, alice); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.Revoke(ctx, alice, models.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+	if err := roles.Revoke(ctx, alice, models.RoleAdmin); !errors.Is(err, ErrRoleNotGranted) {
+		t.Fatalf("revoking twice: err = %v, want ErrRoleNotGranted", err)
+	}
+}
+
+func TestGrantRejectsInvalidRoles(t *testing.T) {
+	roles := NewRoleService(repository.NewMemoryStore())
+	for _, role := range []models.Role{models.RoleUser, "superuser"} {
+		if err := roles.Grant(context.Background(), "0xa1", role, ""); !errors.Is(err, ErrInvalidRole) {
+			t.Errorf("Grant(%q): err = %v, want ErrInvalidRole", role, err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+const (
+	ProviderWallet = "wallet"
+	ProviderLens   = "lens"
+)
+
+var ErrSessionInvalid = errors.New("invalid or expired session")
+
+type TokenPair struct {
+	AccessToken           string    `json:"access_token"`
+	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
+	RefreshToken          string    `json:"refresh_token"`
+	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
+}
+
+type SessionService struct {
+	store repository.Store
+}
+
+func NewSessionService(store repository.Store) *SessionService {
+	return &SessionService{
+		store: store,
+	}
+}
+
+// Create starts a new session for a user whose identity has already been
+// verified and returns its first token pair.
+func (s *SessionService) Create(ctx context.Context, user *models.User, provider, profileID string) (*TokenPair, error) {
+	refreshToken, hash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		RefreshTokenHash: hash,
+		UserID:           user.ID,
+		Address:          user.Address,
+		Provider:         provider,
+		ProfileID:        profileID,
+		ExpiresAt:        now.Add(auth.RefreshTokenTTL),
+	}
+	if err := s.store.Sessions().Create(ctx, &session); err != nil {
+		return nil, err
+	}
+
+	return s.issue(&session, refreshToken, now)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The refresh token is
+// rotated, so each one can be used only once.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	now := time.Now()
+	newToken, newHash, err := auth.NewRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	var session *models.Session
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		session, err = tx.Sessions().GetByRefreshHash(ctx, auth.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrSessionInvalid
+			}
+			return err
+		}
+		if !session.Active(now) {
+			return ErrSessionInvalid
+		}
+
+		rotated, err := tx.Sessions().Rotate(ctx, session, newHash, now)
+		if err != nil {
+			return err
+		}
+		// Another request rotated the same token first
+		if !rotated {
+			return ErrSessionInvalid
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issue(session, newToken, now)
+}
+
+// Authenticate verifies an access token and checks that its session has not
+// been revoked.
+func (s *SessionService) Authenticate(ctx context.Context, accessToken string) (*auth.Claims, error) {
+	claims, err := auth.ParseAccessToken(accessToken)
+	if err != nil {
+		return nil, ErrSessionInvalid
+	}
+
+	session, err := s.store.Sessions().Get(ctx, claims.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrSessionInvalid
+		}
+		return nil, err
+	}
+	if session.UserID != claims.UserID() || !session.Active(time.Now()) {
+		return nil, ErrSessionInvalid
+	}
+
+	return claims, nil
+}
+
+// Revoke ends a single session belonging to userID.
+func (s *SessionService) Revoke(ctx context.Context, userID string, sessionID uint) error {
+	return s.store.Sessions().Revoke(ctx, userID, sessionID, time.Now())
+}
+
+// RevokeAll ends every active session of userID and returns how many were revoked.
+func (s *SessionService) RevokeAll(ctx context.Context, userID string) (int64, error) {
+	return s.store.Sessions().RevokeAll(ctx, userID, time.Now())
+}
+
+func (s *SessionService) issue(session *models.Session, refreshToken string, now time.Time) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := auth.IssueAccessToken(session.UserID, auth.Claims{
+		Address:   session.Address,
+		Provider:  session.Provider,
+		ProfileID: session.ProfileID,
+		SessionID: session.ID,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TokenPair{
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
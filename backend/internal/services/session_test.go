@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bountyBoard/internal/repository"
+)
+
+func TestSessionLifecycle(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	sessions := NewSessionService(store)
+
+	user, err := NewUserService(store).FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := sessions.Create(ctx, user, ProviderWallet, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := sessions.Authenticate(ctx, tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID() != user.ID || claims.Provider != ProviderWallet {
+		t.Fatalf("claims = %+v", claims)
+	}
+
+	refreshed, err := sessions.Refresh(ctx, tokens.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.RefreshToken == tokens.RefreshToken {
+		t.Fatal("refresh token was not rotated")
+	}
+	// A refresh token can be used only once
+	if _, err := sessions.Refresh(ctx, tokens.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("reused refresh token: err = %v, want ErrSessionInvalid", err)
+	}
+
+	if err := sessions.Revoke(ctx, user.ID, claims.SessionID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sessions.Authenticate(ctx, refreshed.AccessToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("revoked session: err = %v, want ErrSessionInvalid", err)
+	}
+	if _, err := sessions.Refresh(ctx, refreshed.RefreshToken); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("revoked session refresh: err = %v, want ErrSessionInvalid", err)
+	}
+}
+
+func TestSessionRevokeAll(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	sessions := NewSessionService(store)
+
+	user, err := NewUserService(store).FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var access []string
+	for i := 0; i < 3; i++ {
+		tokens, err := sessions.Create(ctx, user, ProviderWallet, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		access = append(access, tokens.AccessToken)
+	}
+
+	revoked, err := sessions.RevokeAll(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if revoked != 3 {
+		t.Fatalf("revoked %d sessions, want 3", revoked)
+	}
+	for _, token := range access {
+		if _, err := sessions.Authenticate(ctx, token); !errors.Is(err, ErrSessionInvalid) {
+			t.Fatalf("err = %v, want ErrSessionInvalid", err)
+		}
+	}
+}
+
+func TestAuthenticateRejectsGarbage(t *testing.T) {
+	sessions := NewSessionService(repository.NewMemoryStore())
+	if _, err := sessions.Authenticate(context.Background(), "not-a-token"); !errors.Is(err, ErrSessionInvalid) {
+		t.Fatalf("err = %v, want ErrSessionInvalid", err)
+	}
+}
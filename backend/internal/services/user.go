@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+)
+
+type UserService struct {
+	store repository.Store
+}
+
+func NewUserService(store repository.Store) *UserService {
+	return &UserService{
+		store: store,
+	}
+}
+
+// FindOrCreateByAddress returns the user owning a wallet address, creating the
+// user and their initial reputation on first sign-in. Callers must have
+// verified ownership of the address before calling this.
+func (s *UserService) FindOrCreateByAddress(ctx context.Context, address string) (*models.User, error) {
+	address = strings.ToLower(address)
+
+	user, err := s.store.Users().GetByAddress(ctx, address)
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	user = &models.User{
+		ID:      address, // Using wallet address as ID
+		Address: address,
+	}
+	err = s.store.Transaction(ctx, func(tx repository.Store) error {
+		if err := tx.Users().Create(ctx, user); err != nil {
+			return err
+		}
+		return tx.Reputations().Create(ctx, &models.Reputation{
+			UserID: user.ID,
+			Score:  0,
+			Level:  1,
+		})
+	})
+	// A concurrent sign-in created the user first
+	if errors.Is(err, repository.ErrDuplicate) {
+		return s.store.Users().GetByAddress(ctx, address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// ErrLensProfileTaken is returned when a Lens profile is owned by a wallet
+// other than the user's.
+var ErrLensProfileTaken = errors.New("Lens profile is linked to another account")
+
+// LinkLensProfile attaches a verified Lens profile to the wallet user that owns
+// it. A profile belongs to one user at a time: when it was transferred, the
+// link moves from its previous owner to user, whose Lens sessions then stop
+// working. Linking a profile owned by another wallet fails with
+// ErrLensProfileTaken.
+func (s *UserService) LinkLensProfile(ctx context.Context, user *models.User, identity *auth.LensIdentity) error {
+	if !strings.EqualFold(user.Address, identity.OwnedBy.Hex()) {
+		return ErrLensProfileTaken
+	}
+	profileID := identity.ProfileID
+	if user.LensProfileID != nil && *user.LensProfileID == profileID {
+		return nil
+	}
+
+	return s.store.Transaction(ctx, func(tx repository.Store) error {
+		previous, err := tx.Users().GetByLensProfile(ctx, profileID)
+		switch {
+		case err == nil && previous.ID != user.ID:
+			previous.LensProfileID = nil
+			if err := tx.Users().Save(ctx, previous); err != nil {
+				return err
+			}
+		case err != nil && !errors.Is(err, repository.ErrNotFound):
+			return err
+		}
+
+		linked := *user
+		linked.LensProfileID = &profileID
+		if err := tx.Users().Save(ctx, &linked); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrLensProfileTaken
+			}
+			return err
+		}
+		user.LensProfileID = &profileID
+		return nil
+	})
+}
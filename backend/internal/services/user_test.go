@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFindOrCreateByAddress(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	users := NewUserService(store)
+
+	user, err := users.FindOrCreateByAddress(ctx, "0xABCDEF0000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "0xabcdef0000000000000000000000000000000001" || user.Address != user.ID {
+		t.Fatalf("user = %+v, want lowercase address as ID", user)
+	}
+	if _, err := store.Reputations().GetByUserID(ctx, user.ID); err != nil {
+		t.Fatalf("reputation not created: %v", err)
+	}
+
+	again, err := users.FindOrCreateByAddress(ctx, "0xabcdef0000000000000000000000000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != user.ID {
+		t.Fatalf("second sign-in returned %s, want %s", again.ID, user.ID)
+	}
+}
+
+// lensIdentity is a verified Lens profile owned by address.
+func lensIdentity(profileID, address string) *auth.LensIdentity {
+	return &auth.LensIdentity{ProfileID: profileID, OwnedBy: common.HexToAddress(address)}
+}
+
+func TestLinkLensProfile(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	users := NewUserService(store)
+
+	alice, err := users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := users.LinkLensProfile(ctx, alice, lensIdentity("0x01", alice.Address)); err != nil {
+		t.Fatal(err)
+	}
+	stored, err := store.Users().Get(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.LensProfileID == nil || *stored.LensProfileID != "0x01" {
+		t.Fatalf("lens profile = %v, want 0x01", stored.LensProfileID)
+	}
+
+	// Linking again is a no-op
+	if err := users.LinkLensProfile(ctx, alice, lensIdentity("0x01", alice.Address)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Bob cannot take a profile Alice still owns
+	if err := users.LinkLensProfile(ctx, bob, lensIdentity("0x01", alice.Address)); !errors.Is(err, ErrLensProfileTaken) {
+		t.Fatalf("err = %v, want ErrLensProfileTaken", err)
+	}
+	if bob.LensProfileID != nil {
+		t.Fatalf("bob's profile was changed to %s", *bob.LensProfileID)
+	}
+}
+
+func TestLinkTransferredLensProfile(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	users := NewUserService(store)
+
+	alice, err := users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000a1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := users.FindOrCreateByAddress(ctx, "0x00000000000000000000000000000000000000b0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := users.LinkLensProfile(ctx, alice, lensIdentity("0x01", alice.Address)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The profile now belongs to Bob's wallet, so the link moves to him
+	if err := users.LinkLensProfile(ctx, bob, lensIdentity("0x01", bob.Address)); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := store.Users().GetByLensProfile(ctx, "0x01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.ID != bob.ID {
+		t.Fatalf("profile linked to %s, want %s", owner.ID, bob.ID)
+	}
+	previous, err := store.Users().Get(ctx, alice.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if previous.LensProfileID != nil {
+		t.Fatalf("alice still links %s", *previous.LensProfileID)
+	}
+}
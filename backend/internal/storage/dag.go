@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// The CIDs handed out by IPFS nodes are the roots of UnixFS DAGs, not hashes
+// of the bytes. dagBuilder rebuilds the DAG that `ipfs add` creates with its
+// default settings (256 KiB chunks, balanced layout, 174 links per node) so
+// that CIDs can be checked locally:
+//
+//   - CIDv1 uses raw leaves, so content of a single chunk has a raw CID.
+//   - CIDv0 wraps every leaf in a dag-pb node.
+const (
+	chunkSize    = 256 << 10
+	linksPerNode = 174
+)
+
+// unixfsFile is the UnixFS data type of file nodes.
+const unixfsFile = 2
+
+type dagLink struct {
+	cid      cid.Cid
+	tsize    uint64 // encoded size of the subtree
+	filesize uint64 // content bytes in the subtree
+}
+
+// dagBuilder computes the CID of the content written to it.
+type dagBuilder struct {
+	v0     bool
+	chunk  []byte
+	leaves []dagLink
+}
+
+func newDAGBuilder(v0 bool) *dagBuilder {
+	return &dagBuilder{v0: v0, chunk: make([]byte, 0, chunkSize)}
+}
+
+func (b *dagBuilder) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		take := chunkSize - len(b.chunk)
+		if take > len(p) {
+			take = len(p)
+		}
+		b.chunk = append(b.chunk, p[:take]...)
+		p = p[take:]
+		if len(b.chunk) == chunkSize {
+			b.flush()
+		}
+	}
+	return n, nil
+}
+
+func (b *dagBuilder) flush() {
+	var link dagLink
+	if b.v0 {
+		node := encodeNode(nil, encodeUnixFS(b.chunk, uint64(len(b.chunk)), nil))
+		link = dagLink{cid: sum(cid.DagProtobuf, node, true), tsize: uint64(len(node))}
+	} else {
+		link = dagLink{cid: sum(cid.Raw, b.chunk, false), tsize: uint64(len(b.chunk))}
+	}
+	link.filesize = uint64(len(b.chunk))
+	b.leaves = append(b.leaves, link)
+	b.chunk = b.chunk[:0]
+}
+
+// Sum returns the root CID of everything written so far.
+func (b *dagBuilder) Sum() cid.Cid {
+	if len(b.chunk) > 0 || len(b.leaves) == 0 {
+		b.flush()
+	}
+
+	level := b.leaves
+	for len(level) > 1 {
+		var parents []dagLink
+		for start := 0; start < len(level); start += linksPerNode {
+			end := start + linksPerNode
+			if end > len(level) {
+				end = len(level)
+			}
+			parents = append(parents, b.parent(level[start:end]))
+		}
+		level = parents
+	}
+	return level[0].cid
+}
+
+// parent builds the dag-pb node linking children.
+func (b *dagBuilder) parent(children []dagLink) dagLink {
+	var filesize, tsize uint64
+	blocksizes := make([]uint64, len(children))
+	for i, child := range children {
+		filesize += child.filesize
+		tsize += child.tsize
+		blocksizes[i] = child.filesize
+	}
+	node := encodeNode(children, encodeUnixFS(nil, filesize, blocksizes))
+	return dagLink{
+		cid:      sum(cid.DagProtobuf, node, b.v0),
+		tsize:    tsize + uint64(len(node)),
+		filesize: filesize,
+	}
+}
+
+func sum(codec uint64, data []byte, v0 bool) cid.Cid {
+	prefix := cid.Prefix{Version: 1, Codec: codec, MhType: mh.SHA2_256, MhLength: -1}
+	if v0 {
+		prefix = cid.Prefix{Version: 0, Codec: cid.DagProtobuf, MhType: mh.SHA2_256, MhLength: -1}
+	}
+	c, err := prefix.Sum(data)
+	if err != nil {
+		// sha2-256 is always available
+		panic(err)
+	}
+	return c
+}
+
+// encodeNode encodes a dag-pb PBNode. Links come before Data, as the
+// canonical encoding requires.
+func encodeNode(links []dagLink, data []byte) []byte {
+	var out []byte
+	for _, link := range links {
+		var l []byte
+		l = appendBytes(l, 1, link.cid.Bytes())
+		l = appendBytes(l, 2, nil) // empty name
+		l = appendVarint(l, 3, link.tsize)
+		out = appendBytes(out, 2, l)
+	}
+	return appendBytes(out, 1, data)
+}
+
+// encodeUnixFS encodes the UnixFS Data message of a file node.
+func encodeUnixFS(data []byte, filesize uint64, blocksizes []uint64) []byte {
+	out := appendVarint(nil, 1, unixfsFile)
+	if len(data) > 0 {
+		out = appendBytes(out, 2, data)
+	}
+	out = appendVarint(out, 3, filesize)
+	for _, size := range blocksizes {
+		out = appendVarint(out, 4, size)
+	}
+	return out
+}
+
+func appendVarint(b []byte, field int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendBytes(b []byte, field int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// ComputeCID returns the CIDv1 IPFS assigns to the content of r.
+func ComputeCID(r io.Reader) (cid.Cid, error) {
+	b := newDAGBuilder(false)
+	if _, err := io.Copy(b, r); err != nil {
+		return cid.Undef, err
+	}
+	return b.Sum(), nil
+}
+
+// builderFor returns a builder for content addressed by c, or false if c was
+// not produced by the default UnixFS layout.
+func builderFor(c cid.Cid) (*dagBuilder, bool) {
+	prefix := c.Prefix()
+	if prefix.MhType != mh.SHA2_256 {
+		return nil, false
+	}
+	switch {
+	case prefix.Version == 0:
+		return newDAGBuilder(true), true
+	case prefix.Codec == cid.Raw || prefix.Codec == cid.DagProtobuf:
+		return newDAGBuilder(false), true
+	}
+	return nil, false
+}
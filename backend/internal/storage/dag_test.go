@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+)
+
+// pattern returns n bytes that do not repeat within a chunk, so chunks of
+// different sizes never share a CID by accident.
+func pattern(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+// The expected CIDs are those `ipfs add` assigns with its default settings
+// (v0) and with --cid-version=1 (v1, which implies raw leaves).
+var dagVectors = []struct {
+	name string
+	data []byte
+	v0   string
+	v1   string
+}{
+	{"empty", nil,
+		"QmbFMke1KXqnYyBBWxB74N4c5SBnJMVAiMNRcGu6x1AwQH", "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku"},
+	{"one chunk", []byte("hello world\n"),
+		"QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o", "bafkreifjjcie6lypi6ny7amxnfftagclbuxndqonfipmb64f2km2devei4"},
+	{"exactly one chunk", pattern(chunkSize),
+		"QmeqfRyS3vkku7n6krqC3DgGMex3x2sCpSeKMDmrG13QQq", "bafkreibruh455iawsviqslif5c7uurdcfdemh22mtnytyzvnzn75kpejxy"},
+	{"one byte past a chunk", pattern(chunkSize + 1),
+		"QmUSjGawaz4ptvREcMKSMJneWCa5j8dAz2wSAAvHtW2rnB", "bafybeiexg2oqkfnj56l7fcmawswqbijt5shq4b5rg6a546uwpkqqzwjioi"},
+	{"multiple chunks", pattern(3*chunkSize + 1000),
+		"QmaMuznfvPDxKQmt58AWynv98rzyDT2nwQb4mafGJEmKGf", "bafybeibxcaffkga6wx7kvis5lrj6olydk6e7xmpcuspuptcg5tcucjbjji"},
+	{"full root node", pattern(linksPerNode * chunkSize),
+		"QmXCym15aFeWjAWyPFaAgwVmkuKB7EBsV77Skt54KmxChF", "bafybeihpe5snhzneq7xs53nivmsopto5lrogo3wjynauqylqeym5a3irbm"},
+	{"two levels", pattern(linksPerNode*chunkSize + 1),
+		"QmTedsTekQQkgACJXb1sPZSW8bLdS9LPMrT7L4YdjNRd4n", "bafybeib4y7ghw2rq7bracc4xwtxrbzo7cfvagdpte2tmrkgwl6dyard3cm"},
+}
+
+func TestComputeCID(t *testing.T) {
+	for _, v := range dagVectors {
+		t.Run(v.name, func(t *testing.T) {
+			got, err := ComputeCID(bytes.NewReader(v.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != v.v1 {
+				t.Fatalf("CID = %s, want %s", got, v.v1)
+			}
+		})
+	}
+}
+
+func TestBuilderForMatchesCIDVersion(t *testing.T) {
+	for _, v := range dagVectors {
+		for _, want := range []string{v.v0, v.v1} {
+			t.Run(v.name+"/"+want[:4], func(t *testing.T) {
+				c := cid.MustParse(want)
+				builder, ok := builderFor(c)
+				if !ok {
+					t.Fatalf("no builder for %s", c)
+				}
+				// Writes that straddle chunk boundaries build the same DAG
+				buf := make([]byte, 1000)
+				if _, err := io.CopyBuffer(struct{ io.Writer }{builder}, bytes.NewReader(v.data), buf); err != nil {
+					t.Fatal(err)
+				}
+				if got := builder.Sum(); !got.Equals(c) {
+					t.Fatalf("CID = %s, want %s", got, c)
+				}
+			})
+		}
+	}
+}
+
+func TestVerifyRejectsModifiedContent(t *testing.T) {
+	c := cid.MustParse(dagVectors[3].v1)
+	data := pattern(chunkSize + 1)
+	data[len(data)-1]++
+
+	rc, err := verify(io.NopCloser(bytes.NewReader(data)), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(rc); !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("reading modified content: err = %v, want ErrCIDMismatch", err)
+	}
+}
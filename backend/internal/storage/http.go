@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/ipfs/go-cid"
+)
+
+// upload streams r to url as the "file" part of a multipart form while
+// computing its CID. fields are written before the file. The CID is only
+// valid once the response has been received.
+func upload(ctx context.Context, client *http.Client, url string, fields map[string]string, r io.Reader,
+	authorize func(*http.Request)) (*http.Response, *dagBuilder, error) {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	builder := newDAGBuilder(false)
+
+	written := make(chan struct{})
+	go func() {
+		defer close(written)
+		err := func() error {
+			for name, value := range fields {
+				if err := form.WriteField(name, value); err != nil {
+					return err
+				}
+			}
+			part, err := form.CreateFormFile("file", "file")
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(part, io.TeeReader(r, builder)); err != nil {
+				return err
+			}
+			return form.Close()
+		}()
+		pw.CloseWithError(err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, pr)
+	if err != nil {
+		pr.Close()
+		<-written
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	authorize(req)
+
+	resp, err := client.Do(req)
+	// Unblock the writer if the request ended before the body was read
+	pr.Close()
+	<-written
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, builder, nil
+}
+
+// checkUpload compares the CID a service returned with the one computed from
+// the uploaded bytes.
+func checkUpload(returned string, builder *dagBuilder) (cid.Cid, error) {
+	c, err := cid.Decode(returned)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("invalid CID in response: %w", err)
+	}
+	if want := builder.Sum(); !c.Equals(want) {
+		return c, fmt.Errorf("%w: got %s, computed %s", ErrCIDMismatch, c, want)
+	}
+	return c, nil
+}
+
+// decodeResponse decodes a JSON response body into v, turning non-2xx
+// statuses into errors.
+func decodeResponse(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &statusError{Status: resp.StatusCode, Body: string(body)}
+	}
+	if v == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// statusError is an unsuccessful HTTP response from a storage service.
+type statusError struct {
+	Status int
+	Body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("request failed with status %d: %s", e.Status, e.Body)
+}
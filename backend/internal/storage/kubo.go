@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+type KuboConfig struct {
+	URL      string // RPC API base, e.g. http://127.0.0.1:5001/api/v0
+	Username string // optional basic auth
+	Password string
+}
+
+// KuboConfigFromEnv reads IPFS_API_URL, IPFS_PROJECT_ID and
+// IPFS_PROJECT_SECRET.
+func KuboConfigFromEnv() KuboConfig {
+	cfg := KuboConfig{
+		URL:      os.Getenv("IPFS_API_URL"),
+		Username: os.Getenv("IPFS_PROJECT_ID"),
+		Password: os.Getenv("IPFS_PROJECT_SECRET"),
+	}
+	if cfg.URL == "" {
+		cfg.URL = "http://127.0.0.1:5001/api/v0"
+	}
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/")
+	return cfg
+}
+
+// KuboStore talks to the RPC API of Kubo or a compatible service.
+type KuboStore struct {
+	cfg    KuboConfig
+	client *http.Client
+}
+
+func NewKuboStore(cfg KuboConfig) *KuboStore {
+	return &KuboStore{cfg: cfg, client: &http.Client{}}
+}
+
+func (s *KuboStore) authorize(req *http.Request) {
+	if s.cfg.Username != "" || s.cfg.Password != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+}
+
+// call POSTs an RPC command. Kubo only accepts POST.
+func (s *KuboStore) call(ctx context.Context, command string, args url.Values) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.cfg.URL+"/"+command+"?"+args.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	s.authorize(req)
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("ipfs %s failed: %w", command, err)
+	}
+	return resp, nil
+}
+
+// Put adds the content unpinned and pins it once its CID checks out, so
+// content that does not match is left to garbage collection and a pin the
+// returned CID already had is never removed.
+func (s *KuboStore) Put(ctx context.Context, r io.Reader) (cid.Cid, error) {
+	args := url.Values{
+		"cid-version": {"1"},
+		"raw-leaves":  {"true"},
+		"pin":         {"false"},
+	}
+	resp, builder, err := upload(ctx, s.client, s.cfg.URL+"/add?"+args.Encode(), nil, r, s.authorize)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("ipfs add failed: %w", err)
+	}
+
+	var result struct {
+		Hash string `json:"Hash"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return cid.Undef, fmt.Errorf("ipfs add failed: %w", err)
+	}
+	c, err := checkUpload(result.Hash, builder)
+	if err != nil {
+		log.Printf("Rejected ipfs add result: %v", err)
+		return cid.Undef, err
+	}
+	if err := s.Pin(ctx, c); err != nil {
+		return cid.Undef, err
+	}
+	return c, nil
+}
+
+func (s *KuboStore) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	resp, err := s.call(ctx, "cat", url.Values{"arg": {c.String()}})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := decodeResponse(resp, nil)
+		if isKuboNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ipfs cat failed: %w", err)
+	}
+	return verify(resp.Body, c)
+}
+
+func (s *KuboStore) Pin(ctx context.Context, c cid.Cid) error {
+	resp, err := s.call(ctx, "pin/add", url.Values{"arg": {c.String()}})
+	if err != nil {
+		return err
+	}
+	if err := decodeResponse(resp, nil); err != nil {
+		return fmt.Errorf("ipfs pin add failed: %w", err)
+	}
+	return nil
+}
+
+func (s *KuboStore) Unpin(ctx context.Context, c cid.Cid) error {
+	resp, err := s.call(ctx, "pin/rm", url.Values{"arg": {c.String()}})
+	if err != nil {
+		return err
+	}
+	err = decodeResponse(resp, nil)
+	if err != nil && !isKuboNotPinned(err) {
+		return fmt.Errorf("ipfs pin rm failed: %w", err)
+	}
+	return nil
+}
+
+func (s *KuboStore) Stat(ctx context.Context, c cid.Cid) (*Stat, error) {
+	resp, err := s.call(ctx, "files/stat", url.Values{"arg": {"/ipfs/" + c.String()}})
+	if err != nil {
+		return nil, err
+	}
+	var stat struct {
+		Size int64 `json:"Size"`
+	}
+	if err := decodeResponse(resp, &stat); err != nil {
+		if isKuboNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("ipfs files stat failed: %w", err)
+	}
+
+	resp, err = s.call(ctx, "pin/ls", url.Values{"arg": {c.String()}, "type": {"recursive"}})
+	if err != nil {
+		return nil, err
+	}
+	err = decodeResponse(resp, nil)
+	if err != nil && !isKuboNotPinned(err) {
+		return nil, fmt.Errorf("ipfs pin ls failed: %w", err)
+	}
+	return &Stat{CID: c, Size: stat.Size, Pinned: err == nil}, nil
+}
+
+// Kubo reports command errors as HTTP 500 with a message in the body.
+func isKuboNotPinned(err error) bool {
+	var status *statusError
+	return errors.As(err, &status) && strings.Contains(status.Body, "not pinned")
+}
+
+func isKuboNotFound(err error) bool {
+	var status *statusError
+	return errors.As(err, &status) &&
+		(strings.Contains(status.Body, "not found") || strings.Contains(status.Body, "no link named"))
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeKubo serves the add and pin commands of the Kubo RPC API.
+type fakeKubo struct {
+	hash     string // CID returned by add; the real one if empty
+	addPin   string
+	commands []string
+}
+
+func newFakeKubo(t *testing.T) (*fakeKubo, *KuboStore) {
+	t.Helper()
+	k := &fakeKubo{}
+	server := httptest.NewServer(http.HandlerFunc(k.serve))
+	t.Cleanup(server.Close)
+	return k, NewKuboStore(KuboConfig{URL: server.URL + "/api/v0"})
+}
+
+func (k *fakeKubo) serve(w http.ResponseWriter, r *http.Request) {
+	command := strings.TrimPrefix(r.URL.Path, "/api/v0/")
+	k.commands = append(k.commands, command)
+	switch command {
+	case "add":
+		k.addPin = r.URL.Query().Get("pin")
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c, _ := ComputeCID(file)
+		hash := c.String()
+		if k.hash != "" {
+			hash = k.hash
+		}
+		json.NewEncoder(w).Encode(map[string]string{"Hash": hash})
+	case "pin/add", "pin/rm":
+		io.WriteString(w, "{}")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestKuboPutPinsCheckedContent(t *testing.T) {
+	k, store := newFakeKubo(t)
+
+	c, err := store.Put(context.Background(), strings.NewReader("bounty metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ComputeCID(strings.NewReader("bounty metadata"))
+	if !c.Equals(want) {
+		t.Fatalf("CID = %s, want %s", c, want)
+	}
+	if k.addPin != "false" {
+		t.Fatalf("add pin = %q, want content added unpinned", k.addPin)
+	}
+	if got := strings.Join(k.commands, ","); got != "add,pin/add" {
+		t.Fatalf("commands = %s, want add,pin/add", got)
+	}
+}
+
+func TestKuboPutLeavesMismatchUnpinned(t *testing.T) {
+	k, store := newFakeKubo(t)
+	k.hash = dagVectors[0].v1
+
+	if _, err := store.Put(context.Background(), strings.NewReader("bounty metadata")); !errors.Is(err, ErrCIDMismatch) {
+		t.Fatalf("err = %v, want ErrCIDMismatch", err)
+	}
+	// The returned CID may be pinned by earlier content, so it is not touched
+	if got := strings.Join(k.commands, ","); got != "add" {
+		t.Fatalf("commands = %s, want only add", got)
+	}
+}
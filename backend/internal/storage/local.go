@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+)
+
+// LocalStore is a content-addressed store in a directory on disk. Objects
+// are sharded into subdirectories by the last two characters of their CID.
+// Each object's pin count is kept in a ".pins" file beside it, and the object
+// is removed once every Put and Pin of it has been unpinned.
+type LocalStore struct {
+	dir string
+	mu  sync.Mutex // guards pin counts
+}
+
+func NewLocalStore(dir string) (*LocalStore, error) {
+	if err := os.MkdirAll(filepath.Join(dir, "tmp"), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create content store: %w", err)
+	}
+	return &LocalStore{dir: dir}, nil
+}
+
+func (s *LocalStore) path(c cid.Cid) string {
+	name := c.String()
+	return filepath.Join(s.dir, name[len(name)-2:], name)
+}
+
+// Put writes to a temporary file while hashing and moves it into place once
+// the CID is known, so readers never see partial content.
+func (s *LocalStore) Put(ctx context.Context, r io.Reader) (cid.Cid, error) {
+	tmp, err := os.CreateTemp(filepath.Join(s.dir, "tmp"), "put-")
+	if err != nil {
+		return cid.Undef, err
+	}
+	defer os.Remove(tmp.Name())
+
+	builder := newDAGBuilder(false)
+	_, err = io.Copy(io.MultiWriter(tmp, builder), r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	c := builder.Sum()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.pins(c)
+	if err != nil {
+		return cid.Undef, err
+	}
+	if pins == 0 {
+		path := s.path(c)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return cid.Undef, err
+		}
+		if err := os.Rename(tmp.Name(), path); err != nil {
+			return cid.Undef, err
+		}
+	}
+	if err := s.setPins(c, pins+1); err != nil {
+		return cid.Undef, err
+	}
+	return c, nil
+}
+
+func (s *LocalStore) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return verify(f, c)
+}
+
+func (s *LocalStore) Pin(ctx context.Context, c cid.Cid) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.pins(c)
+	if err != nil {
+		return err
+	}
+	if pins == 0 {
+		return ErrNotFound
+	}
+	return s.setPins(c, pins+1)
+}
+
+func (s *LocalStore) Unpin(ctx context.Context, c cid.Cid) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pins, err := s.pins(c)
+	if err != nil || pins == 0 {
+		return err
+	}
+	return s.setPins(c, pins-1)
+}
+
+// pins returns the pin count of c, which is zero if it is not stored.
+func (s *LocalStore) pins(c cid.Cid) (int, error) {
+	data, err := os.ReadFile(s.path(c) + ".pins")
+	if errors.Is(err, fs.ErrNotExist) {
+		// Objects stored before pins were counted have one
+		_, err := os.Stat(s.path(c))
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return 0, nil
+		case err != nil:
+			return 0, err
+		}
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	pins, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid pin count for %s: %w", c, err)
+	}
+	return pins, nil
+}
+
+// setPins stores the pin count of c, removing the object when it drops to
+// zero.
+func (s *LocalStore) setPins(c cid.Cid, pins int) error {
+	path := s.path(c)
+	if pins > 0 {
+		return os.WriteFile(path+".pins", []byte(strconv.Itoa(pins)), 0o644)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.Remove(path + ".pins"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) Stat(ctx context.Context, c cid.Cid) (*Stat, error) {
+	info, err := os.Stat(s.path(c))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Stat{CID: c, Size: info.Size(), Pinned: true}, nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/ipfs/go-cid"
+)
+
+// MemoryStore keeps content in memory, for tests and local development.
+// Content is kept until every Put and Pin of it has been unpinned.
+type MemoryStore struct {
+	mu      sync.Mutex
+	content map[cid.Cid][]byte
+	pins    map[cid.Cid]int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		content: make(map[cid.Cid][]byte),
+		pins:    make(map[cid.Cid]int),
+	}
+}
+
+func (s *MemoryStore) Put(ctx context.Context, r io.Reader) (cid.Cid, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c, err := ComputeCID(bytes.NewReader(data))
+	if err != nil {
+		return cid.Undef, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.content[c] = data
+	s.pins[c]++
+	return c, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	s.mu.Lock()
+	data, ok := s.content[c]
+	s.mu.Unlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return verify(io.NopCloser(bytes.NewReader(data)), c)
+}
+
+func (s *MemoryStore) Pin(ctx context.Context, c cid.Cid) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.content[c]; !ok {
+		return ErrNotFound
+	}
+	s.pins[c]++
+	return nil
+}
+
+func (s *MemoryStore) Unpin(ctx context.Context, c cid.Cid) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pins[c] > 1 {
+		s.pins[c]--
+		return nil
+	}
+	delete(s.content, c)
+	delete(s.pins, c)
+	return nil
+}
+
+func (s *MemoryStore) Stat(ctx context.Context, c cid.Cid) (*Stat, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.content[c]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &Stat{CID: c, Size: int64(len(data)), Pinned: true}, nil
+}
@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+)
+
+type PinataConfig struct {
+	JWT        string // API key JWT
+	APIURL     string // e.g. https://api.pinata.cloud
+	GatewayURL string // gateway content is read from, e.g. https://gateway.pinata.cloud
+}
+
+// PinataConfigFromEnv reads PINATA_JWT, PINATA_API_URL and
+// PINATA_GATEWAY_URL.
+func PinataConfigFromEnv() PinataConfig {
+	cfg := PinataConfig{
+		JWT:        os.Getenv("PINATA_JWT"),
+		APIURL:     os.Getenv("PINATA_API_URL"),
+		GatewayURL: os.Getenv("PINATA_GATEWAY_URL"),
+	}
+	if cfg.APIURL == "" {
+		cfg.APIURL = "https://api.pinata.cloud"
+	}
+	if cfg.GatewayURL == "" {
+		cfg.GatewayURL = "https://gateway.pinata.cloud"
+	}
+	cfg.APIURL = strings.TrimSuffix(cfg.APIURL, "/")
+	cfg.GatewayURL = strings.TrimSuffix(cfg.GatewayURL, "/")
+	return cfg
+}
+
+// PinataStore pins content with Pinata and reads it through a gateway.
+type PinataStore struct {
+	cfg    PinataConfig
+	client *http.Client
+}
+
+func NewPinataStore(cfg PinataConfig) *PinataStore {
+	return &PinataStore{cfg: cfg, client: &http.Client{}}
+}
+
+func (s *PinataStore) authorize(req *http.Request) {
+	req.Header.Set("Authorization", "Bearer "+s.cfg.JWT)
+}
+
+func (s *PinataStore) do(ctx context.Context, method, url string, body interface{}) (*http.Response, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	s.authorize(req)
+	return s.client.Do(req)
+}
+
+func (s *PinataStore) Put(ctx context.Context, r io.Reader) (cid.Cid, error) {
+	fields := map[string]string{"pinataOptions": `{"cidVersion":1}`}
+	resp, builder, err := upload(ctx, s.client, s.cfg.APIURL+"/pinning/pinFileToIPFS", fields, r, s.authorize)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("pinata upload failed: %w", err)
+	}
+
+	var result struct {
+		IpfsHash    string `json:"IpfsHash"`
+		IsDuplicate bool   `json:"isDuplicate"`
+	}
+	if err := decodeResponse(resp, &result); err != nil {
+		return cid.Undef, fmt.Errorf("pinata upload failed: %w", err)
+	}
+	c, err := checkUpload(result.IpfsHash, builder)
+	if errors.Is(err, ErrCIDMismatch) {
+		log.Printf("Rejected Pinata upload: %v", err)
+		// A duplicate was pinned before this upload and keeps its pin
+		if !result.IsDuplicate {
+			if err := s.Unpin(ctx, c); err != nil {
+				log.Printf("Failed to unpin mismatched upload %s: %v", c, err)
+			}
+		}
+		return cid.Undef, err
+	}
+	return c, err
+}
+
+// gateway requests content from the gateway. The gateway may be public, so
+// the API key is not sent along.
+func (s *PinataStore) gateway(ctx context.Context, method string, c cid.Cid) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.cfg.GatewayURL+"/ipfs/"+c.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	return s.client.Do(req)
+}
+
+func (s *PinataStore) Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error) {
+	resp, err := s.gateway(ctx, http.MethodGet, c)
+	if err != nil {
+		return nil, fmt.Errorf("pinata gateway request failed: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pinata gateway request failed: %w", decodeResponse(resp, nil))
+	}
+	return verify(resp.Body, c)
+}
+
+func (s *PinataStore) Pin(ctx context.Context, c cid.Cid) error {
+	resp, err := s.do(ctx, http.MethodPost, s.cfg.APIURL+"/pinning/pinByHash", map[string]string{"hashToPin": c.String()})
+	if err != nil {
+		return fmt.Errorf("pinata pin failed: %w", err)
+	}
+	if err := decodeResponse(resp, nil); err != nil {
+		return fmt.Errorf("pinata pin failed: %w", err)
+	}
+	return nil
+}
+
+func (s *PinataStore) Unpin(ctx context.Context, c cid.Cid) error {
+	resp, err := s.do(ctx, http.MethodDelete, s.cfg.APIURL+"/pinning/unpin/"+c.String(), nil)
+	if err != nil {
+		return fmt.Errorf("pinata unpin failed: %w", err)
+	}
+	err = decodeResponse(resp, nil)
+	var status *statusError
+	if errors.As(err, &status) && status.Status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("pinata unpin failed: %w", err)
+	}
+	return nil
+}
+
+// Stat reports the pin from the pin list and the size from the gateway.
+func (s *PinataStore) Stat(ctx context.Context, c cid.Cid) (*Stat, error) {
+	query := url.Values{"hashContains": {c.String()}, "status": {"pinned"}}
+	resp, err := s.do(ctx, http.MethodGet, s.cfg.APIURL+"/data/pinList?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("pinata pin list failed: %w", err)
+	}
+	var pins struct {
+		Rows []struct {
+			Hash string `json:"ipfs_pin_hash"`
+		} `json:"rows"`
+	}
+	if err := decodeResponse(resp, &pins); err != nil {
+		return nil, fmt.Errorf("pinata pin list failed: %w", err)
+	}
+	pinned := false
+	for _, row := range pins.Rows {
+		if row.Hash == c.String() {
+			pinned = true
+		}
+	}
+
+	resp, err = s.gateway(ctx, http.MethodHead, c)
+	if err != nil {
+		return nil, fmt.Errorf("pinata gateway request failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pinata gateway request failed with status %d", resp.StatusCode)
+	}
+	return &Stat{CID: c, Size: resp.ContentLength, Pinned: pinned}, nil
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakePinata serves the Pinata API and gateway from one server.
+type fakePinata struct {
+	*httptest.Server
+	mu          sync.Mutex
+	hash        string // CID returned by uploads; the real one if empty
+	duplicate   bool
+	content     map[string][]byte
+	unpinned    []string
+	gatewayAuth []string
+}
+
+func newFakePinata(t *testing.T) *fakePinata {
+	t.Helper()
+	p := &fakePinata{content: make(map[string][]byte)}
+	p.Server = httptest.NewServer(http.HandlerFunc(p.serve))
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakePinata) store() *PinataStore {
+	return NewPinataStore(PinataConfig{JWT: "secret-jwt", APIURL: p.URL + "/api", GatewayURL: p.URL + "/gateway"})
+}
+
+func (p *fakePinata) serve(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if strings.HasPrefix(r.URL.Path, "/gateway/ipfs/") {
+		p.gatewayAuth = append(p.gatewayAuth, r.Header.Get("Authorization"))
+		data, ok := p.content[strings.TrimPrefix(r.URL.Path, "/gateway/ipfs/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer secret-jwt" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.URL.Path == "/api/pinning/pinFileToIPFS":
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		c, _ := ComputeCID(bytes.NewReader(data))
+		hash := c.String()
+		if p.hash != "" {
+			hash = p.hash
+		}
+		p.content[hash] = data
+		json.NewEncoder(w).Encode(map[string]interface{}{"IpfsHash": hash, "isDuplicate": p.duplicate})
+	case strings.HasPrefix(r.URL.Path, "/api/pinning/unpin/"):
+		p.unpinned = append(p.unpinned, strings.TrimPrefix(r.URL.Path, "/api/pinning/unpin/"))
+	case r.URL.Path == "/api/data/pinList":
+		json.NewEncoder(w).Encode(map[string]interface{}{"rows": []map[string]string{{"ipfs_pin_hash": r.URL.Query().Get("hashContains")}}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestPinataRoundTripKeepsJWTFromGateway(t *testing.T) {
+	ctx := context.Background()
+	p := newFakePinata(t)
+	store := p.store()
+
+	c, err := store.Put(ctx, strings.NewReader("bounty metadata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc, err := store.Get(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil || string(data) != "bounty metadata" {
+		t.Fatalf("content = %q, %v", data, err)
+	}
+	stat, err := store.Stat(ctx, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stat.Pinned || stat.Size != int64(len("bounty metadata")) {
+		t.Fatalf("stat = %+v", stat)
+	}
+
+	if len(p.gatewayAuth) != 2 {
+		t.Fatalf("%d gateway requests, want 2", len(p.gatewayAuth))
+	}
+	for _, auth := range p.gatewayAuth {
+		if auth != "" {
+			t.Fatalf("gateway request sent Authorization %q", auth)
+		}
+	}
+}
+
+func TestPinataMismatchUnpinsOnlyNewPins(t *testing.T) {
+	for _, duplicate := range []bool{false, true} {
+		p := newFakePinata(t)
+		p.hash = dagVectors[0].v1 // the empty file, which is not what is uploaded
+		p.duplicate = duplicate
+
+		_, err := p.store().Put(context.Background(), strings.NewReader("bounty metadata"))
+		if !errors.Is(err, ErrCIDMismatch) {
+			t.Fatalf("duplicate=%v: err = %v, want ErrCIDMismatch", duplicate, err)
+		}
+		want := 1
+		if duplicate {
+			want = 0
+		}
+		if len(p.unpinned) != want {
+			t.Fatalf("duplicate=%v: unpinned %v, want %d unpins", duplicate, p.unpinned, want)
+		}
+	}
+}
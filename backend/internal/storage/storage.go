@@ -0,0 +1,125 @@
+// Package storage keeps content-addressed blobs such as bounty metadata,
+// attachments and badge metadata. Content is addressed by the CID IPFS
+// assigns to it, whichever backend holds it.
+package storage
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/ipfs/go-cid"
+)
+
+var (
+	ErrNotFound = errors.New("content not found")
+	// ErrCIDMismatch means content does not hash to the CID it is stored
+	// under.
+	ErrCIDMismatch = errors.New("content does not match its CID")
+	// ErrUnsupportedCID means a CID was not produced by the default UnixFS
+	// layout, so its content cannot be verified.
+	ErrUnsupportedCID = errors.New("unsupported CID")
+)
+
+// ContentStore stores content by CID. Content returned by Put is pinned.
+type ContentStore interface {
+	// Put stores and pins the content of r. The returned CID has been checked
+	// against the bytes read from r.
+	Put(ctx context.Context, r io.Reader) (cid.Cid, error)
+	// Get opens the content stored under c. Reading it fails with
+	// ErrCIDMismatch if the content does not match c.
+	Get(ctx context.Context, c cid.Cid) (io.ReadCloser, error)
+	// Pin keeps content from being garbage collected.
+	Pin(ctx context.Context, c cid.Cid) error
+	// Unpin releases a pin. Stores that count pins keep the content until
+	// every Put and Pin of it has been released. Unpinning content that is
+	// not pinned is not an error.
+	Unpin(ctx context.Context, c cid.Cid) error
+	Stat(ctx context.Context, c cid.Cid) (*Stat, error)
+}
+
+// Stat describes stored content.
+type Stat struct {
+	CID    cid.Cid `json:"cid"`
+	Size   int64   `json:"size"` // content bytes
+	Pinned bool    `json:"pinned"`
+}
+
+// FromEnv builds the store named by CONTENT_STORE:
+//
+//   - kubo (default): a Kubo-compatible HTTP API at IPFS_API_URL, with
+//     optional basic auth from IPFS_PROJECT_ID and IPFS_PROJECT_SECRET
+//   - pinata: Pinata, authenticated with PINATA_JWT
+//   - local: a directory on disk, CONTENT_STORE_DIR
+//   - memory: an in-process store that is lost on restart
+func FromEnv() (ContentStore, error) {
+	switch kind := os.Getenv("CONTENT_STORE"); kind {
+	case "", "kubo":
+		return NewKuboStore(KuboConfigFromEnv()), nil
+	case "pinata":
+		cfg := PinataConfigFromEnv()
+		if cfg.JWT == "" {
+			return nil, errors.New("PINATA_JWT is required when CONTENT_STORE is pinata")
+		}
+		return NewPinataStore(cfg), nil
+	case "local":
+		dir := os.Getenv("CONTENT_STORE_DIR")
+		if dir == "" {
+			dir = "data/content"
+		}
+		return NewLocalStore(dir)
+	case "memory":
+		log.Printf("Warning: CONTENT_STORE is memory, stored content is lost on restart")
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("invalid CONTENT_STORE: %q", kind)
+	}
+}
+
+// verifyingReader checks the content it reads against a CID. The last read
+// fails with ErrCIDMismatch instead of returning the final bytes and io.EOF
+// if the content does not match, so a mismatch is never mistaken for
+// complete content.
+type verifyingReader struct {
+	io.Closer
+	r       *bufio.Reader
+	want    cid.Cid
+	builder *dagBuilder
+}
+
+func verify(rc io.ReadCloser, c cid.Cid) (io.ReadCloser, error) {
+	builder, ok := builderFor(c)
+	if !ok {
+		rc.Close()
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedCID, c)
+	}
+	return &verifyingReader{Closer: rc, r: bufio.NewReader(rc), want: c, builder: builder}, nil
+}
+
+func (r *verifyingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.builder.Write(p[:n])
+	if err == nil {
+		// Look ahead so the check runs before the final bytes are returned
+		if _, peekErr := r.r.Peek(1); peekErr == io.EOF {
+			err = io.EOF
+		}
+	}
+	if err == io.EOF && !r.builder.Sum().Equals(r.want) {
+		return 0, fmt.Errorf("%w: %s", ErrCIDMismatch, r.want)
+	}
+	return n, err
+}
+
+// ParseCID parses a CID, wrapping failures in ErrUnsupportedCID.
+func ParseCID(s string) (cid.Cid, error) {
+	c, err := cid.Decode(s)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("%w: %v", ErrUnsupportedCID, err)
+	}
+	return c, nil
+}
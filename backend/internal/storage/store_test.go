@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+)
+
+// testPinCounts checks that content stays until every Put and Pin of it has
+// been unpinned.
+func testPinCounts(t *testing.T, store ContentStore) {
+	t.Helper()
+	ctx := context.Background()
+
+	var c cid.Cid
+	for i := 0; i < 2; i++ {
+		var err error
+		if c, err = store.Put(ctx, strings.NewReader("shared")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Pin(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 1; i <= 3; i++ {
+		if err := store.Unpin(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+		rc, err := store.Get(ctx, c)
+		if i == 3 {
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("get after every pin was released: err = %v, want ErrNotFound", err)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("get after %d of 3 unpins: %v", i, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil || string(data) != "shared" {
+			t.Fatalf("content after %d of 3 unpins = %q, %v", i, data, err)
+		}
+	}
+
+	if err := store.Unpin(ctx, c); err != nil {
+		t.Fatalf("unpin of removed content: %v", err)
+	}
+	if err := store.Pin(ctx, c); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("pin of removed content: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMemoryStorePinCounts(t *testing.T) {
+	testPinCounts(t, NewMemoryStore())
+}
+
+func TestLocalStorePinCounts(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testPinCounts(t, store)
+}
+
+func TestLocalStoreCountsUncountedObjectsOnce(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := store.Put(ctx, strings.NewReader("stored before pins were counted"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(store.path(c) + ".pins"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Pin(ctx, c); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.Unpin(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := store.Stat(ctx, c); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("stat after unpinning: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,391 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/clock"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxMintBackoff caps the delay between retries of a failing mint.
+const maxMintBackoff = time.Hour
+
+// mintBatchSize is how many due jobs one sweep picks up.
+const mintBatchSize = 50
+
+type BadgeMintConfig struct {
+	Contract    common.Address // deployed Reputation contract; zero disables minting
+	MinterKey   string         // hex private key of the contract owner
+	Interval    time.Duration  // how often to sweep for due jobs
+	MaxAttempts int            // failed attempts before a job is given up
+}
+
+// BadgeMintConfigFromEnv reads REPUTATION_ADDRESS, MINTER_PRIVATE_KEY,
+// BADGE_MINT_INTERVAL and BADGE_MINT_MAX_ATTEMPTS, falling back to defaults.
+// Minting is disabled when REPUTATION_ADDRESS is unset.
+func BadgeMintConfigFromEnv() (BadgeMintConfig, error) {
+	cfg := BadgeMintConfig{
+		MinterKey:   os.Getenv("MINTER_PRIVATE_KEY"),
+		Interval:    30 * time.Second,
+		MaxAttempts: 8,
+	}
+
+	if v := os.Getenv("REPUTATION_ADDRESS"); v != "" {
+		if !common.IsHexAddress(v) {
+			return cfg, fmt.Errorf("invalid REPUTATION_ADDRESS: %q", v)
+		}
+		if cfg.MinterKey == "" {
+			return cfg, errors.New("MINTER_PRIVATE_KEY is required when REPUTATION_ADDRESS is set")
+		}
+		cfg.Contract = common.HexToAddress(v)
+	}
+
+	var err error
+	if v := os.Getenv("BADGE_MINT_INTERVAL"); v != "" {
+		if cfg.Interval, err = time.ParseDuration(v); err != nil || cfg.Interval <= 0 {
+			return cfg, fmt.Errorf("invalid BADGE_MINT_INTERVAL: %q", v)
+		}
+	}
+	if v := os.Getenv("BADGE_MINT_MAX_ATTEMPTS"); v != "" {
+		if cfg.MaxAttempts, err = strconv.Atoi(v); err != nil || cfg.MaxAttempts <= 0 {
+			return cfg, fmt.Errorf("invalid BADGE_MINT_MAX_ATTEMPTS: %q", v)
+		}
+	}
+	return cfg, nil
+}
+
+// Enabled reports whether a Reputation contract is configured.
+func (c BadgeMintConfig) Enabled() bool {
+	return c.Contract != (common.Address{})
+}
+
+// MetadataPinner stores badge metadata and returns its CID.
+type MetadataPinner interface {
+	UploadJSON(data interface{}) (string, error)
+}
+
+// BadgeMinter works through the badge mint queue. Each badge's ERC-721
+// metadata is pinned to IPFS and a mintBadge transaction is signed and
+// recorded before it is broadcast, so a restart never mints a badge twice:
+// submitted jobs are rebroadcast until a receipt shows up, then the token ID
+// and transaction hash are stored on the badge. Failures are retried with
+// exponential backoff until MaxAttempts.
+type BadgeMinter struct {
+	store    repository.Store
+	clock    clock.Clock
+	backend  chain.TxBackend
+	contract *chain.ReputationContract
+	signer   chain.Signer
+	pinner   MetadataPinner
+	badges   *services.ReputationConfig
+	cfg      BadgeMintConfig
+}
+
+func NewBadgeMinter(store repository.Store, clk clock.Clock, backend chain.TxBackend, contract *chain.ReputationContract,
+	signer chain.Signer, pinner MetadataPinner, badges *services.ReputationConfig, cfg BadgeMintConfig) *BadgeMinter {
+	return &BadgeMinter{
+		store:    store,
+		clock:    clk,
+		backend:  backend,
+		contract: contract,
+		signer:   signer,
+		pinner:   pinner,
+		badges:   badges,
+		cfg:      cfg,
+	}
+}
+
+// Run sweeps on every interval until ctx is cancelled.
+func (w *BadgeMinter) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Sweep(ctx); err != nil {
+			log.Printf("Badge mint sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep advances every due job by one step. Jobs locked by another instance
+// are skipped.
+func (w *BadgeMinter) Sweep(ctx context.Context) error {
+	due, err := w.store.Reputations().DueMintJobs(ctx, w.clock.Now(), mintBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to find due badge mints: %w", err)
+	}
+
+	for _, id := range due {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.process(ctx, id); err != nil {
+			log.Printf("Failed to process badge mint job %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+func (w *BadgeMinter) process(ctx context.Context, id uint) error {
+	var broadcast *types.Transaction
+	err := w.store.Transaction(ctx, func(tx repository.Store) error {
+		job, err := tx.Reputations().LockDueMintJob(ctx, id, w.clock.Now())
+		if errors.Is(err, repository.ErrNotFound) {
+			// Handled by another instance or no longer due
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if job.Status == models.MintPending {
+			broadcast, err = w.submit(ctx, tx, job)
+		} else {
+			err = w.confirm(ctx, tx, job)
+		}
+		if err != nil {
+			w.fail(job, err)
+		}
+		return tx.Reputations().SaveMintJob(ctx, job)
+	})
+	if err != nil || broadcast == nil {
+		return err
+	}
+
+	// The signed transaction is committed, so a failed broadcast is retried
+	// from the submitted state
+	if err := w.backend.SendTransaction(ctx, broadcast); err != nil && !isKnownTx(err) {
+		log.Printf("Failed to broadcast badge mint job %d: %v", id, err)
+	}
+	return nil
+}
+
+// submit pins the badge metadata and signs its mint. The transaction is
+// returned for broadcasting after the job is saved.
+func (w *BadgeMinter) submit(ctx context.Context, tx repository.Store, job *models.BadgeMintJob) (*types.Transaction, error) {
+	badge, err := tx.Reputations().GetBadge(ctx, job.BadgeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load badge: %w", err)
+	}
+	to, err := w.recipient(ctx, tx, badge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.MetadataURI == "" {
+		hash, err := w.pinner.UploadJSON(w.metadata(badge))
+		if err != nil {
+			return nil, fmt.Errorf("failed to pin badge metadata: %w", err)
+		}
+		job.MetadataURI = "ipfs://" + hash
+	}
+
+	signed, err := w.contract.SignMint(ctx, w.backend, w.signer, to, job.MetadataURI)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	job.Status = models.MintSubmitted
+	job.TxHash = signed.Hash().Hex()
+	job.RawTx = raw
+	job.NextAttemptAt = w.clock.Now().Add(w.cfg.Interval)
+	return signed, nil
+}
+
+// confirm checks a submitted mint. Without a receipt the transaction is
+// rebroadcast; a reverted mint is signed again.
+func (w *BadgeMinter) confirm(ctx context.Context, tx repository.Store, job *models.BadgeMintJob) error {
+	receipt, err := w.backend.TransactionReceipt(ctx, common.HexToHash(job.TxHash))
+	if errors.Is(err, ethereum.NotFound) {
+		return w.rebroadcast(ctx, job)
+	}
+	if err != nil {
+		// The node may be unavailable; try again without counting an attempt
+		job.LastError = err.Error()
+		job.NextAttemptAt = w.clock.Now().Add(w.cfg.Interval)
+		return nil
+	}
+
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		w.resubmit(job)
+		return errors.New("mint transaction reverted")
+	}
+	tokenID, err := w.contract.MintedTokenID(receipt)
+	if err != nil {
+		return err
+	}
+
+	badge, err := tx.Reputations().GetBadge(ctx, job.BadgeID)
+	if err != nil {
+		return err
+	}
+	token := tokenID.String()
+	badge.TokenID = &token
+	badge.TokenURI = job.MetadataURI
+	badge.TxHash = job.TxHash
+	if err := tx.Reputations().SaveBadge(ctx, badge); err != nil {
+		return err
+	}
+
+	job.Status = models.MintMinted
+	job.RawTx = nil
+	job.LastError = ""
+	return nil
+}
+
+func (w *BadgeMinter) rebroadcast(ctx context.Context, job *models.BadgeMintJob) error {
+	var signed types.Transaction
+	if err := signed.UnmarshalBinary(job.RawTx); err != nil {
+		w.resubmit(job)
+		return fmt.Errorf("invalid signed transaction: %w", err)
+	}
+
+	err := w.backend.SendTransaction(ctx, &signed)
+	switch {
+	case err == nil || isKnownTx(err):
+		job.NextAttemptAt = w.clock.Now().Add(w.cfg.Interval)
+		return nil
+	case strings.Contains(err.Error(), "nonce too low"):
+		return w.checkReplaced(ctx, job, &signed)
+	default:
+		job.LastError = err.Error()
+		job.NextAttemptAt = w.clock.Now().Add(w.cfg.Interval)
+		return nil
+	}
+}
+
+// checkReplaced handles a rebroadcast rejected because its nonce is used.
+// Another transaction may have taken the nonce, but ours may also have been
+// mined since the receipt check, so the mint is only signed again once a
+// mined transaction holds the nonce and ours still has no receipt.
+func (w *BadgeMinter) checkReplaced(ctx context.Context, job *models.BadgeMintJob, signed *types.Transaction) error {
+	job.NextAttemptAt = w.clock.Now().Add(w.cfg.Interval)
+
+	from, err := types.Sender(types.LatestSignerForChainID(signed.ChainId()), signed)
+	if err != nil {
+		w.resubmit(job)
+		return fmt.Errorf("invalid signed transaction: %w", err)
+	}
+	mined, err := w.backend.NonceAt(ctx, from, nil)
+	if err != nil {
+		job.LastError = err.Error()
+		return nil
+	}
+	if mined <= signed.Nonce() {
+		// Only a pending transaction holds the nonce; wait for it to be mined
+		return nil
+	}
+
+	_, err = w.backend.TransactionReceipt(ctx, signed.Hash())
+	switch {
+	case err == nil:
+		// Ours was mined after all and is confirmed on the next sweep
+		return nil
+	case errors.Is(err, ethereum.NotFound):
+		w.resubmit(job)
+		return errors.New("mint transaction was replaced")
+	default:
+		job.LastError = err.Error()
+		return nil
+	}
+}
+
+// resubmit sends a job back to be signed again.
+func (w *BadgeMinter) resubmit(job *models.BadgeMintJob) {
+	job.Status = models.MintPending
+	job.TxHash = ""
+	job.RawTx = nil
+}
+
+// fail records a failed attempt and schedules a retry with exponential
+// backoff. Pending jobs are given up after MaxAttempts; a job with a
+// transaction in flight keeps being checked.
+func (w *BadgeMinter) fail(job *models.BadgeMintJob, err error) {
+	job.Attempts++
+	job.LastError = err.Error()
+	if job.Attempts >= w.cfg.MaxAttempts && job.Status == models.MintPending {
+		job.Status = models.MintFailed
+		return
+	}
+
+	backoff := w.cfg.Interval << (job.Attempts - 1)
+	if backoff > maxMintBackoff || backoff <= 0 {
+		backoff = maxMintBackoff
+	}
+	job.NextAttemptAt = w.clock.Now().Add(backoff)
+}
+
+// recipient returns the wallet a user's badges are minted to.
+func (w *BadgeMinter) recipient(ctx context.Context, tx repository.Store, userID string) (common.Address, error) {
+	user, err := tx.Users().Get(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return common.Address{}, err
+	}
+	if user != nil && common.IsHexAddress(user.Address) {
+		return common.HexToAddress(user.Address), nil
+	}
+	// Wallet users are identified by their address
+	if common.IsHexAddress(userID) {
+		return common.HexToAddress(userID), nil
+	}
+	return common.Address{}, fmt.Errorf("user %s has no wallet address", userID)
+}
+
+// badgeMetadata is the ERC-721 metadata JSON of a badge.
+type badgeMetadata struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Image       string           `json:"image,omitempty"`
+	Attributes  []badgeAttribute `json:"attributes"`
+}
+
+type badgeAttribute struct {
+	TraitType   string      `json:"trait_type"`
+	DisplayType string      `json:"display_type,omitempty"`
+	Value       interface{} `json:"value"`
+}
+
+func (w *BadgeMinter) metadata(badge *models.Badge) badgeMetadata {
+	metadata := badgeMetadata{
+		Name:        badge.Name,
+		Description: badge.Description,
+		Attributes: []badgeAttribute{
+			{TraitType: "Badge", Value: badge.Name},
+			{TraitType: "Awarded", DisplayType: "date", Value: badge.CreatedAt.Unix()},
+		},
+	}
+	if definition, ok := w.badges.Badge(badge.Name); ok && definition.ImageCID != "" {
+		metadata.Image = "ipfs://" + definition.ImageCID
+	}
+	return metadata
+}
+
+// isKnownTx reports whether a broadcast failed only because the node already
+// has the transaction.
+func isKnownTx(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
@@ -0,0 +1,234 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/chain/chaintest"
+	"github.com/bountyBoard/internal/clock"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	reputationAddress = common.HexToAddress("0x000000000000000000000000000000000000e9a7")
+	creatorAddress    = common.HexToAddress(creator)
+)
+
+type fakePinner struct{}
+
+func (fakePinner) UploadJSON(data interface{}) (string, error) {
+	return "bafkreihdwdcefgh4dqkjv67uzcmw7ojee6xedzdetojuzjevtenxquvyku", nil
+}
+
+// laggingBackend reports the next hidden receipts as not found, like a node
+// that has not caught up with the block the transaction was mined in.
+type laggingBackend struct {
+	*chaintest.Chain
+	hidden int
+}
+
+func (b *laggingBackend) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	if b.hidden > 0 {
+		b.hidden--
+		return nil, ethereum.NotFound
+	}
+	return b.Chain.TransactionReceipt(ctx, hash)
+}
+
+type mintTest struct {
+	*chaintest.Chain
+	backend *laggingBackend
+	store   *repository.MemoryStore
+	now     time.Time
+	minter  *BadgeMinter
+	jobID   uint
+	badgeID uint
+}
+
+func newMintTest(t *testing.T) *mintTest {
+	t.Helper()
+
+	sim := chaintest.New(t, map[common.Address][]byte{
+		reputationAddress: chaintest.MintCode(chaintest.EventID("BadgeMinted(address,uint256,string)")),
+	})
+	contract, err := chain.NewReputationContract(reputationAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mt := &mintTest{
+		Chain:   sim,
+		backend: &laggingBackend{Chain: sim},
+		store:   repository.NewMemoryStore(),
+		now:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	cfg := BadgeMintConfig{Contract: reputationAddress, Interval: time.Minute, MaxAttempts: 3}
+	mt.minter = NewBadgeMinter(mt.store, clock.Func(func() time.Time { return mt.now }), mt.backend, contract,
+		chain.NewKeySigner(sim.Key, chaintest.ChainID), fakePinner{}, &services.ReputationConfig{}, cfg)
+
+	ctx := context.Background()
+	if err := mt.store.Users().Create(ctx, &models.User{ID: hunter, Address: hunter}); err != nil {
+		t.Fatal(err)
+	}
+	badge := models.Badge{UserID: hunter, Name: "First Bounty", Description: "Completed a bounty"}
+	if err := mt.store.Reputations().CreateBadge(ctx, &badge); err != nil {
+		t.Fatal(err)
+	}
+	job := models.BadgeMintJob{BadgeID: badge.ID, Status: models.MintPending, NextAttemptAt: mt.now}
+	if err := mt.store.Reputations().CreateMintJob(ctx, &job); err != nil {
+		t.Fatal(err)
+	}
+	mt.badgeID, mt.jobID = badge.ID, job.ID
+	return mt
+}
+
+// sweep runs the minter once the next attempt is due.
+func (mt *mintTest) sweep(t *testing.T) *models.BadgeMintJob {
+	t.Helper()
+	mt.now = mt.now.Add(time.Hour)
+	if err := mt.minter.Sweep(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return mt.job(t)
+}
+
+func (mt *mintTest) job(t *testing.T) *models.BadgeMintJob {
+	t.Helper()
+	job, err := mt.store.Reputations().GetMintJob(context.Background(), mt.jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func (mt *mintTest) badge(t *testing.T) *models.Badge {
+	t.Helper()
+	badge, err := mt.store.Reputations().GetBadge(context.Background(), mt.badgeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return badge
+}
+
+func (mt *mintTest) submitted(t *testing.T) *models.BadgeMintJob {
+	t.Helper()
+	job := mt.sweep(t)
+	if job.Status != models.MintSubmitted || job.TxHash == "" || len(job.RawTx) == 0 {
+		t.Fatalf("job after submit = %+v", job)
+	}
+	return job
+}
+
+func (mt *mintTest) expectMinted(t *testing.T, txHash, tokenID string) {
+	t.Helper()
+	job := mt.sweep(t)
+	if job.Status != models.MintMinted || job.TxHash != txHash {
+		t.Fatalf("job = %+v, want minted by %s", job, txHash)
+	}
+	badge := mt.badge(t)
+	if badge.TokenID == nil || *badge.TokenID != tokenID || badge.TxHash != txHash || badge.TokenURI != job.MetadataURI {
+		t.Fatalf("badge = %+v", badge)
+	}
+}
+
+// waitIndexed waits until the node gives a definite answer for the
+// transaction's receipt. Until its index has caught up it answers receipt
+// queries for transactions it does not know with an error rather than not
+// found.
+func (mt *mintTest) waitIndexed(t *testing.T, hash common.Hash) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := mt.TransactionReceipt(context.Background(), hash)
+		if err == nil || errors.Is(err, ethereum.NotFound) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("transaction %s not indexed: %v", hash, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestBadgeMinterMintsBadge(t *testing.T) {
+	mt := newMintTest(t)
+
+	job := mt.submitted(t)
+	mt.Commit()
+	mt.expectMinted(t, job.TxHash, "1")
+}
+
+func TestBadgeMinterResignsRevertedMint(t *testing.T) {
+	mt := newMintTest(t)
+
+	// Make the mint revert once it is mined after the call breaking the
+	// contract, although it was signed while mints still succeeded
+	mt.Send(t, reputationAddress, nil)
+	reverted := mt.submitted(t)
+	mt.Commit()
+
+	job := mt.sweep(t)
+	if job.Status != models.MintPending || job.Attempts != 1 || job.TxHash != "" {
+		t.Fatalf("job after revert = %+v, want pending for a new signature", job)
+	}
+
+	mt.Send(t, reputationAddress, nil)
+	mt.Commit()
+	resigned := mt.submitted(t)
+	if resigned.TxHash == reverted.TxHash {
+		t.Fatal("reverted mint was not signed again")
+	}
+	mt.Commit()
+	mt.expectMinted(t, resigned.TxHash, "1")
+}
+
+func TestBadgeMinterResignsReplacedMint(t *testing.T) {
+	mt := newMintTest(t)
+
+	job := mt.submitted(t)
+	var orphan types.Transaction
+	if err := orphan.UnmarshalBinary(job.RawTx); err != nil {
+		t.Fatal(err)
+	}
+	// Another mint takes the nonce, and token 1 with it
+	replacement := mt.Replace(t, &orphan, reputationAddress, append(orphan.Data()[:4:4], chaintest.AddressTopic(creatorAddress).Bytes()...))
+	mt.Commit()
+	mt.waitIndexed(t, replacement.Hash())
+	mt.waitIndexed(t, orphan.Hash())
+
+	job = mt.sweep(t)
+	if job.Status != models.MintPending || job.LastError != "mint transaction was replaced" {
+		t.Fatalf("job after replacement = %+v", job)
+	}
+
+	resigned := mt.submitted(t)
+	mt.Commit()
+	mt.expectMinted(t, resigned.TxHash, "2")
+}
+
+func TestBadgeMinterKeepsMintMinedDuringRebroadcast(t *testing.T) {
+	mt := newMintTest(t)
+
+	job := mt.submitted(t)
+	mt.Commit()
+	// Once the node has caught up, its pool no longer knows the mint either
+	mt.waitIndexed(t, common.HexToHash(job.TxHash))
+
+	// The receipt is missed, so the mint is rebroadcast and rejected for its
+	// nonce, but it is the mint itself that holds the nonce
+	mt.backend.hidden = 1
+	rebroadcast := mt.sweep(t)
+	if rebroadcast.Status != models.MintSubmitted || rebroadcast.TxHash != job.TxHash || rebroadcast.Attempts != 0 {
+		t.Fatalf("job after rebroadcast = %+v, want still submitted as %s", rebroadcast, job.TxHash)
+	}
+
+	mt.expectMinted(t, job.TxHash, "1")
+}
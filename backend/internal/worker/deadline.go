@@ -0,0 +1,162 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/bountyBoard/internal/clock"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+)
+
+type DeadlineConfig struct {
+	Interval    time.Duration // how often to sweep for past-deadline bounties
+	GracePeriod time.Duration // extra time a hunter gets after the deadline
+}
+
+// DeadlineConfigFromEnv reads DEADLINE_SWEEP_INTERVAL and
+// DEADLINE_GRACE_PERIOD, falling back to defaults.
+func DeadlineConfigFromEnv() (DeadlineConfig, error) {
+	cfg := DeadlineConfig{
+		Interval:    time.Minute,
+		GracePeriod: 24 * time.Hour,
+	}
+
+	var err error
+	if v := os.Getenv("DEADLINE_SWEEP_INTERVAL"); v != "" {
+		if cfg.Interval, err = time.ParseDuration(v); err != nil || cfg.Interval <= 0 {
+			return cfg, fmt.Errorf("invalid DEADLINE_SWEEP_INTERVAL: %q", v)
+		}
+	}
+	if v := os.Getenv("DEADLINE_GRACE_PERIOD"); v != "" {
+		if cfg.GracePeriod, err = time.ParseDuration(v); err != nil || cfg.GracePeriod < 0 {
+			return cfg, fmt.Errorf("invalid DEADLINE_GRACE_PERIOD: %q", v)
+		}
+	}
+	return cfg, nil
+}
+
+// DeadlineWorker enforces bounty deadlines. Open bounties past their deadline
+// are expired. A claimed bounty whose hunter has not submitted anything by
+// the deadline plus the grace period has its claim released and goes
+// straight to expired rather than back to open: its deadline has passed, so
+// nobody could finish it in time. Creators and hunters are notified of each
+// change.
+type DeadlineWorker struct {
+	store repository.Store
+	clock clock.Clock
+	cfg   DeadlineConfig
+}
+
+func NewDeadlineWorker(store repository.Store, clk clock.Clock, cfg DeadlineConfig) *DeadlineWorker {
+	return &DeadlineWorker{
+		store: store,
+		clock: clk,
+		cfg:   cfg,
+	}
+}
+
+// Run sweeps on every interval until ctx is cancelled.
+func (w *DeadlineWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Sweep(ctx); err != nil {
+			log.Printf("Deadline sweep failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep applies deadline rules once.
+func (w *DeadlineWorker) Sweep(ctx context.Context) error {
+	now := w.clock.Now()
+
+	stale, err := w.store.Bounties().ListStaleClaims(ctx, now.Add(-w.cfg.GracePeriod))
+	if err != nil {
+		return fmt.Errorf("failed to find stale claims: %w", err)
+	}
+	for _, id := range stale {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.unclaim(ctx, id, now); err != nil {
+			log.Printf("Failed to unclaim bounty %d: %v", id, err)
+		}
+	}
+
+	expired, err := w.store.Bounties().ListPastDeadline(ctx, domain.StatusOpen, now)
+	if err != nil {
+		return fmt.Errorf("failed to find expired bounties: %w", err)
+	}
+	for _, id := range expired {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err := w.expire(ctx, id, now); err != nil {
+			log.Printf("Failed to expire bounty %d: %v", id, err)
+		}
+	}
+
+	return nil
+}
+
+// unclaim releases a stale claim and expires the bounty in one transaction,
+// so the bounty is never seen open past its deadline.
+func (w *DeadlineWorker) unclaim(ctx context.Context, id uint, now time.Time) error {
+	_, err := services.WithLockedBounty(ctx, w.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if bounty.Status != domain.StatusClaimed || bounty.HunterID == nil ||
+			!bounty.Deadline.Add(w.cfg.GracePeriod).Before(now) {
+			return nil
+		}
+		// Re-check under the lock; the hunter may have submitted meanwhile.
+		// Submissions by earlier hunters of a reopened bounty do not count.
+		hunterID := *bounty.HunterID
+		submissions, err := tx.Submissions().CountByHunter(ctx, bounty.ID, hunterID)
+		if err != nil || submissions > 0 {
+			return err
+		}
+
+		bounty.HunterID = nil
+		if err := tx.Bounties().Transition(ctx, bounty, domain.ActionUnclaim, services.ActorScheduler); err != nil {
+			return err
+		}
+		if err := tx.Bounties().Transition(ctx, bounty, domain.ActionExpire, services.ActorScheduler); err != nil {
+			return err
+		}
+
+		if err := services.Notify(ctx, tx, hunterID, services.NotificationBountyUnclaimed, &bounty.ID,
+			fmt.Sprintf("Your claim on %q was released because no work was submitted by the deadline.", bounty.Title)); err != nil {
+			return err
+		}
+		return services.Notify(ctx, tx, bounty.CreatorID, services.NotificationBountyExpired, &bounty.ID,
+			fmt.Sprintf("The hunter on %q did not submit by the deadline, so the bounty has expired.", bounty.Title))
+	})
+	return err
+}
+
+func (w *DeadlineWorker) expire(ctx context.Context, id uint, now time.Time) error {
+	_, err := services.WithLockedBounty(ctx, w.store, id, func(tx repository.Store, bounty *models.Bounty) error {
+		if bounty.Status != domain.StatusOpen || !bounty.Deadline.Before(now) {
+			return nil
+		}
+
+		if err := tx.Bounties().Transition(ctx, bounty, domain.ActionExpire, services.ActorScheduler); err != nil {
+			return err
+		}
+		return services.Notify(ctx, tx, bounty.CreatorID, services.NotificationBountyExpired, &bounty.ID,
+			fmt.Sprintf("%q passed its deadline without being claimed and has expired.", bounty.Title))
+	})
+	return err
+}
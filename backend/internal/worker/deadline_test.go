@@ -0,0 +1,247 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bountyBoard/internal/clock"
+	"github.com/bountyBoard/internal/database/dbtest"
+	"github.com/bountyBoard/internal/domain"
+	"github.com/bountyBoard/internal/models"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+)
+
+const (
+	creator     = "0x00000000000000000000000000000000000000c0"
+	hunter      = "0x00000000000000000000000000000000000000b0"
+	earlyHunter = "0x00000000000000000000000000000000000000b1"
+)
+
+var deadline = time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+
+type deadlineTest struct {
+	store   repository.Store
+	now     time.Time
+	worker  *DeadlineWorker
+	created uint
+}
+
+// testDeadlines runs a deadline test against the in-memory store and against
+// the GORM store, whose queries for due bounties are SQL.
+func testDeadlines(t *testing.T, test func(t *testing.T, dt *deadlineTest)) {
+	stores := map[string]func(t *testing.T) repository.Store{
+		"memory": func(t *testing.T) repository.Store { return repository.NewMemoryStore() },
+		"gorm":   func(t *testing.T) repository.Store { return repository.NewGormStore(dbtest.Open(t)) },
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			test(t, newDeadlineTest(t, open(t)))
+		})
+	}
+}
+
+func newDeadlineTest(t *testing.T, store repository.Store) *deadlineTest {
+	t.Helper()
+
+	dt := &deadlineTest{store: store, now: deadline.Add(-time.Hour)}
+	dt.worker = NewDeadlineWorker(store, clock.Func(func() time.Time { return dt.now }), DeadlineConfig{
+		Interval:    time.Minute,
+		GracePeriod: 24 * time.Hour,
+	})
+	for _, id := range []string{creator, hunter, earlyHunter} {
+		if err := store.Users().Create(context.Background(), &models.User{ID: id, Address: id}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dt
+}
+
+func (dt *deadlineTest) bounty(t *testing.T, status domain.BountyStatus, hunterID *string) uint {
+	t.Helper()
+	dt.created++
+	bounty := models.Bounty{
+		BlockchainID: dt.created,
+		Title:        "Write the docs",
+		CreatorID:    creator,
+		HunterID:     hunterID,
+		Status:       status,
+		Deadline:     deadline,
+	}
+	if err := dt.store.Bounties().Create(context.Background(), &bounty); err != nil {
+		t.Fatal(err)
+	}
+	return bounty.ID
+}
+
+func (dt *deadlineTest) submit(t *testing.T, bountyID uint, hunterID string) {
+	t.Helper()
+	err := dt.store.Submissions().Create(context.Background(), &models.BountySubmission{
+		BountyID: bountyID,
+		HunterID: hunterID,
+		Content:  "Done",
+		Status:   domain.SubmissionPending,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (dt *deadlineTest) sweep(t *testing.T, at time.Time) {
+	t.Helper()
+	dt.now = at
+	if err := dt.worker.Sweep(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func (dt *deadlineTest) get(t *testing.T, id uint) *models.Bounty {
+	t.Helper()
+	bounty, err := dt.store.Bounties().Get(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bounty
+}
+
+func (dt *deadlineTest) status(t *testing.T, id uint) domain.BountyStatus {
+	t.Helper()
+	return dt.get(t, id).Status
+}
+
+// notifications returns the kinds of a user's notifications, oldest first.
+func (dt *deadlineTest) notifications(t *testing.T, userID string) []string {
+	t.Helper()
+	rows, err := dt.store.Notifications().List(context.Background(), userID, false, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kinds := make([]string, len(rows))
+	for i, row := range rows {
+		kinds[len(rows)-1-i] = row.Type
+	}
+	return kinds
+}
+
+func (dt *deadlineTest) history(t *testing.T, id uint) []domain.BountyStatus {
+	t.Helper()
+	rows, err := dt.store.Bounties().History(context.Background(), id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make([]domain.BountyStatus, len(rows))
+	for i, row := range rows {
+		statuses[i] = row.ToStatus
+	}
+	return statuses
+}
+
+func equal[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSweepExpiresOpenBounties(t *testing.T) {
+	testDeadlines(t, func(t *testing.T, dt *deadlineTest) {
+		id := dt.bounty(t, domain.StatusOpen, nil)
+
+		dt.sweep(t, deadline.Add(-time.Minute))
+		if got := dt.status(t, id); got != domain.StatusOpen {
+			t.Fatalf("status before deadline = %s, want open", got)
+		}
+
+		dt.sweep(t, deadline.Add(time.Minute))
+		if got := dt.status(t, id); got != domain.StatusExpired {
+			t.Fatalf("status after deadline = %s, want expired", got)
+		}
+		if got := dt.notifications(t, creator); !equal(got, []string{services.NotificationBountyExpired}) {
+			t.Fatalf("creator notifications = %v", got)
+		}
+	})
+}
+
+func TestSweepKeepsClaimsWithinGracePeriod(t *testing.T) {
+	testDeadlines(t, func(t *testing.T, dt *deadlineTest) {
+		h := hunter
+		id := dt.bounty(t, domain.StatusClaimed, &h)
+
+		dt.sweep(t, deadline.Add(23*time.Hour))
+		if got := dt.status(t, id); got != domain.StatusClaimed {
+			t.Fatalf("status within grace period = %s, want claimed", got)
+		}
+		if got := dt.notifications(t, hunter); len(got) != 0 {
+			t.Fatalf("hunter notified within grace period: %v", got)
+		}
+	})
+}
+
+func TestSweepKeepsSubmittedClaims(t *testing.T) {
+	testDeadlines(t, func(t *testing.T, dt *deadlineTest) {
+		h := hunter
+		id := dt.bounty(t, domain.StatusClaimed, &h)
+		dt.submit(t, id, hunter)
+
+		dt.sweep(t, deadline.Add(48*time.Hour))
+		if got := dt.status(t, id); got != domain.StatusClaimed {
+			t.Fatalf("status of submitted claim = %s, want claimed", got)
+		}
+	})
+}
+
+func TestSweepExpiresUnsubmittedClaims(t *testing.T) {
+	testDeadlines(t, func(t *testing.T, dt *deadlineTest) {
+		h := hunter
+		id := dt.bounty(t, domain.StatusClaimed, &h)
+
+		dt.sweep(t, deadline.Add(25*time.Hour))
+
+		bounty := dt.get(t, id)
+		if bounty.Status != domain.StatusExpired || bounty.HunterID != nil {
+			t.Fatalf("bounty = %+v, want expired without hunter", bounty)
+		}
+		want := []domain.BountyStatus{domain.StatusOpen, domain.StatusExpired}
+		if got := dt.history(t, id); !equal(got, want) {
+			t.Fatalf("history = %v, want %v", got, want)
+		}
+
+		// One notice each; the creator only hears that the bounty expired
+		if got := dt.notifications(t, hunter); !equal(got, []string{services.NotificationBountyUnclaimed}) {
+			t.Fatalf("hunter notifications = %v", got)
+		}
+		if got := dt.notifications(t, creator); !equal(got, []string{services.NotificationBountyExpired}) {
+			t.Fatalf("creator notifications = %v", got)
+		}
+
+		// Later sweeps leave it alone
+		dt.sweep(t, deadline.Add(72*time.Hour))
+		if got := dt.notifications(t, creator); len(got) != 1 {
+			t.Fatalf("creator notifications after second sweep = %v", got)
+		}
+	})
+}
+
+func TestSweepIgnoresEarlierHuntersSubmissions(t *testing.T) {
+	testDeadlines(t, func(t *testing.T, dt *deadlineTest) {
+		// The bounty was reopened after the first hunter's work was rejected and
+		// claimed again by someone who never submitted
+		h := hunter
+		id := dt.bounty(t, domain.StatusClaimed, &h)
+		dt.submit(t, id, earlyHunter)
+
+		dt.sweep(t, deadline.Add(25*time.Hour))
+		if got := dt.status(t, id); got != domain.StatusExpired {
+			t.Fatalf("status = %s, want expired", got)
+		}
+		if got := dt.notifications(t, earlyHunter); len(got) != 0 {
+			t.Fatalf("earlier hunter notified: %v", got)
+		}
+	})
+}
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
+	"github.com/bountyBoard/internal/auth"
+	"github.com/bountyBoard/internal/chain"
+	"github.com/bountyBoard/internal/clock"
+	"github.com/bountyBoard/internal/indexer"
 	"github.com/bountyBoard/internal/middleware"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/storage"
+	"github.com/bountyBoard/internal/worker"
 	"log"
 	"os"
 
 	v1 "github.com/bountyBoard/api/v1"
 	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/services"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 )
@@ -20,6 +29,88 @@ func main() {
 	// Initialize database
 	database.InitDB()
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
+	// Refuse to serve against an outdated schema
+	if err := database.CheckSchema(database.DB); err != nil {
+		log.Fatal(err)
+	}
+
+	// Validate the level curve and badge definitions before anything uses them
+	reputationConfig, err := services.ReputationConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	services.SetReputationConfig(reputationConfig)
+
+	if len(os.Args) > 1 && os.Args[1] == "reputation" {
+		runReputation(os.Args[2:])
+		return
+	}
+
+	// Load access token signing key
+	auth.InitTokens()
+
+	// Load the domain and chain wallets must sign in for
+	auth.InitSIWE()
+
+	// Load the points awarded for bounty outcomes
+	reputationRules, err := services.ReputationRulesFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	services.SetReputationRules(reputationRules)
+
+	store := repository.NewGormStore(database.DB)
+
+	// Grant roles configured in the environment
+	services.NewRoleService(store).BootstrapFromEnv(context.Background())
+
+	// Content-addressed storage for metadata and attachments
+	content, err := storage.FromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentLimits, err := services.AttachmentLimitsFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	services.SetAttachmentLimits(attachmentLimits)
+
+	// Connect to the chain and start following BountyBoard events
+	chain.InitChain()
+	var verifier *services.BountyVerifier
+	if chain.Enabled() {
+		cfg, err := indexer.ConfigFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		go indexer.New(store, chain.Client, chain.Board, services.NewBountyMetadataService(content), cfg).Run(context.Background())
+
+		// Bounties created through the API are trusted as deep as indexed ones
+		verifier = services.NewBountyVerifier(store, chain.Client, chain.Board, cfg.Confirmations)
+		go verifier.Run(context.Background())
+
+		// Mint awarded badges as Reputation NFTs
+		mintCfg, err := worker.BadgeMintConfigFromEnv()
+		if err != nil {
+			log.Fatal(err)
+		}
+		if mintCfg.Enabled() {
+			go newBadgeMinter(store, content, reputationConfig, mintCfg).Run(context.Background())
+		}
+	}
+
+	// Expire bounties and release stale claims once deadlines pass
+	deadlineCfg, err := worker.DeadlineConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	go worker.NewDeadlineWorker(store, clock.Real{}, deadlineCfg).Run(context.Background())
+
 	// Set up Gin
 	r := gin.Default()
 
@@ -46,14 +137,18 @@ func main() {
 	})
 
 	// Register API routes
-	v1.RegisterBountyRoutes(r)
-	v1.RegisterReputationRoutes(r)
+	server := v1.NewServer(store, content, verifier)
+	server.RegisterAuthRoutes(r)
+	server.RegisterBountyRoutes(r)
+	server.RegisterReputationRoutes(r)
+	server.RegisterAdminRoutes(r)
 
 	// Apply Lens authentication middleware to protected routes
 	protected := r.Group("/api/v1")
-	// protected.Use(middleware.LensAuth())
-	protected.Use(middleware.WalletAuth())
-	v1.RegisterUserRoutes(protected)
+	// protected.Use(middleware.LensAuth(store))
+	protected.Use(middleware.WalletAuth(store))
+	server.RegisterUserRoutes(protected)
+	server.RegisterNotificationRoutes(protected)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -62,3 +157,20 @@ func main() {
 	}
 	r.Run(":" + port)
 }
+
+func newBadgeMinter(store repository.Store, content storage.ContentStore, reputationConfig *services.ReputationConfig, cfg worker.BadgeMintConfig) *worker.BadgeMinter {
+	chainID, err := chain.TxClient.ChainID(context.Background())
+	if err != nil {
+		log.Fatal("Failed to read chain ID: ", err)
+	}
+	signer, err := chain.ParseKeySigner(cfg.MinterKey, chainID)
+	if err != nil {
+		log.Fatal("Invalid MINTER_PRIVATE_KEY: ", err)
+	}
+	contract, err := chain.NewReputationContract(cfg.Contract)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return worker.NewBadgeMinter(store, clock.Real{}, chain.TxClient, contract, signer,
+		services.NewIPFSService(content), reputationConfig, cfg)
+}
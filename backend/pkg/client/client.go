@@ -0,0 +1,237 @@
+// Package client is a Go client for the parts of the bountyBoard API that
+// need work on the client side, such as encrypting private submissions and
+// decrypting them again. The server never sees the plaintext or the keys.
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdh"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/bountyBoard/pkg/sealed"
+)
+
+// maxCiphertextSize caps how much of a ciphertext download is read.
+const maxCiphertextSize = 1 << 30
+
+// Client calls the API as one user.
+type Client struct {
+	// BaseURL is the API root, e.g. https://bounties.example.com/api/v1.
+	BaseURL string
+	// Token is a session access token from the login endpoints.
+	Token string
+	HTTP  *http.Client
+}
+
+func New(baseURL, token string) *Client {
+	return &Client{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		HTTP:    http.DefaultClient,
+	}
+}
+
+// APIError is an error response from the API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api: %d %s", e.StatusCode, e.Message)
+}
+
+// Submission is the part of a submission returned by SubmitPrivate.
+type Submission struct {
+	ID            uint64 `json:"id"`
+	BountyID      uint64 `json:"bounty_id"`
+	HunterID      string `json:"hunter_id"`
+	Encrypted     bool   `json:"encrypted"`
+	CiphertextCID string `json:"ciphertext_cid"`
+	Status        string `json:"status"`
+}
+
+type sealedSubmission struct {
+	SubmissionID  uint64          `json:"submission_id"`
+	BountyID      uint64          `json:"bounty_id"`
+	CiphertextCID string          `json:"ciphertext_cid"`
+	Envelope      sealed.Envelope `json:"envelope"`
+}
+
+// SetEncryptionKey registers the public key private submissions to userID
+// are encrypted to. userID must be the caller.
+func (c *Client) SetEncryptionKey(ctx context.Context, userID string, key *ecdh.PublicKey) error {
+	body, err := json.Marshal(map[string]string{"public_key": sealed.EncodePublicKey(key)})
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, "/users/"+userID+"/encryption-key", "application/json", bytes.NewReader(body), nil)
+}
+
+// EncryptionKey returns the public key registered by userID.
+func (c *Client) EncryptionKey(ctx context.Context, userID string) (*ecdh.PublicKey, error) {
+	var resp struct {
+		PublicKey string `json:"public_key"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/users/"+userID+"/encryption-key", "", nil, &resp); err != nil {
+		return nil, err
+	}
+	return sealed.ParsePublicKey(resp.PublicKey)
+}
+
+// SubmitPrivate encrypts plaintext to the bounty creator's registered key and
+// submits it as the caller. If hunterID is set the submission is also
+// encrypted to the hunter's key, so they can read it back.
+func (c *Client) SubmitPrivate(ctx context.Context, bountyID uint64, creatorID, hunterID string, plaintext []byte) (*Submission, error) {
+	recipients := []sealed.Recipient{{ID: creatorID}}
+	if hunterID != "" {
+		recipients = append(recipients, sealed.Recipient{ID: hunterID})
+	}
+	for i := range recipients {
+		key, err := c.EncryptionKey(ctx, recipients[i].ID)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key of %s: %w", recipients[i].ID, err)
+		}
+		recipients[i].PublicKey = key
+	}
+
+	ciphertext, envelopes, err := sealed.Seal(bountyID, plaintext, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	envelopeJSON, err := json.Marshal(envelopes)
+	if err != nil {
+		return nil, err
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	if err := writer.WriteField("envelopes", string(envelopeJSON)); err != nil {
+		return nil, err
+	}
+	part, err := writer.CreateFormFile("ciphertext", "submission.sealed")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := part.Write(ciphertext); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	var submission Submission
+	path := fmt.Sprintf("/bounties/%d/submit", bountyID)
+	if err := c.do(ctx, http.MethodPost, path, writer.FormDataContentType(), &form, &submission); err != nil {
+		return nil, err
+	}
+	return &submission, nil
+}
+
+// PrivateSubmission downloads an encrypted submission shared with the caller
+// and decrypts it with their private key.
+func (c *Client) PrivateSubmission(ctx context.Context, bountyID, submissionID uint64, key *ecdh.PrivateKey) ([]byte, error) {
+	info, err := c.sealedSubmission(ctx, bountyID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ciphertext []byte
+	path := fmt.Sprintf("/bounties/%d/submissions/%d/sealed/ciphertext", bountyID, submissionID)
+	if err := c.do(ctx, http.MethodGet, path, "", nil, &ciphertext); err != nil {
+		return nil, err
+	}
+	return sealed.Open(bountyID, ciphertext, info.Envelope, key)
+}
+
+// Reshare gives arbiterID access to an encrypted submission during a
+// dispute, by wrapping the caller's copy of the content key for the
+// arbiter's registered key.
+func (c *Client) Reshare(ctx context.Context, bountyID, submissionID uint64, key *ecdh.PrivateKey, arbiterID string) error {
+	info, err := c.sealedSubmission(ctx, bountyID, submissionID)
+	if err != nil {
+		return err
+	}
+	arbiterKey, err := c.EncryptionKey(ctx, arbiterID)
+	if err != nil {
+		return fmt.Errorf("encryption key of %s: %w", arbiterID, err)
+	}
+	envelope, err := sealed.Reshare(info.Envelope, key, sealed.Recipient{ID: arbiterID, PublicKey: arbiterKey})
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("/bounties/%d/submissions/%d/envelopes", bountyID, submissionID)
+	return c.do(ctx, http.MethodPost, path, "application/json", bytes.NewReader(body), nil)
+}
+
+func (c *Client) sealedSubmission(ctx context.Context, bountyID, submissionID uint64) (*sealedSubmission, error) {
+	var info sealedSubmission
+	path := fmt.Sprintf("/bounties/%d/submissions/%d/sealed", bountyID, submissionID)
+	if err := c.do(ctx, http.MethodGet, path, "", nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// do sends a request and decodes the response into out: raw bytes if out is
+// a *[]byte, JSON otherwise. A nil out discards the response.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Error == "" {
+			apiErr.Error = strings.TrimSpace(string(data))
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: apiErr.Error}
+	}
+
+	switch out := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		data, err := io.ReadAll(io.LimitReader(resp.Body, maxCiphertextSize+1))
+		if err != nil {
+			return err
+		}
+		if len(data) > maxCiphertextSize {
+			return fmt.Errorf("response exceeds %d bytes", maxCiphertextSize)
+		}
+		*out = data
+		return nil
+	default:
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+}
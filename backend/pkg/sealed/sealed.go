@@ -0,0 +1,253 @@
+// Package sealed encrypts private bounty submissions end to end. The
+// submission is encrypted once with a random content key using
+// XChaCha20-Poly1305, and the content key is wrapped for each recipient with
+// an X25519 key agreement, HKDF-SHA256 and ChaCha20-Poly1305. The backend only
+// ever sees the ciphertext and the wrapped keys ("envelopes").
+package sealed
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Version is the first byte of every ciphertext.
+const Version = 1
+
+const (
+	KeySize = 32 // X25519 public and private keys, and content keys
+	// WrappedKeySize is a content key plus its authentication tag.
+	WrappedKeySize = KeySize + chacha20poly1305.Overhead
+	// Overhead is the ciphertext size minus the plaintext size.
+	Overhead = 1 + chacha20poly1305.NonceSizeX + chacha20poly1305.Overhead
+)
+
+var (
+	ErrInvalidKey        = errors.New("invalid X25519 key")
+	ErrInvalidEnvelope   = errors.New("invalid key envelope")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	// ErrDecrypt means the ciphertext or envelope was not made for this key
+	// and bounty, or was tampered with.
+	ErrDecrypt = errors.New("decryption failed")
+)
+
+const (
+	contentInfo = "bountyboard/submission/v1"
+	wrapInfo    = "bountyboard/submission/v1/key-wrap"
+)
+
+// Envelope carries the content key of a submission wrapped for one
+// recipient. Keys are base64 in JSON.
+type Envelope struct {
+	RecipientID  string `json:"recipient_id"`
+	RecipientKey []byte `json:"recipient_key"` // X25519 public key the content key is wrapped for
+	EphemeralKey []byte `json:"ephemeral_key"` // sender's one-time X25519 public key
+	WrappedKey   []byte `json:"wrapped_key"`
+}
+
+// Validate checks the sizes of an envelope's keys.
+func (e *Envelope) Validate() error {
+	if e.RecipientID == "" {
+		return fmt.Errorf("%w: recipient_id is required", ErrInvalidEnvelope)
+	}
+	if len(e.RecipientKey) != KeySize || len(e.EphemeralKey) != KeySize {
+		return fmt.Errorf("%w: keys must be %d bytes", ErrInvalidEnvelope, KeySize)
+	}
+	if len(e.WrappedKey) != WrappedKeySize {
+		return fmt.Errorf("%w: wrapped_key must be %d bytes", ErrInvalidEnvelope, WrappedKeySize)
+	}
+	return nil
+}
+
+// Recipient is someone a submission is encrypted to.
+type Recipient struct {
+	ID        string
+	PublicKey *ecdh.PublicKey
+}
+
+// GenerateKey creates an X25519 key pair for receiving submissions.
+func GenerateKey() (*ecdh.PrivateKey, error) {
+	return ecdh.X25519().GenerateKey(rand.Reader)
+}
+
+// ParsePublicKey decodes a base64 X25519 public key.
+func ParsePublicKey(s string) (*ecdh.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(s)
+	if err != nil || len(raw) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	key, err := ecdh.X25519().NewPublicKey(raw)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+// EncodePublicKey encodes a public key as base64, the form the API uses.
+func EncodePublicKey(key *ecdh.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key.Bytes())
+}
+
+// Seal encrypts plaintext for a submission to bountyID and wraps the content
+// key for each recipient. The bounty ID is authenticated, so the ciphertext
+// cannot be replayed as a submission to another bounty.
+func Seal(bountyID uint64, plaintext []byte, recipients ...Recipient) ([]byte, []Envelope, error) {
+	if len(recipients) == 0 {
+		return nil, nil, errors.New("at least one recipient is required")
+	}
+
+	contentKey := make([]byte, KeySize)
+	if _, err := rand.Read(contentKey); err != nil {
+		return nil, nil, err
+	}
+	aead, err := chacha20poly1305.NewX(contentKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ciphertext := make([]byte, 1+aead.NonceSize(), Overhead+len(plaintext))
+	ciphertext[0] = Version
+	nonce := ciphertext[1:]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, nil, err
+	}
+	ciphertext = aead.Seal(ciphertext, nonce, plaintext, contentAAD(bountyID))
+
+	envelopes := make([]Envelope, len(recipients))
+	for i, recipient := range recipients {
+		if envelopes[i], err = wrap(contentKey, recipient); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ciphertext, envelopes, nil
+}
+
+// Open decrypts a submission to bountyID with the recipient's private key
+// and their envelope.
+func Open(bountyID uint64, ciphertext []byte, envelope Envelope, key *ecdh.PrivateKey) ([]byte, error) {
+	if err := ValidateCiphertext(ciphertext); err != nil {
+		return nil, err
+	}
+	contentKey, err := unwrap(envelope, key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := ciphertext[1 : 1+aead.NonceSize()]
+	plaintext, err := aead.Open(nil, nonce, ciphertext[1+aead.NonceSize():], contentAAD(bountyID))
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// Reshare wraps the content key in envelope, which belongs to key, for
+// another recipient, e.g. the arbiter of a dispute.
+func Reshare(envelope Envelope, key *ecdh.PrivateKey, to Recipient) (Envelope, error) {
+	contentKey, err := unwrap(envelope, key)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return wrap(contentKey, to)
+}
+
+// ValidateCiphertext checks the version and minimum size of a ciphertext. It
+// cannot check that the ciphertext decrypts.
+func ValidateCiphertext(ciphertext []byte) error {
+	if len(ciphertext) < Overhead {
+		return fmt.Errorf("%w: too short", ErrInvalidCiphertext)
+	}
+	if ciphertext[0] != Version {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidCiphertext, ciphertext[0])
+	}
+	return nil
+}
+
+// ReadHeader reads and validates the version byte of a streamed ciphertext
+// and returns a reader over the whole ciphertext.
+func ReadHeader(r io.Reader) (io.Reader, error) {
+	var header [1]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	if header[0] != Version {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCiphertext, header[0])
+	}
+	return io.MultiReader(bytes.NewReader(header[:]), r), nil
+}
+
+func contentAAD(bountyID uint64) []byte {
+	return binary.BigEndian.AppendUint64([]byte(contentInfo), bountyID)
+}
+
+func wrap(contentKey []byte, to Recipient) (Envelope, error) {
+	if to.PublicKey == nil || to.PublicKey.Curve() != ecdh.X25519() {
+		return Envelope{}, ErrInvalidKey
+	}
+	ephemeral, err := GenerateKey()
+	if err != nil {
+		return Envelope{}, err
+	}
+	envelope := Envelope{
+		RecipientID:  to.ID,
+		RecipientKey: to.PublicKey.Bytes(),
+		EphemeralKey: ephemeral.PublicKey().Bytes(),
+	}
+	aead, err := wrapAEAD(ephemeral, to.PublicKey, envelope)
+	if err != nil {
+		return Envelope{}, err
+	}
+	// Every wrapping key is used once, so a zero nonce is safe
+	envelope.WrappedKey = aead.Seal(nil, make([]byte, aead.NonceSize()), contentKey, nil)
+	return envelope, nil
+}
+
+func unwrap(envelope Envelope, key *ecdh.PrivateKey) ([]byte, error) {
+	if err := envelope.Validate(); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(envelope.RecipientKey, key.PublicKey().Bytes()) {
+		return nil, fmt.Errorf("%w: envelope is for a different key", ErrDecrypt)
+	}
+	ephemeral, err := ecdh.X25519().NewPublicKey(envelope.EphemeralKey)
+	if err != nil {
+		return nil, ErrInvalidEnvelope
+	}
+	aead, err := wrapAEAD(key, ephemeral, envelope)
+	if err != nil {
+		return nil, err
+	}
+	contentKey, err := aead.Open(nil, make([]byte, aead.NonceSize()), envelope.WrappedKey, nil)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return contentKey, nil
+}
+
+// wrapAEAD derives the key-wrapping cipher from an X25519 shared secret,
+// bound to both public keys of the envelope.
+func wrapAEAD(private *ecdh.PrivateKey, public *ecdh.PublicKey, envelope Envelope) (cipher.AEAD, error) {
+	shared, err := private.ECDH(public)
+	if err != nil {
+		return nil, ErrInvalidKey
+	}
+	salt := append(append([]byte{}, envelope.EphemeralKey...), envelope.RecipientKey...)
+	wrapKey := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(wrapInfo)), wrapKey); err != nil {
+		return nil, err
+	}
+	return chacha20poly1305.New(wrapKey)
+}
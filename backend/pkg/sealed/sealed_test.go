@@ -0,0 +1,203 @@
+package sealed
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"errors"
+	"io"
+	"testing"
+)
+
+const bountyID = 42
+
+func generateKey(t *testing.T) *ecdh.PrivateKey {
+	t.Helper()
+	key, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// sealed is a submission sealed to a creator and a hunter.
+type sealed struct {
+	plaintext  []byte
+	ciphertext []byte
+	envelopes  []Envelope
+	keys       []*ecdh.PrivateKey
+}
+
+func seal(t *testing.T) *sealed {
+	t.Helper()
+	s := &sealed{
+		plaintext: []byte("the private write-up"),
+		keys:      []*ecdh.PrivateKey{generateKey(t), generateKey(t)},
+	}
+	var err error
+	s.ciphertext, s.envelopes, err = Seal(bountyID, s.plaintext,
+		Recipient{ID: "creator", PublicKey: s.keys[0].PublicKey()},
+		Recipient{ID: "hunter", PublicKey: s.keys[1].PublicKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSealOpenRoundTrip(t *testing.T) {
+	s := seal(t)
+
+	if len(s.ciphertext) != len(s.plaintext)+Overhead || s.ciphertext[0] != Version {
+		t.Fatalf("ciphertext is %d bytes with version %d", len(s.ciphertext), s.ciphertext[0])
+	}
+	if bytes.Contains(s.ciphertext, s.plaintext) {
+		t.Fatal("ciphertext contains the plaintext")
+	}
+	for i, envelope := range s.envelopes {
+		if err := envelope.Validate(); err != nil {
+			t.Fatalf("envelope %d: %v", i, err)
+		}
+		plaintext, err := Open(bountyID, s.ciphertext, envelope, s.keys[i])
+		if err != nil {
+			t.Fatalf("recipient %d: %v", i, err)
+		}
+		if !bytes.Equal(plaintext, s.plaintext) {
+			t.Fatalf("recipient %d read %q", i, plaintext)
+		}
+	}
+}
+
+func TestOpenWithWrongKey(t *testing.T) {
+	s := seal(t)
+	outsider := generateKey(t)
+
+	if _, err := Open(bountyID, s.ciphertext, s.envelopes[0], s.keys[1]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("other recipient's key: err = %v, want ErrDecrypt", err)
+	}
+	if _, err := Open(bountyID, s.ciphertext, s.envelopes[0], outsider); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("outsider's key: err = %v, want ErrDecrypt", err)
+	}
+
+	// Claiming the envelope is for the outsider does not let them unwrap it
+	envelope := s.envelopes[0]
+	envelope.RecipientKey = outsider.PublicKey().Bytes()
+	if _, err := Open(bountyID, s.ciphertext, envelope, outsider); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("relabelled envelope: err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestOpenRejectsOtherBounty(t *testing.T) {
+	s := seal(t)
+
+	if _, err := Open(bountyID+1, s.ciphertext, s.envelopes[0], s.keys[0]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestOpenRejectsModifiedCiphertext(t *testing.T) {
+	s := seal(t)
+
+	for _, i := range []int{1, len(s.ciphertext) / 2, len(s.ciphertext) - 1} {
+		modified := bytes.Clone(s.ciphertext)
+		modified[i] ^= 1
+		if _, err := Open(bountyID, modified, s.envelopes[0], s.keys[0]); !errors.Is(err, ErrDecrypt) {
+			t.Fatalf("byte %d flipped: err = %v, want ErrDecrypt", i, err)
+		}
+	}
+
+	modified := bytes.Clone(s.ciphertext)
+	modified[0] = Version + 1
+	if _, err := Open(bountyID, modified, s.envelopes[0], s.keys[0]); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("other version: err = %v, want ErrInvalidCiphertext", err)
+	}
+	if _, err := Open(bountyID, s.ciphertext[:Overhead-1], s.envelopes[0], s.keys[0]); !errors.Is(err, ErrInvalidCiphertext) {
+		t.Fatalf("truncated: err = %v, want ErrInvalidCiphertext", err)
+	}
+}
+
+func TestOpenRejectsModifiedEnvelope(t *testing.T) {
+	s := seal(t)
+
+	wrapped := s.envelopes[0]
+	wrapped.WrappedKey = bytes.Clone(wrapped.WrappedKey)
+	wrapped.WrappedKey[0] ^= 1
+	if _, err := Open(bountyID, s.ciphertext, wrapped, s.keys[0]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("modified wrapped key: err = %v, want ErrDecrypt", err)
+	}
+
+	ephemeral := s.envelopes[0]
+	ephemeral.EphemeralKey = generateKey(t).PublicKey().Bytes()
+	if _, err := Open(bountyID, s.ciphertext, ephemeral, s.keys[0]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("replaced ephemeral key: err = %v, want ErrDecrypt", err)
+	}
+
+	// Another recipient's wrapped key does not fit this envelope
+	swapped := s.envelopes[0]
+	swapped.WrappedKey = s.envelopes[1].WrappedKey
+	if _, err := Open(bountyID, s.ciphertext, swapped, s.keys[0]); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("swapped wrapped key: err = %v, want ErrDecrypt", err)
+	}
+
+	short := s.envelopes[0]
+	short.WrappedKey = short.WrappedKey[:WrappedKeySize-1]
+	if _, err := Open(bountyID, s.ciphertext, short, s.keys[0]); !errors.Is(err, ErrInvalidEnvelope) {
+		t.Fatalf("short wrapped key: err = %v, want ErrInvalidEnvelope", err)
+	}
+}
+
+func TestReshare(t *testing.T) {
+	s := seal(t)
+	arbiter := generateKey(t)
+
+	envelope, err := Reshare(s.envelopes[0], s.keys[0], Recipient{ID: "arbiter", PublicKey: arbiter.PublicKey()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := Open(bountyID, s.ciphertext, envelope, arbiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plaintext, s.plaintext) {
+		t.Fatalf("arbiter read %q", plaintext)
+	}
+
+	// Only the envelope's own recipient can reshare it
+	if _, err := Reshare(s.envelopes[0], arbiter, Recipient{ID: "arbiter", PublicKey: arbiter.PublicKey()}); !errors.Is(err, ErrDecrypt) {
+		t.Fatalf("reshare by outsider: err = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestPublicKeyEncoding(t *testing.T) {
+	key := generateKey(t).PublicKey()
+
+	parsed, err := ParsePublicKey(EncodePublicKey(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !parsed.Equal(key) {
+		t.Fatal("parsed key differs")
+	}
+	for _, invalid := range []string{"", "not base64!", "AAAA"} {
+		if _, err := ParsePublicKey(invalid); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("ParsePublicKey(%q): err = %v, want ErrInvalidKey", invalid, err)
+		}
+	}
+}
+
+func TestReadHeader(t *testing.T) {
+	s := seal(t)
+
+	r, err := ReadHeader(bytes.NewReader(s.ciphertext))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := io.ReadAll(r)
+	if err != nil || !bytes.Equal(read, s.ciphertext) {
+		t.Fatalf("read back %d bytes, %v", len(read), err)
+	}
+
+	for _, invalid := range [][]byte{nil, {Version + 1, 0, 0}} {
+		if _, err := ReadHeader(bytes.NewReader(invalid)); !errors.Is(err, ErrInvalidCiphertext) {
+			t.Fatalf("ReadHeader(%v): err = %v, want ErrInvalidCiphertext", invalid, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/bountyBoard/internal/database"
+	"github.com/bountyBoard/internal/repository"
+	"github.com/bountyBoard/internal/services"
+)
+
+const reputationUsage = `usage: main reputation <command>
+
+commands:
+  rebuild    recompute every score, level and badge from the reputation ledger`
+
+// runReputation implements the `reputation` subcommand.
+func runReputation(args []string) {
+	if len(args) != 1 || args[0] != "rebuild" {
+		fmt.Fprintln(os.Stderr, reputationUsage)
+		os.Exit(2)
+	}
+
+	service := services.NewReputationService(repository.NewGormStore(database.DB))
+	result, err := service.Rebuild(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("rebuilt %d reputations: %d changed, %d badges awarded\n", result.Users, result.Changed, result.BadgesAwarded)
+}
@@ -1,10 +1,10 @@
 // SPDX-License-Identifier: MIT
 pragma solidity ^0.8.20;
 
-import "@openzeppelin/contracts/token/ERC721/ERC721.sol";
+import "@openzeppelin/contracts/token/ERC721/extensions/ERC721URIStorage.sol";
 import "@openzeppelin/contracts/access/Ownable.sol";
 
-contract Reputation is ERC721, Ownable {
+contract Reputation is ERC721URIStorage, Ownable {
     mapping(address => uint256) public reputationScores;
     mapping(address => uint256) public completedTasks;
     
@@ -15,6 +15,8 @@ contract Reputation is ERC721, Ownable {
     uint256 public constant EXPERT_THRESHOLD = 20;
     uint256 public constant MASTER_THRESHOLD = 50;
 
+    event BadgeMinted(address indexed to, uint256 indexed tokenId, string tokenURI);
+
     constructor() ERC721("BountyBoardReputation", "BBR") Ownable(msg.sender) {}
 
     function updateReputation(address user, uint256 points) external onlyOwner {
@@ -40,6 +42,16 @@ contract Reputation is ERC721, Ownable {
         _safeMint(to, tokenId);
     }
 
+    // Mints a milestone badge awarded off-chain by the backend, with its
+    // metadata pinned to IPFS.
+    function mintBadge(address to, string calldata uri) external onlyOwner returns (uint256) {
+        uint256 tokenId = _nextTokenId++;
+        _safeMint(to, tokenId);
+        _setTokenURI(tokenId, uri);
+        emit BadgeMinted(to, tokenId, uri);
+        return tokenId;
+    }
+
     function getReputation(address user) external view returns (uint256) {
         return reputationScores[user];
     }
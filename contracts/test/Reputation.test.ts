@@ -0,0 +1,44 @@
+import { expect } from "chai";
+import { ethers } from "hardhat";
+import { Contract } from "ethers";
+
+describe("Reputation", function () {
+  let reputation: Contract;
+  let owner: any;
+  let hunter: any;
+
+  beforeEach(async function () {
+    [owner, hunter] = await ethers.getSigners();
+
+    const Reputation = await ethers.getContractFactory("Reputation");
+    reputation = await Reputation.deploy();
+    await reputation.waitForDeployment();
+  });
+
+  describe("Badge Minting", function () {
+    it("Should mint a badge with its metadata URI", async function () {
+      const uri = "ipfs://bafkreibadgemetadata";
+
+      await expect(reputation.mintBadge(await hunter.getAddress(), uri))
+        .to.emit(reputation, "BadgeMinted")
+        .withArgs(await hunter.getAddress(), 0, uri);
+
+      expect(await reputation.ownerOf(0)).to.equal(await hunter.getAddress());
+      expect(await reputation.tokenURI(0)).to.equal(uri);
+    });
+
+    it("Should assign increasing token IDs", async function () {
+      await reputation.mintBadge(await hunter.getAddress(), "ipfs://first");
+      await reputation.mintBadge(await hunter.getAddress(), "ipfs://second");
+
+      expect(await reputation.tokenURI(1)).to.equal("ipfs://second");
+      expect(await reputation.balanceOf(await hunter.getAddress())).to.equal(2);
+    });
+
+    it("Should only allow the owner to mint", async function () {
+      await expect(
+        reputation.connect(hunter).mintBadge(await hunter.getAddress(), "ipfs://metadata")
+      ).to.be.revertedWithCustomError(reputation, "OwnableUnauthorizedAccount");
+    });
+  });
+});
@@ -3,6 +3,9 @@ import { ethers } from 'ethers';
 import { BOUNTY_BOARD_ABI } from '@/lib/contracts/abis';
 
 const provider = new ethers.JsonRpcProvider("https://rpc.testnet.lens.dev");
+
+// Gateway used to read metadata documents referenced as ipfs://<cid>
+const IPFS_GATEWAY_URL = process.env.IPFS_GATEWAY_URL || 'https://gateway.pinata.cloud/ipfs/';
 const bountyBoardContract = new ethers.Contract(
   "0x86b202095aa1Db771c791B8bf60660B97B5dc8EA",
   BOUNTY_BOARD_ABI,
@@ -42,7 +45,7 @@ export async function GET(request: NextRequest) {
 
       // Fetch metadata from IPFS
       try {
-        const metadata = await fetch(bounty.metadata).then(res => res.json());
+        const metadata = await loadMetadata(bounty.metadata);
         bountyObj.title = metadata.title;
         bountyObj.deadline = metadata.deadline;
       } catch (error) {
@@ -65,3 +68,18 @@ function getBountyStatus(status: number): string {
   const statuses = ['open', 'claimed', 'disputed', 'completed'];
   return statuses[status] || 'unknown';
 }
+
+// loadMetadata reads a bounty's metadata string: an ipfs://<cid> reference
+// resolved through the gateway, or inline JSON written by older clients.
+async function loadMetadata(metadata: string) {
+  if (!metadata.startsWith('ipfs://')) {
+    return JSON.parse(metadata);
+  }
+  const cid = metadata.slice('ipfs://'.length);
+  const base = IPFS_GATEWAY_URL.endsWith('/') ? IPFS_GATEWAY_URL : `${IPFS_GATEWAY_URL}/`;
+  const res = await fetch(`${base}${cid}`);
+  if (!res.ok) {
+    throw new Error(`Gateway returned ${res.status} for ${cid}`);
+  }
+  return res.json();
+}
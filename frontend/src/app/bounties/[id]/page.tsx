@@ -157,7 +157,7 @@ function BountyDetailClient({ bountyId }: { bountyId: string }) {
       }
 
       const data = await response.json()
-      setSubmissions(data)
+      setSubmissions(data.items)
     } catch (error) {
       console.error('Error fetching submissions:', error)
     }
@@ -181,7 +181,7 @@ function BountyDetailClient({ bountyId }: { bountyId: string }) {
       }
 
       const data = await response.json()
-      setComments(data)
+      setComments(data.items)
     } catch (error) {
       console.error('Error fetching comments:', error)
     }
@@ -128,13 +128,31 @@ export default function CreateBounty() {
     }
 
     try {
-      // First, approve the BountyBoard contract to spend tokens
       const reward = parseEther(formData.reward);
-      const metadata = JSON.stringify({
-        title: formData.title,
-        description: formData.description,
-        deadline: formData.deadline,
+      const deadline = new Date(formData.deadline).toISOString();
+
+      // Pin the metadata document first so the contract can reference it
+      const metadataResponse = await fetch(buildApiUrl(`api/v1/bounties/metadata`), {
+        method: "POST",
+        headers: {
+          "Content-Type": "application/json",
+          Authorization: authHeader,
+        },
+        body: JSON.stringify({
+          title: formData.title,
+          description: formData.description,
+          reward: formData.reward,
+          deadline,
+        }),
       });
+      if (!metadataResponse.ok) {
+        const error = await metadataResponse.json();
+        throw new Error(error.error || "Failed to pin bounty metadata");
+      }
+      // uri is ipfs://<cid>, the metadata string stored on-chain
+      const { cid, uri: metadata } = await metadataResponse.json();
+
+      // Then approve the BountyBoard contract to spend tokens
       console.log("Approving tokens...");
       const approvalHash = await writeContractAsync({
         address: GRASS_TOKEN_ADDRESS,
@@ -189,8 +207,9 @@ export default function CreateBounty() {
         title: formData.title,
         description: formData.description,
         reward: formData.reward,
-        deadline: new Date(formData.deadline).toISOString(),
+        deadline,
         txHash: hash,
+        metadata_cid: cid,
       };
       console.log("Sending payload to backend:", payload);
 
@@ -39,7 +39,7 @@ export default function BountiesPage() {
           throw new Error(error.error || 'Failed to fetch bounties')
         }
         const data = await response.json()
-        setBounties(data)
+        setBounties(data.items)
         setLoading(false)
       } catch (error) {
         console.error('Error fetching bounties:', error)
@@ -21,16 +21,12 @@ export default function Home() {
     const fetchBounties = async () => {
       try {
         // Fetch all bounties without status filter
-        const response = await fetch(buildApiUrl('api/v1/bounties'))
+        const response = await fetch(buildApiUrl('api/v1/bounties?sort=created_at&order=desc&limit=3'))
         if (!response.ok) {
           throw new Error('Failed to fetch bounties')
         }
         const data = await response.json()
-        // Sort by newest first and take first 3
-        const sortedBounties = data
-          .sort((a: any, b: any) => new Date(b.createdAt).getTime() - new Date(a.createdAt).getTime())
-          .slice(0, 3)
-        setBounties(sortedBounties)
+        setBounties(data.items)
       } catch (error) {
         console.error('Error fetching bounties:', error)
       } finally {
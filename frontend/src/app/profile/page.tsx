@@ -82,18 +82,29 @@ export default function Profile() {
           address ? [`0x${address.toLowerCase()}`] : undefined
   })
 
+  // Dispute resolution is open to the arbiter and admin roles, which admins
+  // grant through the role API, so ask the backend who holds them
   useEffect(() => {
-    const checkIfAdmin = () => {
-      if (address) {
-        const adminAddresses = [
-          '0x15b5BDf7a5e0305B9a4bE413383C9b1500C8FCF2',
-          '0x15b5BDf7a5e0305B9a4bE413383C9b1500C8FCF2'.toLowerCase(),
-        ];
-        setIsAdmin(adminAddresses.includes(address) || adminAddresses.includes(address.toLowerCase()));
+    const checkIfAdmin = async () => {
+      const authHeader = getAuthHeader();
+      if (!address || !authHeader) {
+        setIsAdmin(false);
+        return;
+      }
+      try {
+        const response = await fetch(buildApiUrl('api/v1/auth/me'), {
+          headers: { 'Authorization': authHeader }
+        });
+        if (!response.ok) throw new Error(`Failed to fetch roles: ${response.statusText}`);
+        const { roles } = await response.json() as { roles: string[] };
+        setIsAdmin(roles.includes('admin') || roles.includes('arbiter'));
+      } catch (error) {
+        console.error('Error fetching roles:', error);
+        setIsAdmin(false);
       }
     }
     checkIfAdmin()
-  }, [address])
+  }, [address, getAuthHeader])
 
   useEffect(() => {
     const fetchBounties = async () => {
@@ -116,17 +127,19 @@ export default function Profile() {
         };
 
         const [createdResponse, claimedResponse] = await Promise.all([
-          fetch(buildApiUrl(`api/v1/bounties?creator=${formattedUserId}`), { headers }),
-          fetch(buildApiUrl(`api/v1/bounties?hunter=${formattedUserId}`), { headers })
+          fetch(buildApiUrl(`api/v1/bounties?creator=${formattedUserId}&limit=100`), { headers }),
+          fetch(buildApiUrl(`api/v1/bounties?hunter=${formattedUserId}&limit=100`), { headers })
         ]);
 
         if (!createdResponse.ok) throw new Error(`Failed to fetch created bounties: ${createdResponse.statusText}`);
         if (!claimedResponse.ok) throw new Error(`Failed to fetch claimed bounties: ${claimedResponse.statusText}`);
 
-        const [createdData, claimedData] = await Promise.all([
+        const [createdPage, claimedPage] = await Promise.all([
           createdResponse.json(),
           claimedResponse.json()
         ]);
+        const createdData = createdPage.items;
+        const claimedData = claimedPage.items;
 
         setCreatedBounties(createdData);
         setClaimedBounties(claimedData);
@@ -139,7 +152,7 @@ export default function Profile() {
           );
           if (!disputedResponse.ok) throw new Error(`Failed to fetch disputed bounties: ${disputedResponse.statusText}`);
           const disputedData = await disputedResponse.json();
-          setDisputedBounties(disputedData);
+          setDisputedBounties(disputedData.items);
         }
 
         // Generate actionable items
@@ -186,7 +199,7 @@ export default function Profile() {
       }
 
       const data = await response.json();
-      setDisputedBounties(data);
+      setDisputedBounties(data.items);
     } catch (error) {
       console.error('Error fetching disputed bounties:', error);
     } finally {
@@ -1,32 +1,167 @@
-import { useAccount, useConnect, useDisconnect } from 'wagmi';
+import { useAccount, useChainId, useConnect, useDisconnect, useSignMessage } from 'wagmi';
 import { injected } from '@wagmi/core';
-import { useState, useCallback } from 'react';
+import { getAddress } from 'viem';
+import { useState, useCallback, useEffect } from 'react';
+import { buildApiUrl } from '@/constants/api';
+
+interface TokenPair {
+  access_token: string;
+  access_token_expires_at: string;
+  refresh_token: string;
+  refresh_token_expires_at: string;
+}
+
+const SESSION_KEY = 'bountyboard.session';
+
+// Refresh the access token this long before it expires
+const REFRESH_MARGIN_MS = 60 * 1000;
+
+interface StoredSession {
+  address: string;
+  tokens: TokenPair;
+}
+
+const loadSession = (address?: string): StoredSession | null => {
+  if (!address || typeof window === 'undefined') return null;
+  try {
+    const raw = window.localStorage.getItem(SESSION_KEY);
+    if (!raw) return null;
+    const session: StoredSession = JSON.parse(raw);
+    // Sessions belong to one wallet; switching accounts needs a new sign-in
+    if (session.address !== address.toLowerCase()) return null;
+    if (new Date(session.tokens.refresh_token_expires_at).getTime() <= Date.now()) return null;
+    return session;
+  } catch {
+    return null;
+  }
+};
+
+const saveSession = (session: StoredSession | null) => {
+  if (typeof window === 'undefined') return;
+  if (session) {
+    window.localStorage.setItem(SESSION_KEY, JSON.stringify(session));
+  } else {
+    window.localStorage.removeItem(SESSION_KEY);
+  }
+};
+
+// buildSiweMessage formats an EIP-4361 message for the backend to verify.
+// The domain and URI are this site's, so SIWE_DOMAIN must match its host.
+const buildSiweMessage = (address: string, chainId: number, nonce: string, expiresAt: string) => {
+  return [
+    `${window.location.host} wants you to sign in with your Ethereum account:`,
+    getAddress(address),
+    '',
+    'Sign in to BountyBoard.',
+    '',
+    `URI: ${window.location.origin}`,
+    'Version: 1',
+    `Chain ID: ${chainId}`,
+    `Nonce: ${nonce}`,
+    `Issued At: ${new Date().toISOString()}`,
+    `Expiration Time: ${expiresAt}`,
+  ].join('\n');
+};
 
 export const useWalletAuth = () => {
   const { isConnected, address } = useAccount();
+  const chainId = useChainId();
   const { connectAsync } = useConnect();
   const { disconnectAsync } = useDisconnect();
+  const { signMessageAsync } = useSignMessage();
   const [isLoading, setIsLoading] = useState(false);
+  const [session, setSession] = useState<StoredSession | null>(null);
+
+  useEffect(() => {
+    setSession(loadSession(address));
+  }, [address]);
+
+  const storeTokens = useCallback((owner: string, tokens: TokenPair | null) => {
+    const next = tokens ? { address: owner.toLowerCase(), tokens } : null;
+    saveSession(next);
+    setSession(next);
+  }, []);
+
+  // authenticate proves ownership of the wallet with Sign-In with Ethereum and
+  // stores the session tokens the backend issues for it
+  const authenticate = useCallback(async (account: string) => {
+    const nonceResponse = await fetch(buildApiUrl('api/v1/auth/nonce'));
+    if (!nonceResponse.ok) throw new Error('Failed to get sign-in nonce');
+    const { nonce, expires_at } = await nonceResponse.json();
+
+    const message = buildSiweMessage(account, chainId, nonce, expires_at);
+    const signature = await signMessageAsync({ account: account as `0x${string}`, message });
+
+    const verifyResponse = await fetch(buildApiUrl('api/v1/auth/verify'), {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ message, signature }),
+    });
+    if (!verifyResponse.ok) {
+      const { error } = await verifyResponse.json().catch(() => ({ error: undefined }));
+      throw new Error(error || 'Sign-in failed');
+    }
+    const { tokens } = await verifyResponse.json();
+    storeTokens(account, tokens);
+  }, [chainId, signMessageAsync, storeTokens]);
+
+  const refresh = useCallback(async (current: StoredSession) => {
+    const response = await fetch(buildApiUrl('api/v1/auth/refresh'), {
+      method: 'POST',
+      headers: { 'Content-Type': 'application/json' },
+      body: JSON.stringify({ refresh_token: current.tokens.refresh_token }),
+    });
+    if (!response.ok) {
+      // The refresh token was revoked or already used; sign in again
+      storeTokens(current.address, null);
+      return;
+    }
+    const { tokens } = await response.json();
+    storeTokens(current.address, tokens);
+  }, [storeTokens]);
+
+  // Keep the access token fresh while the session lasts
+  useEffect(() => {
+    if (!session) return;
+    const expiresAt = new Date(session.tokens.access_token_expires_at).getTime();
+    const delay = Math.max(expiresAt - Date.now() - REFRESH_MARGIN_MS, 0);
+    const timer = setTimeout(() => {
+      refresh(session).catch((error) => console.error('Failed to refresh session:', error));
+    }, delay);
+    return () => clearTimeout(timer);
+  }, [session, refresh]);
 
   const signIn = async () => {
-    if (!isConnected) {
-      try {
-        setIsLoading(true);
+    try {
+      setIsLoading(true);
+      let account = address;
+      if (!isConnected) {
         const result = await connectAsync({ connector: injected() });
-        if (!result?.accounts[0]) throw new Error('No accounts found');
-        return result.accounts[0];
-      } catch (error: any) {
-        throw new Error('Error connecting wallet');
-      } finally {
-        setIsLoading(false);
+        account = result?.accounts[0];
+        if (!account) throw new Error('No accounts found');
+      }
+      if (!loadSession(account)) {
+        await authenticate(account!);
       }
+      return account;
+    } catch (error: any) {
+      console.error('Sign-in error:', error);
+      throw new Error(error?.message || 'Error connecting wallet');
+    } finally {
+      setIsLoading(false);
     }
-    return address;
   };
 
   const signOut = async () => {
     try {
       setIsLoading(true);
+      if (session) {
+        await fetch(buildApiUrl('api/v1/auth/logout'), {
+          method: 'POST',
+          headers: { Authorization: `Bearer ${session.tokens.access_token}` },
+        }).catch(() => undefined);
+        storeTokens(session.address, null);
+      }
       await disconnectAsync();
     } catch (error: any) {
       throw new Error('Error disconnecting wallet');
@@ -36,18 +171,15 @@ export const useWalletAuth = () => {
   };
 
   const getAuthHeader = useCallback(() => {
-    if (!address) return null;
-    // Ensure wallet address is lowercase and has 0x prefix
-    const formattedAddress = address.toLowerCase();
-    // Always include 0x prefix
-    const finalAddress = formattedAddress.startsWith('0x') ? formattedAddress : `0x${formattedAddress}`;
-    return `Bearer ${finalAddress}`;
-  }, [address]);
+    if (!address || !session) return null;
+    return `Bearer ${session.tokens.access_token}`;
+  }, [address, session]);
 
   return {
     signIn,
     signOut,
     isConnected,
+    isAuthenticated: !!session,
     address,
     isLoading,
     getAuthHeader,